@@ -14,8 +14,9 @@ require (
 	github.com/stretchr/testify v1.8.0
 	go.uber.org/atomic v1.9.0
 	go.uber.org/zap v1.23.0
+	golang.org/x/net v0.0.0-20220520000938-2e3eb7b945c2
 	google.golang.org/protobuf v1.28.1
-	gopkg.in/telebot.v3 v3.1.0
+	gopkg.in/telebot.v3 v3.2.1
 	gorm.io/driver/mysql v1.3.6
 	gorm.io/gorm v1.25.7-0.20240204074919-46816ad31dde
 )
@@ -48,7 +49,6 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/subosito/gotenv v1.4.1 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
-	golang.org/x/net v0.0.0-20220520000938-2e3eb7b945c2 // indirect
 	golang.org/x/sys v0.7.0 // indirect
 	golang.org/x/text v0.3.7 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
@@ -0,0 +1,65 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version of the backup format, bumped on incompatible changes
+const Version = 1
+
+// Backup the subscriptions and settings of a chat
+type Backup struct {
+	Version       int            `json:"version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	ChatID        int64          `json:"chat_id"` // chat the backup was made of, informational
+	Preference    *Preference    `json:"preference,omitempty"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Preference the settings of a chat
+type Preference struct {
+	EnableActionButton int    `json:"enable_action_button"`
+	Language           string `json:"language,omitempty"`
+}
+
+// Subscription a subscription with all its settings, the source is identified by its url
+type Subscription struct {
+	URL                string   `json:"url"`
+	SourceTitle        string   `json:"source_title,omitempty"`
+	Title              string   `json:"title,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+	EnableNotification int      `json:"enable_notification"`
+	EnableTelegraph    int      `json:"enable_telegraph"`
+	EnableEditUpdate   int      `json:"enable_edit_update"`
+	ThreadID           int      `json:"thread_id,omitempty"`
+	Interval           int      `json:"interval"`
+	PreviewLength      int      `json:"preview_length,omitempty"`
+	WebPagePreview     int      `json:"web_page_preview,omitempty"`
+	WaitTime           int      `json:"wait_time,omitempty"`
+	Paused             bool     `json:"paused,omitempty"` // updates of the source are paused
+}
+
+// New creates an empty backup of a chat in the current version
+func New(chatID int64) *Backup {
+	return &Backup{Version: Version, CreatedAt: time.Now(), ChatID: chatID}
+}
+
+// Read parses a backup, rejecting versions newer than this bot understands
+func Read(r io.Reader) (*Backup, error) {
+	var b Backup
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("parse backup failed, %w", err)
+	}
+	if b.Version < 1 || b.Version > Version {
+		return nil, fmt.Errorf("unsupported backup version %d", b.Version)
+	}
+	return &b, nil
+}
+
+// JSON dumps the backup as indented JSON
+func (b *Backup) JSON() ([]byte, error) {
+	return json.MarshalIndent(b, "", "  ")
+}
@@ -0,0 +1,30 @@
+package backup
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackup(t *testing.T) {
+	b := New(-100)
+	b.Preference = &Preference{EnableActionButton: 1, Language: "ja"}
+	b.Subscriptions = []Subscription{
+		{URL: "https://example.com/feed.xml", Tags: []string{"news"}, EnableNotification: 1, Interval: 10, Paused: true},
+	}
+
+	data, err := b.JSON()
+	assert.Nil(t, err)
+	got, err := Read(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, b.Subscriptions, got.Subscriptions)
+	assert.Equal(t, b.Preference, got.Preference)
+	assert.Equal(t, int64(-100), got.ChatID)
+
+	_, err = Read(strings.NewReader(`{"version": 2, "subscriptions": []}`))
+	assert.Error(t, err)
+	_, err = Read(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
@@ -2,33 +2,46 @@ package bot
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 	tb "gopkg.in/telebot.v3"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/handler"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/middleware"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/preview"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
 	"github.com/andatoshiki/toshiki-rssbot/internal/config"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
 	"github.com/andatoshiki/toshiki-rssbot/internal/log"
 	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 )
 
+// buttonTTL menus older than it are expired, the buttons of the pushed contents never expire
+const buttonTTL = 48 * time.Hour
+
 type Bot struct {
-	core *core.Core
-	tb   *tb.Bot // telebot.Bot instance
+	core    *core.Core
+	tb      *tb.Bot // telebot.Bot instance
+	running atomic.Bool
 }
 
 func NewBot(core *core.Core) *Bot {
 	log.Infof("init telegram bot, token %s, endpoint %s", config.BotToken, config.TelegramEndpoint)
+	session.SetSecret(config.BotToken)
+	poller, err := newPoller(core.HttpClient().Client())
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
 	settings := tb.Settings{
 		URL:    config.TelegramEndpoint,
 		Token:  config.BotToken,
-		Poller: &tb.LongPoller{Timeout: 10 * time.Second},
+		Poller: poller,
 		Client: core.HttpClient().Client(),
 	}
 
@@ -41,30 +54,51 @@ func NewBot(core *core.Core) *Bot {
 		core: core,
 	}
 
-	var err error
 	b.tb, err = tb.NewBot(settings)
 	if err != nil {
 		log.Error(err)
 		return nil
 	}
-	b.tb.Use(middleware.UserFilter(), middleware.PreLoadMentionChat(), middleware.IsChatAdmin())
+	b.tb.Use(
+		middleware.GroupTextFilter(), middleware.UserFilter(core), middleware.PreLoadMentionChat(), middleware.Language(core), middleware.IsChatAdmin(),
+	)
 	return b
 }
 
 func (b *Bot) registerCommands(appCore *core.Core) error {
+	importReports := handler.NewImportReports()
 	commandHandlers := []handler.CommandHandler{
-		handler.NewStart(),
+		handler.NewStart(appCore),
 		handler.NewPing(b.tb),
 		handler.NewAddSubscription(appCore),
+		handler.NewPreview(appCore),
 		handler.NewRemoveSubscription(b.tb, appCore),
 		handler.NewListSubscription(appCore),
 		handler.NewRemoveAllSubscription(),
-		handler.NewOnDocument(b.tb, appCore),
+		handler.NewOnDocument(b.tb, appCore, importReports),
+		handler.NewOnMigration(b.tb, appCore),
+		handler.NewOnMyChatMember(b.tb, appCore),
+		handler.NewOnText(b.tb, appCore),
 		handler.NewSet(b.tb, appCore),
 		handler.NewSetFeedTag(appCore),
 		handler.NewSetUpdateInterval(appCore),
+		handler.NewSetTopic(appCore),
+		handler.NewTags(appCore),
+		handler.NewRenameTag(appCore),
+		handler.NewMergeTag(appCore),
+		handler.NewDeleteTag(appCore),
+		handler.NewStats(appCore),
+		handler.NewAdmin(b.tb, appCore),
+		handler.NewActionButtons(appCore),
+		handler.NewLang(appCore),
+		handler.NewSearch(appCore),
+		handler.NewInlineQuery(appCore),
+		handler.NewSaved(appCore),
+		handler.NewUnsave(appCore),
 		handler.NewExport(appCore),
 		handler.NewImport(),
+		handler.NewBackup(appCore),
+		handler.NewRestore(appCore),
 		handler.NewPauseAll(appCore),
 		handler.NewActiveAll(appCore),
 		handler.NewHelp(),
@@ -80,26 +114,76 @@ func (b *Bot) registerCommands(appCore *core.Core) error {
 		handler.NewCancelRemoveAllSubscriptionButton(),
 		handler.NewSetFeedItemButton(b.tb, appCore),
 		handler.NewRemoveSubscriptionItemButton(appCore),
+		handler.NewUndoUnsubscribeButton(appCore),
 		handler.NewNotificationSwitchButton(b.tb, appCore),
 		handler.NewSetSubscriptionTagButton(b.tb),
 		handler.NewTelegraphSwitchButton(b.tb, appCore),
 		handler.NewSubscriptionSwitchButton(b.tb, appCore),
+		handler.NewEditUpdateSwitchButton(b.tb, appCore),
+		handler.NewSetFeedPageButton(b.tb, appCore),
+		handler.NewSetFeedBackButton(b.tb, appCore),
+		handler.NewSetIntervalButton(b.tb, appCore),
+		handler.NewSetPreviewLengthButton(b.tb, appCore),
+		handler.NewSetWebPagePreviewButton(b.tb, appCore),
+		handler.NewContentMuteButton(appCore),
+		handler.NewContentPauseButton(appCore),
+		handler.NewContentUnsubButton(appCore),
+		handler.NewContentSaveButton(appCore),
+		handler.NewSavedPageButton(appCore),
+		handler.NewSearchPageButton(appCore),
+		handler.NewListSubscriptionPageButton(b.tb, appCore),
+		handler.NewSetLanguageButton(appCore),
+		handler.NewPreviewSubscribeButton(appCore),
+		handler.NewFeedLinkChannelButton(appCore),
+		handler.NewFeedLinkPreviewButton(appCore),
+		handler.NewImportRetryButton(appCore, importReports),
+		handler.NewRestoreButton(appCore),
 	}
 
 	for _, h := range ButtonHandlers {
-		b.tb.Handle(h, h.Handle, h.Middlewares()...)
+		ttl := buttonTTL
+		if persistentHandler, ok := h.(handler.PersistentButtonHandler); ok && persistentHandler.Persistent() {
+			ttl = 0
+		}
+		middlewares := append([]tb.MiddlewareFunc{middleware.VerifyCallback(ttl)}, h.Middlewares()...)
+		b.tb.Handle(h, h.Handle, middlewares...)
 	}
 
-	var commands []tb.Command
-	for _, h := range commandHandlers {
-		if h.Description() == "" {
-			continue
+	// the default command list is in the default language, other languages are set for users of that language
+	for _, lang := range i18n.Languages {
+		var commands, adminCommands []tb.Command
+		for _, h := range commandHandlers {
+			if h.Description() == "" {
+				continue
+			}
+			description, ok := i18n.Lookup(lang, "command."+strings.TrimPrefix(h.Command(), "/"))
+			if !ok {
+				description = h.Description()
+			}
+			command := tb.Command{Text: h.Command(), Description: description}
+			adminCommands = append(adminCommands, command)
+			if adminHandler, ok := h.(handler.AdminCommandHandler); ok && adminHandler.AdminOnly() {
+				continue
+			}
+			commands = append(commands, command)
+		}
+
+		var langOpts []interface{}
+		if lang != i18n.Default {
+			langOpts = append(langOpts, lang)
+		}
+		log.Debugf("set bot command %s %+v", lang, commands)
+		if err := b.tb.SetCommands(append([]interface{}{commands}, langOpts...)...); err != nil {
+			return err
+		}
+
+		// admin commands are only listed in the private chats of the admins
+		for _, adminID := range config.AdminUsers {
+			scope := tb.CommandScope{Type: tb.CommandScopeChat, ChatID: adminID}
+			if err := b.tb.SetCommands(append([]interface{}{adminCommands, scope}, langOpts...)...); err != nil {
+				log.Errorf("set admin %d commands failed, %v", adminID, err)
+			}
 		}
-		commands = append(commands, tb.Command{Text: h.Command(), Description: h.Description()})
-	}
-	log.Debugf("set bot command %+v", commands)
-	if err := b.tb.SetCommands(commands); err != nil {
-		return err
 	}
 	return nil
 }
@@ -112,17 +196,34 @@ func (b *Bot) Run() error {
 	if err := b.registerCommands(b.core); err != nil {
 		return err
 	}
+	if err := setupUpdates(b.tb); err != nil {
+		return err
+	}
 	log.Infof("bot start %s", config.AppVersionInfo())
+	b.running.Store(true)
 	b.tb.Start()
 	return nil
 }
 
+// Stop stops receiving updates, the webhook server finishes the requests in flight before it is closed
+func (b *Bot) Stop() {
+	if b.running.CompareAndSwap(true, false) {
+		b.tb.Stop()
+	}
+}
+
 func (b *Bot) SourceUpdate(
 	source *model.Source, newContents []*model.Content, subscribes []*model.Subscribe,
 ) {
 	b.BroadcastNews(source, subscribes, newContents)
 }
 
+func (b *Bot) SourceContentUpdate(
+	source *model.Source, updatedContents []*model.Content, subscribes []*model.Subscribe,
+) {
+	b.BroadcastContentUpdate(source, subscribes, updatedContents)
+}
+
 func (b *Bot) SourceUpdateError(source *model.Source) {
 	b.BroadcastSourceError(source)
 }
@@ -137,15 +238,14 @@ func (b *Bot) BroadcastNews(source *model.Source, subs []*model.Subscribe, conte
 		"new contents", len(contents),
 	)
 
+	preferences := b.chatPreferences(subs)
 	for _, content := range contents {
-		previewText := preview.TrimDescription(content.Description, config.PreviewText)
-
 		for _, sub := range subs {
 			tpldata := &config.TplData{
-				SourceTitle:     source.Title,
+				SourceTitle:     sub.DisplayTitle(source),
 				ContentTitle:    content.Title,
 				RawLink:         content.RawLink,
-				PreviewText:     previewText,
+				PreviewText:     preview.TrimDescription(content.Description, preview.Length(sub)),
 				TelegraphURL:    content.TelegraphURL,
 				Tags:            sub.Tag,
 				EnableTelegraph: sub.EnableTelegraph == 1 && content.TelegraphURL != "",
@@ -155,9 +255,11 @@ func (b *Bot) BroadcastNews(source *model.Source, subs []*model.Subscribe, conte
 				ID: sub.UserID,
 			}
 			o := &tb.SendOptions{
-				DisableWebPagePreview: config.DisableWebPagePreview,
+				DisableWebPagePreview: preview.DisableWebPage(sub),
 				ParseMode:             config.MessageMode,
 				DisableNotification:   sub.EnableNotification != 1,
+				ThreadID:              sub.ThreadID,
+				ReplyMarkup:           contentActionMarkup(preferences[sub.UserID], sub, source, content),
 			}
 			msg, err := tpldata.Render(config.MessageMode)
 			if err != nil {
@@ -167,7 +269,24 @@ func (b *Bot) BroadcastNews(source *model.Source, subs []*model.Subscribe, conte
 				)
 				return
 			}
-			if _, err := b.tb.Send(u, msg, o); err != nil {
+			m, err := b.tb.Send(u, msg, o)
+			if err != nil && sub.ThreadID != 0 && isTopicMissingError(err) {
+				// the forum topic was deleted, fall back to the general topic
+				zap.S().Warnw(
+					"broadcast news error, forum topic not found",
+					"error", err.Error(),
+					"user id", sub.UserID,
+					"source id", sub.SourceID,
+					"thread id", sub.ThreadID,
+				)
+				if err := b.core.SetSubscriptionThread(context.Background(), sub.UserID, sub.SourceID, 0); err != nil {
+					log.Errorf("reset subscription thread failed, %v", err)
+				}
+				sub.ThreadID = 0
+				o.ThreadID = 0
+				m, err = b.tb.Send(u, msg, o)
+			}
+			if err != nil {
 
 				if strings.Contains(err.Error(), "Forbidden") {
 					zap.S().Errorw(
@@ -179,6 +298,9 @@ func (b *Bot) BroadcastNews(source *model.Source, subs []*model.Subscribe, conte
 						"link", source.Link,
 					)
 					b.core.Unsubscribe(context.Background(), sub.UserID, sub.SourceID)
+					if err := b.core.ChatLeft(context.Background(), sub.UserID); err != nil {
+						log.Errorf("record chat %d left failed, %v", sub.UserID, err)
+					}
 				}
 
 				/*
@@ -193,6 +315,110 @@ func (b *Bot) BroadcastNews(source *model.Source, subs []*model.Subscribe, conte
 						"error", err.Error(),
 					)
 				}
+				continue
+			}
+
+			if err := b.core.AddContentMessage(context.Background(), content, sub.UserID, m.ID); err != nil {
+				log.Errorf("save message of content %s failed, %v", content.HashID, err)
+			}
+		}
+	}
+}
+
+// chatPreferences gets the preferences of the subscriber chats, chats failed to load are left out
+func (b *Bot) chatPreferences(subs []*model.Subscribe) map[int64]*model.ChatPreference {
+	preferences := make(map[int64]*model.ChatPreference)
+	for _, sub := range subs {
+		preference, err := b.core.GetChatPreference(context.Background(), sub.UserID)
+		if err != nil {
+			log.Errorf("get chat %d preference failed, %v", sub.UserID, err)
+			continue
+		}
+		preferences[sub.UserID] = preference
+	}
+	return preferences
+}
+
+// contentActionMarkup generates the action buttons of a pushed content if the chat enabled them
+func contentActionMarkup(
+	preference *model.ChatPreference, sub *model.Subscribe, source *model.Source, content *model.Content,
+) *tb.ReplyMarkup {
+	if preference == nil || preference.EnableActionButton != 1 {
+		return nil
+	}
+	return handler.ContentActionMarkup(preference.Language, sub, source, session.ContentHash(content.HashID))
+}
+
+func isTopicMissingError(err error) bool {
+	return strings.Contains(err.Error(), "thread not found") || strings.Contains(err.Error(), "TOPIC_DELETED")
+}
+
+// BroadcastContentUpdate edit the delivered messages of contents that changed upstream
+func (b *Bot) BroadcastContentUpdate(source *model.Source, subs []*model.Subscribe, contents []*model.Content) {
+	editSubs := make(map[int64]*model.Subscribe)
+	for _, sub := range subs {
+		if sub.EnableEditUpdate == 1 {
+			editSubs[sub.UserID] = sub
+		}
+	}
+	if len(editSubs) == 0 {
+		return
+	}
+
+	zap.S().Infow(
+		"broadcast content update",
+		"fetcher id", source.ID,
+		"fetcher title", source.Title,
+		"subscriber count", len(editSubs),
+		"updated contents", len(contents),
+	)
+
+	preferences := b.chatPreferences(subs)
+	for _, content := range contents {
+		messages, err := b.core.GetContentMessages(context.Background(), content.HashID)
+		if err != nil {
+			log.Errorf("get messages of content %s failed, %v", content.HashID, err)
+			continue
+		}
+
+		for _, m := range messages {
+			sub, ok := editSubs[m.ChatID]
+			if !ok {
+				continue
+			}
+
+			tpldata := &config.TplData{
+				SourceTitle:     sub.DisplayTitle(source),
+				ContentTitle:    content.Title,
+				RawLink:         content.RawLink,
+				PreviewText:     preview.TrimDescription(content.Description, preview.Length(sub)),
+				TelegraphURL:    content.TelegraphURL,
+				Tags:            sub.Tag,
+				EnableTelegraph: sub.EnableTelegraph == 1 && content.TelegraphURL != "",
+			}
+			msg, err := tpldata.Render(config.MessageMode)
+			if err != nil {
+				zap.S().Errorw(
+					"broadcast content update error, tpldata.Render err",
+					"error", err.Error(),
+				)
+				return
+			}
+
+			stored := &tb.StoredMessage{MessageID: strconv.Itoa(m.MessageID), ChatID: m.ChatID}
+			o := &tb.SendOptions{
+				DisableWebPagePreview: preview.DisableWebPage(sub),
+				ParseMode:             config.MessageMode,
+				ReplyMarkup:           contentActionMarkup(preferences[sub.UserID], sub, source, content),
+			}
+			if _, err := b.tb.Edit(stored, msg, o); err != nil {
+				zap.S().Errorw(
+					"broadcast content update error, edit message failed",
+					"error", err.Error(),
+					"chat id", m.ChatID,
+					"message id", m.MessageID,
+					"hash id", content.HashID,
+				)
 			}
 		}
 	}
@@ -204,15 +430,19 @@ func (b *Bot) BroadcastSourceError(source *model.Source) {
 	if err != nil {
 		log.Errorf("get subscriptions failed, %v", err)
 	}
+	preferences := b.chatPreferences(subs)
 	var u tb.User
 	for _, sub := range subs {
-		message := fmt.Sprintf(
-			"[%s](%s) has failed to update for %d consecutive times, update has paused", source.Title, source.Link, config.ErrorThreshold,
-		)
+		lang := i18n.Default
+		if preference := preferences[sub.UserID]; preference != nil && preference.Language != "" {
+			lang = preference.Language
+		}
+		message := i18n.T(lang, "source.update_paused", source.Title, source.Link, config.ErrorThreshold)
 		u.ID = sub.UserID
 		_, _ = b.tb.Send(
 			&u, message, &tb.SendOptions{
 				ParseMode: tb.ModeMarkdown,
+				ThreadID:  sub.ThreadID,
 			},
 		)
 	}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+	"github.com/andatoshiki/toshiki-rssbot/internal/storage"
+	"github.com/andatoshiki/toshiki-rssbot/internal/storage/mock"
+)
+
+func TestBot_BroadcastNews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	subscriptionStorage := mock.NewMockSubscription(ctrl)
+	messageStorage := mock.NewMockMessage(ctrl)
+	preferenceStorage := mock.NewMockPreference(ctrl)
+	appCore := core.NewCore(
+		nil, nil, nil, subscriptionStorage, messageStorage, preferenceStorage, nil, nil, nil, nil, nil, nil,
+	)
+
+	t.Run(
+		"deleted topic falls back to the general topic", func(t *testing.T) {
+			api := newFakeBotAPI(t, "")
+			b := &Bot{core: appCore, tb: newTestBot(t, api, nil)}
+
+			chatID := int64(-1001)
+			source := &model.Source{ID: 1, Title: "source", Link: "https://example.com/feed"}
+			sub := &model.Subscribe{UserID: chatID, SourceID: source.ID, ThreadID: 42}
+			content := &model.Content{SourceID: source.ID, HashID: "hash", Title: "title", RawLink: "https://example.com/1"}
+
+			preferenceStorage.EXPECT().GetPreference(gomock.Any(), chatID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			subscriptionStorage.EXPECT().GetSubscription(gomock.Any(), chatID, source.ID).Return(
+				&model.Subscribe{UserID: chatID, SourceID: source.ID, ThreadID: 42}, nil,
+			).Times(1)
+			subscriptionStorage.EXPECT().UpsertSubscription(
+				gomock.Any(), chatID, source.ID, &model.Subscribe{UserID: chatID, SourceID: source.ID, ThreadID: 0},
+			).Return(nil).Times(1)
+			messageStorage.EXPECT().AddMessage(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, message *model.Message) error {
+					assert.Equal(t, chatID, message.ChatID)
+					assert.Equal(t, content.HashID, message.HashID)
+					return nil
+				},
+			).Times(1)
+
+			b.BroadcastNews(source, []*model.Subscribe{sub}, []*model.Content{content})
+			assert.Equal(t, []string{"42", ""}, api.threads)
+			assert.Equal(t, 0, sub.ThreadID)
+		},
+	)
+}
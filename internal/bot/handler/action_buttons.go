@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+type ActionButtons struct {
+	core *core.Core
+}
+
+func NewActionButtons(core *core.Core) *ActionButtons {
+	return &ActionButtons{core: core}
+}
+
+func (a *ActionButtons) Command() string {
+	return "/buttons"
+}
+
+func (a *ActionButtons) Description() string {
+	return "Toggle action buttons on pushed messages"
+}
+
+func (a *ActionButtons) Handle(ctx tb.Context) error {
+	mentionChat, _ := session.GetMentionChatFromCtxStore(ctx)
+	chatID := ctx.Chat().ID
+	if mentionChat != nil {
+		chatID = mentionChat.ID
+	}
+
+	preference, err := a.core.ToggleChatActionButton(context.Background(), chatID)
+	if err != nil {
+		log.Errorf("toggle chat %d action button failed, %v", chatID, err)
+		return ctx.Reply(tr(ctx, "buttons.failed"))
+	}
+
+	if preference.EnableActionButton == 1 {
+		return ctx.Reply(tr(ctx, "buttons.enabled"))
+	}
+	return ctx.Reply(tr(ctx, "buttons.disabled"))
+}
+
+func (a *ActionButtons) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
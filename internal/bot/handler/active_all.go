@@ -2,7 +2,7 @@ package handler
 
 import (
 	"context"
-	"fmt"
+	"html"
 
 	tb "gopkg.in/telebot.v3"
 
@@ -23,7 +23,7 @@ func (a *ActiveAll) Command() string {
 }
 
 func (a *ActiveAll) Description() string {
-	return "Start fetching subscription updates"
+	return "Start fetching subscription updates, /activeall #tag to activate a tag"
 }
 
 func (a *ActiveAll) Handle(ctx tb.Context) error {
@@ -33,21 +33,36 @@ func (a *ActiveAll) Handle(ctx tb.Context) error {
 		subscribeUserID = mentionChat.ID
 	}
 
-	source, err := a.core.GetUserSubscribedSources(context.Background(), subscribeUserID)
+	// a #tag activates only the subscriptions with the tag
+	tag := tagFromPayload(ctx.Message().Payload)
+	source, err := a.core.GetUserTagSubscribedSources(context.Background(), subscribeUserID, tag)
 	if err != nil {
-		return ctx.Reply("Internal service error")
+		return ctx.Reply(tr(ctx, "common.internal_error"))
+	}
+	if tag != "" && len(source) == 0 {
+		return ctx.Reply(tr(ctx, "tag.no_subscriptions", tag))
 	}
 
 	for _, s := range source {
 		err := a.core.EnableSourceUpdate(context.Background(), s.ID)
 		if err != nil {
-			return ctx.Reply("Activation failed")
+			return ctx.Reply(tr(ctx, "activeall.failed"))
 		}
 	}
 
-	reply := "All subscriptions has been enabled and activated"
+	if tag != "" {
+		// tags are often joined with _, which Markdown reads as italics
+		return ctx.Reply(
+			tr(ctx, "activeall.tag_success", len(source), html.EscapeString(tag)), &tb.SendOptions{
+				DisableWebPagePreview: true,
+				ParseMode:             tb.ModeHTML,
+			},
+		)
+	}
+
+	reply := tr(ctx, "activeall.success")
 	if mentionChat != nil {
-		reply = fmt.Sprintf("Channel [%s](https://t.me/%s) has enabled and activated all feed subscription sources update", mentionChat.Title, mentionChat.Username)
+		reply = tr(ctx, "activeall.channel_success", mentionChat.Title, mentionChat.Username)
 	}
 
 	return ctx.Reply(
@@ -3,7 +3,6 @@ package handler
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	"go.uber.org/zap"
 	tb "gopkg.in/telebot.v3"
@@ -34,27 +33,38 @@ func (a *AddSubscription) Description() string {
 func (a *AddSubscription) addSubscriptionForChat(ctx tb.Context) error {
 	sourceURL := message.URLFromMessage(ctx.Message())
 	if sourceURL == "" {
-		// 未附带链接，使用
-		hint := fmt.Sprintf("Please append the target subscription url for RSS feed at the end of the command; e.g.: %s https://github.blog/feed/", a.Command()) // Please append the target subscription url for RSS feed at the end of the command; e.g.: %s https://github.blog/feed/
+		// no link attached, show the usage
+		hint := tr(ctx, "sub.usage", a.Command())
 		return ctx.Send(hint, &tb.SendOptions{ReplyTo: ctx.Message()})
 	}
 
 	source, err := a.core.CreateSource(context.Background(), sourceURL)
 	if err != nil {
-		return ctx.Reply(fmt.Sprintf("%s, failed to subscribe", err))
+		return ctx.Reply(tr(ctx, "sub.source_failed", err))
 	}
 
 	log.Infof("%d subscribe [%d]%s %s", ctx.Chat().ID, source.ID, source.Title, source.Link)
 	if err := a.core.AddSubscription(context.Background(), ctx.Chat().ID, source.ID); err != nil {
 		if err == core.ErrSubscriptionExist {
-			return ctx.Reply("Source subscribed and exist in present feed list already, please do not repeatedly duplicate subscription")
+			return ctx.Reply(tr(ctx, "sub.exist"))
 		}
 		log.Errorf("add subscription user %d source %d failed %v", ctx.Chat().ID, source.ID, err)
-		return ctx.Reply("Failed to subscribe from source")
+		return ctx.Reply(tr(ctx, "sub.failed"))
+	}
+
+	registerChat(ctx, a.core, ctx.Chat())
+
+	// subscribed inside a forum topic, deliver updates to that topic
+	if ctx.Message().TopicMessage {
+		if err := a.core.SetSubscriptionThread(
+			context.Background(), ctx.Chat().ID, source.ID, ctx.Message().ThreadID,
+		); err != nil {
+			log.Errorf("set subscription user %d source %d thread failed %v", ctx.Chat().ID, source.ID, err)
+		}
 	}
 
 	return ctx.Reply(
-		fmt.Sprintf("[[%d]][%s](%s) Successfully subscribed from source to feed list", source.ID, source.Title, source.Link),
+		tr(ctx, "sub.success", source.ID, source.Title, source.Link),
 		&tb.SendOptions{
 			DisableWebPagePreview: true,
 			ParseMode:             tb.ModeMarkdown,
@@ -62,13 +72,12 @@ func (a *AddSubscription) addSubscriptionForChat(ctx tb.Context) error {
 	)
 }
 
-func (a *AddSubscription) hasChannelPrivilege(bot *tb.Bot, channelChat *tb.Chat, opUserID int64, botID int64) (
-	bool, error,
-) {
-	adminList, err := bot.AdminsOf(channelChat)
+// hasChannelPrivilege checks that both the operating user and the bot administer a channel
+func hasChannelPrivilege(ctx tb.Context, channelChat *tb.Chat, opUserID int64, botID int64) (bool, error) {
+	adminList, err := ctx.Bot().AdminsOf(channelChat)
 	if err != nil {
 		zap.S().Error(err)
-		return false, errors.New("Failed to fetch channel information from remote origin")
+		return false, errors.New(tr(ctx, "channel.fetch_failed"))
 	}
 
 	senderIsAdmin := false
@@ -88,42 +97,43 @@ func (a *AddSubscription) hasChannelPrivilege(bot *tb.Bot, channelChat *tb.Chat,
 func (a *AddSubscription) addSubscriptionForChannel(ctx tb.Context, channelName string) error {
 	sourceURL := message.URLFromMessage(ctx.Message())
 	if sourceURL == "" {
-		return ctx.Send("Please run ' /sub `@channel_id` URL ' command for subscription on behalf of a specific channel; e.g.: @toshikidev", &tb.SendOptions{ReplyTo: ctx.Message()})
+		return ctx.Send(tr(ctx, "sub.channel_usage"), &tb.SendOptions{ReplyTo: ctx.Message()})
 	}
 
 	bot := ctx.Bot()
 	channelChat, err := bot.ChatByUsername(channelName)
 	if err != nil {
-		return ctx.Reply("Failed to fetch channel details & information")
+		return ctx.Reply(tr(ctx, "channel.fetch_failed"))
 	}
 	if channelChat.Type != tb.ChatChannel {
-		return ctx.Reply("Either you or the bot is currently not the administrator of the channel provided, failed to configure subscription")
+		return ctx.Reply(tr(ctx, "sub.channel_no_privilege"))
 	}
 
-	hasPrivilege, err := a.hasChannelPrivilege(bot, channelChat, ctx.Sender().ID, bot.Me.ID)
+	hasPrivilege, err := hasChannelPrivilege(ctx, channelChat, ctx.Sender().ID, bot.Me.ID)
 	if err != nil {
 		return ctx.Reply(err.Error())
 	}
 	if !hasPrivilege {
-		return ctx.Reply("Either you or the bot is currently not the administrator of the channel provided, failed to configure subscription")
+		return ctx.Reply(tr(ctx, "sub.channel_no_privilege"))
 	}
 
 	source, err := a.core.CreateSource(context.Background(), sourceURL)
 	if err != nil {
-		return ctx.Reply(fmt.Sprintf("%s，Subscription failed", err))
+		return ctx.Reply(tr(ctx, "sub.source_failed", err))
 	}
 
 	log.Infof("%d subscribe [%d]%s %s", channelChat.ID, source.ID, source.Title, source.Link)
 	if err := a.core.AddSubscription(context.Background(), channelChat.ID, source.ID); err != nil {
 		if err == core.ErrSubscriptionExist {
-			return ctx.Reply("The source has been subscribed, please do not repeatedly duplicate subscription")
+			return ctx.Reply(tr(ctx, "sub.exist"))
 		}
 		log.Errorf("add subscription user %d source %d failed %v", channelChat.ID, source.ID, err)
-		return ctx.Reply("Failed to subscribe from source")
+		return ctx.Reply(tr(ctx, "sub.failed"))
 	}
+	registerChat(ctx, a.core, channelChat)
 
 	return ctx.Reply(
-		fmt.Sprintf("[[%d]] [%s](%s) Feed source has successfully subscribed", source.ID, source.Title, source.Link),
+		tr(ctx, "sub.success", source.ID, source.Title, source.Link),
 		&tb.SendOptions{
 			DisableWebPagePreview: true,
 			ParseMode:             tb.ModeMarkdown,
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/middleware"
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const (
+	// statsCycleCount number of latest fetch cycles the global statistics average
+	statsCycleCount = 10
+	// broadcastInterval pause between broadcast messages to stay below the Telegram rate limits
+	broadcastInterval = 50 * time.Millisecond
+)
+
+type Admin struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewAdmin(bot *tb.Bot, core *core.Core) *Admin {
+	return &Admin{bot: bot, core: core}
+}
+
+func (a *Admin) Command() string {
+	return "/admin"
+}
+
+func (a *Admin) Description() string {
+	return "Bot administration, /admin sources|chats|pause|resume|broadcast|stats|users|allow|deny|invite"
+}
+
+func (a *Admin) AdminOnly() bool {
+	return true
+}
+
+func (a *Admin) Handle(ctx tb.Context) error {
+	args := strings.Fields(ctx.Message().Payload)
+	if len(args) == 0 {
+		return ctx.Reply(tr(ctx, "admin.usage"))
+	}
+
+	switch args[0] {
+	case "sources":
+		return a.sources(ctx)
+	case "chats":
+		return a.chats(ctx)
+	case "pause", "resume":
+		if len(args) != 2 {
+			return ctx.Reply(tr(ctx, "admin.usage"))
+		}
+		return a.toggleSource(ctx, cast.ToUint(args[1]), args[0] == "pause")
+	case "broadcast":
+		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(ctx.Message().Payload), "broadcast"))
+		if text == "" {
+			return ctx.Reply(tr(ctx, "admin.usage"))
+		}
+		return a.broadcast(ctx, text)
+	case "stats":
+		return a.stats(ctx)
+	case "users":
+		return a.users(ctx)
+	case "allow", "deny":
+		if len(args) != 2 || cast.ToInt64(args[1]) == 0 {
+			return ctx.Reply(tr(ctx, "admin.usage"))
+		}
+		return a.setUserAllowed(ctx, cast.ToInt64(args[1]), args[0] == "allow")
+	case "invite":
+		return a.invite(ctx)
+	default:
+		return ctx.Reply(tr(ctx, "admin.usage"))
+	}
+}
+
+// sources replies all sources with their subscriber counts and states
+func (a *Admin) sources(ctx tb.Context) error {
+	sources, err := a.core.GetSources(context.Background())
+	if err != nil {
+		return ctx.Reply(tr(ctx, "admin.failed"))
+	}
+	if len(sources) == 0 {
+		return ctx.Reply(tr(ctx, "stats.no_sources"))
+	}
+
+	var msg strings.Builder
+	msg.WriteString(tr(ctx, "admin.sources_title", len(sources)))
+	for _, source := range sources {
+		count, err := a.core.CountSourceSubscriptions(context.Background(), source.ID)
+		if err != nil {
+			log.Errorf("count source %d subscriptions failed, %v", source.ID, err)
+		}
+		msg.WriteString(
+			fmt.Sprintf(
+				"[%d] <a href=\"%s\">%s</a>\n", source.ID, html.EscapeString(source.Link),
+				html.EscapeString(source.Title),
+			),
+		)
+		msg.WriteString(tr(ctx, "admin.source_subscribers", count, source.ErrorCount))
+		if source.ErrorCount >= config.ErrorThreshold {
+			msg.WriteString(", " + tr(ctx, "list.paused"))
+		}
+		msg.WriteString("\n")
+	}
+	return sendChunks(ctx, msg.String())
+}
+
+// chats replies the chats with subscriptions
+func (a *Admin) chats(ctx tb.Context) error {
+	counts, err := a.core.GetChatSubscriptionCounts(context.Background())
+	if err != nil {
+		log.Errorf("get chat subscription counts failed, %v", err)
+		return ctx.Reply(tr(ctx, "admin.failed"))
+	}
+	if len(counts) == 0 {
+		return ctx.Reply(tr(ctx, "admin.no_chats"))
+	}
+
+	var msg strings.Builder
+	msg.WriteString(tr(ctx, "admin.chats_title", len(counts)))
+	for _, count := range counts {
+		msg.WriteString(tr(ctx, "admin.chat", count.UserID, count.Count))
+		msg.WriteString("\n")
+	}
+	return sendChunks(ctx, msg.String())
+}
+
+// toggleSource pauses or resumes the updates of a source for all its subscribers
+func (a *Admin) toggleSource(ctx tb.Context, sourceID uint, pause bool) error {
+	source, err := a.core.GetSource(context.Background(), sourceID)
+	if err != nil {
+		return ctx.Reply(tr(ctx, "set.source_not_found"))
+	}
+
+	if pause {
+		err = a.core.DisableSourceUpdate(context.Background(), sourceID)
+	} else {
+		err = a.core.EnableSourceUpdate(context.Background(), sourceID)
+	}
+	if err != nil {
+		log.Errorf("admin toggle source %d failed, %v", sourceID, err)
+		return ctx.Reply(tr(ctx, "admin.failed"))
+	}
+
+	log.Infof("admin %d set source %d paused %v", ctx.Sender().ID, sourceID, pause)
+	if pause {
+		return ctx.Reply(tr(ctx, "admin.paused", source.ID, source.Title))
+	}
+	return ctx.Reply(tr(ctx, "admin.resumed", source.ID, source.Title))
+}
+
+// broadcast sends a notice to every chat with subscriptions in the background
+func (a *Admin) broadcast(ctx tb.Context, text string) error {
+	counts, err := a.core.GetChatSubscriptionCounts(context.Background())
+	if err != nil {
+		log.Errorf("get chat subscription counts failed, %v", err)
+		return ctx.Reply(tr(ctx, "admin.failed"))
+	}
+
+	log.Infof("admin %d broadcast to %d chats", ctx.Sender().ID, len(counts))
+	go func() {
+		sent := 0
+		for _, count := range counts {
+			if _, err := a.bot.Send(tb.ChatID(count.UserID), text); err != nil {
+				log.Warnf("broadcast to chat %d failed, %v", count.UserID, err)
+			} else {
+				sent++
+			}
+			time.Sleep(broadcastInterval)
+		}
+		if err := ctx.Send(tr(ctx, "admin.broadcast_done", sent, len(counts)-sent)); err != nil {
+			log.Errorf("send broadcast result failed, %v", err)
+		}
+	}()
+	return ctx.Reply(tr(ctx, "admin.broadcast_started", len(counts)))
+}
+
+// stats replies the fetch statistics of all sources and the latest fetch cycles
+func (a *Admin) stats(ctx tb.Context) error {
+	sources, err := a.core.GetSources(context.Background())
+	if err != nil {
+		return ctx.Reply(tr(ctx, "stats.failed"))
+	}
+	stats, err := a.core.GetSourceFetchStats(context.Background(), nil)
+	if err != nil {
+		log.Errorf("get source stats failed, %v", err)
+		return ctx.Reply(tr(ctx, "stats.failed"))
+	}
+	cycles, err := a.core.GetFetchCycles(context.Background(), statsCycleCount)
+	if err != nil {
+		log.Errorf("get fetch cycles failed, %v", err)
+		return ctx.Reply(tr(ctx, "stats.failed"))
+	}
+
+	var msg strings.Builder
+	if len(cycles) > 0 {
+		var total int64
+		for _, cycle := range cycles {
+			total += cycle.DurationMs
+		}
+		last := cycles[0]
+		msg.WriteString(
+			tr(
+				ctx, "stats.cycles", last.StartedAt.Format("2006-01-02 15:04:05"), last.SourceCount,
+				last.FailedCount, last.DurationMs, len(cycles), total/int64(len(cycles)),
+			),
+		)
+		msg.WriteString("\n\n")
+	}
+	if len(sources) == 0 {
+		msg.WriteString(tr(ctx, "stats.no_sources"))
+		return ctx.Reply(msg.String(), &tb.SendOptions{ParseMode: tb.ModeHTML})
+	}
+	msg.WriteString(sourceStatsText(ctx, sources, stats))
+	return sendChunks(ctx, msg.String())
+}
+
+// users replies the users on the allowlist
+func (a *Admin) users(ctx tb.Context) error {
+	users, err := a.core.GetAllowedUsers(context.Background())
+	if err != nil {
+		log.Errorf("get allowed users failed, %v", err)
+		return ctx.Reply(tr(ctx, "admin.failed"))
+	}
+
+	var msg strings.Builder
+	if !config.AllowlistEnabled {
+		msg.WriteString(tr(ctx, "admin.allowlist_disabled") + "\n\n")
+	}
+	if len(users) == 0 {
+		msg.WriteString(tr(ctx, "admin.no_users"))
+		return ctx.Reply(msg.String())
+	}
+	msg.WriteString(tr(ctx, "admin.users_title", len(users)))
+	for _, user := range users {
+		msg.WriteString(fmt.Sprintf("<code>%d</code>\n", user.ID))
+	}
+	return sendChunks(ctx, msg.String())
+}
+
+// setUserAllowed adds a user to the allowlist or removes it
+func (a *Admin) setUserAllowed(ctx tb.Context, userID int64, allowed bool) error {
+	if err := a.core.SetUserAllowed(context.Background(), userID, allowed); err != nil {
+		log.Errorf("set user %d allowed %v failed, %v", userID, allowed, err)
+		return ctx.Reply(tr(ctx, "admin.failed"))
+	}
+
+	log.Infof("admin %d set user %d allowed %v", ctx.Sender().ID, userID, allowed)
+	if allowed {
+		return ctx.Reply(tr(ctx, "admin.allowed", userID))
+	}
+	return ctx.Reply(tr(ctx, "admin.denied", userID))
+}
+
+// invite replies a one-time deep link that allowlists whoever starts the bot with it
+func (a *Admin) invite(ctx tb.Context) error {
+	invite, err := a.core.CreateInvite(context.Background(), ctx.Sender().ID)
+	if err != nil {
+		log.Errorf("create invite failed, %v", err)
+		return ctx.Reply(tr(ctx, "admin.failed"))
+	}
+
+	link := fmt.Sprintf("https://t.me/%s?start=%s", a.bot.Me.Username, invite.Code)
+	return ctx.Reply(tr(ctx, "admin.invite", link), &tb.SendOptions{DisableWebPagePreview: true})
+}
+
+func (a *Admin) Middlewares() []tb.MiddlewareFunc {
+	return []tb.MiddlewareFunc{middleware.AdminFilter()}
+}
+
+// notifyAdmins sends a notice to every bot admin in the language of their chat
+func notifyAdmins(bot *tb.Bot, appCore *core.Core, key string, args ...interface{}) {
+	for _, adminID := range config.AdminUsers {
+		lang := i18n.Default
+		preference, err := appCore.GetChatPreference(context.Background(), adminID)
+		if err == nil && preference.Language != "" {
+			lang = preference.Language
+		}
+		if _, err := bot.Send(&tb.User{ID: adminID}, i18n.T(lang, key, args...)); err != nil {
+			log.Errorf("notify admin %d failed, %v", adminID, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+// Backup sends the subscriptions and settings of a chat as a JSON file, /restore reads it back
+type Backup struct {
+	core *core.Core
+}
+
+func NewBackup(core *core.Core) *Backup {
+	return &Backup{core: core}
+}
+
+func (b *Backup) Command() string {
+	return "/backup"
+}
+
+func (b *Backup) Description() string {
+	return "Back up subscriptions and settings to a JSON file"
+}
+
+func (b *Backup) Handle(ctx tb.Context) error {
+	chatID := ctx.Chat().ID
+	if mentionChat, ok := session.GetMentionChatFromCtxStore(ctx); ok {
+		chatID = mentionChat.ID
+	}
+
+	backupData, err := b.core.Backup(context.Background(), chatID)
+	if err != nil {
+		log.Errorf("backup chat %d failed, %v", chatID, err)
+		return ctx.Reply(tr(ctx, "backup.failed"))
+	}
+	if len(backupData.Subscriptions) == 0 {
+		return ctx.Reply(tr(ctx, "list.empty"))
+	}
+	data, err := backupData.JSON()
+	if err != nil {
+		log.Errorf("marshal backup of chat %d failed, %v", chatID, err)
+		return ctx.Reply(tr(ctx, "backup.failed"))
+	}
+
+	file := &tb.Document{
+		File:     tb.FromReader(bytes.NewReader(data)),
+		FileName: fmt.Sprintf("backup_%d_%d.json", chatID, time.Now().Unix()),
+		Caption:  tr(ctx, "backup.caption", len(backupData.Subscriptions)),
+	}
+	if err := ctx.Reply(file); err != nil {
+		log.Errorf("send backup file failed, %v", err)
+		return ctx.Reply(tr(ctx, "backup.failed"))
+	}
+	return nil
+}
+
+func (b *Backup) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+const (
+	ContentMuteButtonUnique  = "content_mute_btn"
+	ContentPauseButtonUnique = "content_pause_btn"
+	ContentUnsubButtonUnique = "content_unsub_btn"
+	ContentSaveButtonUnique  = "content_save_btn"
+)
+
+// ContentActionMarkup generates the action buttons attached to a pushed content message, labeled in lang
+func ContentActionMarkup(lang string, sub *model.Subscribe, source *model.Source, contentHash uint32) *tb.ReplyMarkup {
+	data := session.Marshal(
+		&session.Attachment{
+			UserId:      sub.UserID,
+			SourceId:    uint32(sub.SourceID),
+			ContentHash: contentHash,
+		},
+	)
+
+	saveKey := tb.InlineButton{
+		Unique: ContentSaveButtonUnique,
+		Text:   i18n.T(lang, "action.save"),
+		Data:   data,
+	}
+
+	muteKey := tb.InlineButton{
+		Unique: ContentMuteButtonUnique,
+		Text:   i18n.T(lang, "action.mute"),
+		Data:   data,
+	}
+	if sub.EnableNotification != 1 {
+		muteKey.Text = i18n.T(lang, "action.unmute")
+	}
+
+	pauseKey := tb.InlineButton{
+		Unique: ContentPauseButtonUnique,
+		Text:   i18n.T(lang, "action.pause"),
+		Data:   data,
+	}
+	if source.ErrorCount >= config.ErrorThreshold {
+		pauseKey.Text = i18n.T(lang, "action.resume")
+	}
+
+	unsubKey := tb.InlineButton{
+		Unique: ContentUnsubButtonUnique,
+		Text:   i18n.T(lang, "action.unsubscribe"),
+		Data:   data,
+	}
+
+	return &tb.ReplyMarkup{InlineKeyboard: [][]tb.InlineButton{{saveKey}, {muteKey, pauseKey, unsubKey}}}
+}
+
+// contentActionAuth checks that the button belongs to the chat it was pressed in and that the sender manages the chat
+func contentActionAuth(ctx tb.Context) (*session.Attachment, error) {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return nil, errors.New("invalid callback")
+	}
+
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if attachData.GetUserId() != c.Message.Chat.ID {
+		return nil, errors.New("subscription does not belong to the chat")
+	}
+
+	if !chat.IsChatAdmin(ctx.Bot(), c.Message.Chat, c.Sender.ID) {
+		return nil, errors.New("permission denied")
+	}
+	return attachData, nil
+}
+
+// refreshContentActionMarkup redraws the action buttons of the pressed message
+func refreshContentActionMarkup(ctx tb.Context, appCore *core.Core, attachData *session.Attachment) error {
+	sourceID := uint(attachData.GetSourceId())
+	sub, err := appCore.GetSubscription(context.Background(), attachData.GetUserId(), sourceID)
+	if err != nil {
+		return err
+	}
+
+	source, err := appCore.GetSource(context.Background(), sourceID)
+	if err != nil {
+		return err
+	}
+
+	_, err = ctx.Bot().EditReplyMarkup(ctx.Callback().Message, ContentActionMarkup(session.GetLanguageFromCtxStore(ctx), sub, source, attachData.GetContentHash()))
+	return err
+}
+
+type ContentMuteButton struct {
+	core *core.Core
+}
+
+func NewContentMuteButton(core *core.Core) *ContentMuteButton {
+	return &ContentMuteButton{core: core}
+}
+
+func (b *ContentMuteButton) CallbackUnique() string {
+	return "\f" + ContentMuteButtonUnique
+}
+
+func (b *ContentMuteButton) Description() string {
+	return ""
+}
+
+func (b *ContentMuteButton) Handle(ctx tb.Context) error {
+	attachData, err := contentActionAuth(ctx)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	err = b.core.ToggleSubscriptionNotice(
+		context.Background(), attachData.GetUserId(), uint(attachData.GetSourceId()),
+	)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	if err := refreshContentActionMarkup(ctx, b.core, attachData); err != nil {
+		log.Errorf("refresh content action markup failed, %v", err)
+	}
+	return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.modified")})
+}
+
+func (b *ContentMuteButton) Persistent() bool {
+	return true
+}
+
+func (b *ContentMuteButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+type ContentPauseButton struct {
+	core *core.Core
+}
+
+func NewContentPauseButton(core *core.Core) *ContentPauseButton {
+	return &ContentPauseButton{core: core}
+}
+
+func (b *ContentPauseButton) CallbackUnique() string {
+	return "\f" + ContentPauseButtonUnique
+}
+
+func (b *ContentPauseButton) Description() string {
+	return ""
+}
+
+func (b *ContentPauseButton) Handle(ctx tb.Context) error {
+	attachData, err := contentActionAuth(ctx)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	sourceID := uint(attachData.GetSourceId())
+	sub, err := b.core.GetSubscription(context.Background(), attachData.GetUserId(), sourceID)
+	if sub == nil || err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	if err := b.core.ToggleSourceUpdateStatus(context.Background(), sourceID); err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	if err := refreshContentActionMarkup(ctx, b.core, attachData); err != nil {
+		log.Errorf("refresh content action markup failed, %v", err)
+	}
+	return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.modified")})
+}
+
+func (b *ContentPauseButton) Persistent() bool {
+	return true
+}
+
+func (b *ContentPauseButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+type ContentUnsubButton struct {
+	core *core.Core
+}
+
+func NewContentUnsubButton(core *core.Core) *ContentUnsubButton {
+	return &ContentUnsubButton{core: core}
+}
+
+func (b *ContentUnsubButton) CallbackUnique() string {
+	return "\f" + ContentUnsubButtonUnique
+}
+
+func (b *ContentUnsubButton) Description() string {
+	return ""
+}
+
+func (b *ContentUnsubButton) Handle(ctx tb.Context) error {
+	attachData, err := contentActionAuth(ctx)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	userID := attachData.GetUserId()
+	sourceID := uint(attachData.GetSourceId())
+	if _, err := b.core.Unsubscribe(context.Background(), userID, sourceID); err != nil {
+		log.Errorf("unsubscribe data %s failed, %v", ctx.Callback().Data, err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "unsub.failed")})
+	}
+	log.Infof("%d unsubscribe [%d] from content action button", userID, sourceID)
+
+	if _, err := ctx.Bot().EditReplyMarkup(ctx.Callback().Message, nil); err != nil {
+		log.Errorf("remove content action markup failed, %v", err)
+	}
+	return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "unsub.success")})
+}
+
+func (b *ContentUnsubButton) Persistent() bool {
+	return true
+}
+
+func (b *ContentUnsubButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+type ContentSaveButton struct {
+	core *core.Core
+}
+
+func NewContentSaveButton(core *core.Core) *ContentSaveButton {
+	return &ContentSaveButton{core: core}
+}
+
+func (b *ContentSaveButton) CallbackUnique() string {
+	return "\f" + ContentSaveButtonUnique
+}
+
+func (b *ContentSaveButton) Description() string {
+	return ""
+}
+
+func (b *ContentSaveButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil || attachData.GetContentHash() == 0 {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	// anyone in the chat may save, the bookmark belongs to the sender
+	if attachData.GetUserId() != c.Message.Chat.ID {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	_, err = b.core.SaveBookmark(context.Background(), c.Sender.ID, c.Message.Chat.ID, attachData.GetHashID())
+	if err != nil {
+		if errors.Is(err, core.ErrBookmarkExist) {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "action.saved_exist")})
+		}
+		if errors.Is(err, core.ErrContentNotExist) {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "action.content_gone")})
+		}
+		log.Errorf("save bookmark %s for %d failed, %v", attachData.GetHashID(), c.Sender.ID, err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "action.save_failed")})
+	}
+	return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "action.save_success")})
+}
+
+func (b *ContentSaveButton) Persistent() bool {
+	return true
+}
+
+func (b *ContentSaveButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
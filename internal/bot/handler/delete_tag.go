@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+)
+
+type DeleteTag struct {
+	core *core.Core
+}
+
+func NewDeleteTag(core *core.Core) *DeleteTag {
+	return &DeleteTag{core: core}
+}
+
+func (d *DeleteTag) Command() string {
+	return "/deltag"
+}
+
+func (d *DeleteTag) Description() string {
+	return "Remove a tag from all subscriptions, /deltag #tag"
+}
+
+func (d *DeleteTag) Handle(ctx tb.Context) error {
+	tags := tagsFromPayload(ctx.Message().Payload)
+	if len(tags) != 1 {
+		return ctx.Reply(tr(ctx, "deltag.usage"))
+	}
+
+	count, err := d.core.DeleteTag(context.Background(), tagOwnerID(ctx), tags[0])
+	if err != nil {
+		return tagErrorReply(ctx, err, tags[0], "deltag.failed")
+	}
+	return ctx.Reply(tr(ctx, "deltag.success", tags[0], count))
+}
+
+func (d *DeleteTag) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
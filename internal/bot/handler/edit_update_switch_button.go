@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+)
+
+const (
+	EditUpdateSwitchButtonUnique = "set_toggle_edit_update_btn"
+)
+
+type EditUpdateSwitchButton struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewEditUpdateSwitchButton(bot *tb.Bot, core *core.Core) *EditUpdateSwitchButton {
+	return &EditUpdateSwitchButton{bot: bot, core: core}
+}
+
+func (b *EditUpdateSwitchButton) CallbackUnique() string {
+	return "\f" + EditUpdateSwitchButtonUnique
+}
+
+func (b *EditUpdateSwitchButton) Description() string {
+	return ""
+}
+
+func (b *EditUpdateSwitchButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	subscriberID := attachData.GetUserId()
+	if subscriberID != c.Sender.ID {
+
+		channelChat, err := b.bot.ChatByID(subscriberID)
+		if err != nil {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+		}
+		if !chat.IsChatAdmin(b.bot, channelChat, c.Sender.ID) {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+		}
+	}
+
+	sourceID := uint(attachData.GetSourceId())
+	source, err := b.core.GetSource(context.Background(), sourceID)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	err = b.core.ToggleSubscriptionEditUpdate(context.Background(), subscriberID, sourceID)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	sub, err := b.core.GetSubscription(context.Background(), subscriberID, sourceID)
+	if sub == nil || err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+
+	text := feedSettingText(ctx, b.core, source, sub)
+	_ = ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.modified")})
+	return ctx.Edit(
+		text,
+		&tb.SendOptions{ParseMode: tb.ModeHTML},
+		&tb.ReplyMarkup{InlineKeyboard: genFeedSetBtn(ctx, sub, source)},
+	)
+}
+
+func (b *EditUpdateSwitchButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
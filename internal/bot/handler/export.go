@@ -33,16 +33,17 @@ func (e *Export) Command() string {
 	return "/export"
 }
 
-func (e *Export) getChannelSources(bot *tb.Bot, opUserID int64, channelName string) ([]*model.Source, error) {
+func (e *Export) getChannelID(ctx tb.Context, channelName string) (int64, error) {
+	bot, opUserID := ctx.Bot(), ctx.Chat().ID
 	// export channel subscription sources
 	channelChat, err := bot.ChatByUsername(channelName)
 	if err != nil {
-		return nil, errors.New("Failed to fetch channel information from remote origin}")
+		return 0, errors.New(tr(ctx, "channel.fetch_failed"))
 	}
 
 	adminList, err := bot.AdminsOf(channelChat)
 	if err != nil {
-		return nil, errors.New("Failed to fetch information of channel administrator")
+		return 0, errors.New(tr(ctx, "channel.admins_failed"))
 	}
 
 	senderIsAdmin := false
@@ -54,49 +55,66 @@ func (e *Export) getChannelSources(bot *tb.Bot, opUserID int64, channelName stri
 	}
 
 	if !senderIsAdmin {
-		return nil, errors.New("Bot operational executions by non-administrative users are not permitted")
+		return 0, errors.New(tr(ctx, "channel.not_admin"))
 	}
+	return channelChat.ID, nil
+}
 
-	sources, err := e.core.GetUserSubscribedSources(context.Background(), channelChat.ID)
+// getFeeds gets the subscriptions of a chat to export with their tags and titles
+func (e *Export) getFeeds(chatID int64, tag string) ([]opml.Feed, error) {
+	subscriptions, err := e.core.GetUserTagSubscriptions(context.Background(), chatID, tag)
 	if err != nil {
-		zap.S().Error(err)
-		return nil, errors.New("Failed to fetch resources or information from remote source origin")
+		return nil, err
 	}
-	return sources, nil
+
+	var feeds []opml.Feed
+	for _, sub := range subscriptions {
+		source, err := e.core.GetSource(context.Background(), sub.SourceID)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(
+			feeds, opml.Feed{Title: sub.DisplayTitle(source), XMLURL: source.Link, Tags: model.ParseTags(sub.Tag)},
+		)
+	}
+	return feeds, nil
 }
 
 func (e *Export) Handle(ctx tb.Context) error {
 	mention := message.MentionFromMessage(ctx.Message())
-	var sources []*model.Source
-	if mention == "" {
-		var err error
-		sources, err = e.core.GetUserSubscribedSources(context.Background(), ctx.Chat().ID)
-		if err != nil {
-			log.Error(err)
-			return ctx.Send("Failed to export") //
-		}
-	} else {
+	// a #tag exports only the subscriptions with the tag
+	tag := tagFromPayload(ctx.Message().Payload)
+	chatID := ctx.Chat().ID
+	if mention != "" {
 		var err error
-		sources, err = e.getChannelSources(ctx.Bot(), ctx.Chat().ID, mention)
+		chatID, err = e.getChannelID(ctx, mention)
 		if err != nil {
 			log.Error(err)
-			return ctx.Send("Failed to export")
+			return ctx.Send(err.Error())
 		}
 	}
 
-	if len(sources) == 0 {
-		return ctx.Send("The subscription list is currently empty ")
+	feeds, err := e.getFeeds(chatID, tag)
+	if err != nil {
+		zap.S().Error(err)
+		return ctx.Send(tr(ctx, "subscriptions.fetch_failed"))
+	}
+	if len(feeds) == 0 {
+		if tag != "" {
+			return ctx.Send(tr(ctx, "tag.no_subscriptions", tag))
+		}
+		return ctx.Send(tr(ctx, "list.empty"))
 	}
 
-	opmlStr, err := opml.ToOPML(sources)
+	opmlStr, err := opml.ToOPML(feeds)
 	if err != nil {
-		return ctx.Send("Failed to export")
+		return ctx.Send(tr(ctx, "export.failed"))
 	}
 	opmlFile := &tb.Document{File: tb.FromReader(strings.NewReader(opmlStr))}
 	opmlFile.FileName = fmt.Sprintf("subscriptions_%d.opml", time.Now().Unix())
 	if err := ctx.Send(opmlFile); err != nil {
 		log.Errorf("send OPML file failed, err:%v", err)
-		return ctx.Send("Failed to export")
+		return ctx.Send(tr(ctx, "export.failed"))
 	}
 	return nil
 }
@@ -1,6 +1,11 @@
 package handler
 
-import tb "gopkg.in/telebot.v3"
+import (
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+)
 
 type CommandHandler interface {
 	// Command string of bot Command
@@ -13,6 +18,12 @@ type CommandHandler interface {
 	Middlewares() []tb.MiddlewareFunc
 }
 
+// AdminCommandHandler a command only bot admins can use, hidden from the command menus of other users
+type AdminCommandHandler interface {
+	CommandHandler
+	AdminOnly() bool
+}
+
 type ButtonHandler interface {
 	tb.CallbackEndpoint
 	// Description of Command
@@ -22,3 +33,14 @@ type ButtonHandler interface {
 	// Middlewares Handler middlewares
 	Middlewares() []tb.MiddlewareFunc
 }
+
+// PersistentButtonHandler buttons attached to the pushed contents, their callback data never expires
+type PersistentButtonHandler interface {
+	ButtonHandler
+	Persistent() bool
+}
+
+// tr translates a key to the reply language of the update
+func tr(ctx tb.Context, key string, args ...interface{}) string {
+	return i18n.T(session.GetLanguageFromCtxStore(ctx), key, args...)
+}
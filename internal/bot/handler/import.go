@@ -18,8 +18,7 @@ func (i *Import) Description() string {
 }
 
 func (i *Import) Handle(ctx tb.Context) error {
-	reply := "Please append and send your OPML attachment as a direct message to the bot; if you need to import OPML feed on behalf of your channel, please affix the channel ID with the attachment; eg: @toshikidev"
-	return ctx.Reply(reply)
+	return ctx.Reply(tr(ctx, "import.hint"))
 }
 
 func (i *Import) Middlewares() []tb.MiddlewareFunc {
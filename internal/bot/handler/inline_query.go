@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/preview"
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+	"github.com/andatoshiki/toshiki-rssbot/internal/storage"
+)
+
+const (
+	MaxInlineResultsPerPage = 20
+	// InlineCacheTime seconds telegram caches the results of a query, results are cached per user
+	InlineCacheTime = 60
+)
+
+type InlineQuery struct {
+	core *core.Core
+}
+
+func NewInlineQuery(core *core.Core) *InlineQuery {
+	return &InlineQuery{core: core}
+}
+
+func (q *InlineQuery) Command() string {
+	return tb.OnQuery
+}
+
+func (q *InlineQuery) Description() string {
+	return ""
+}
+
+// Handle answers an inline query with recent matching items of the sources the querying user subscribed
+func (q *InlineQuery) Handle(ctx tb.Context) error {
+	query := ctx.Query()
+	offset, _ := strconv.Atoi(query.Offset)
+
+	stdCtx := context.Background()
+	opts := &storage.SearchContentsOptions{Query: query.Text, Count: MaxInlineResultsPerPage, Offset: offset}
+	result, err := q.core.SearchContents(stdCtx, query.Sender.ID, 0, opts)
+	if err != nil {
+		log.Errorf("inline search contents of %d failed, %v", query.Sender.ID, err)
+		return ctx.Answer(&tb.QueryResponse{IsPersonal: true})
+	}
+
+	sources := make(map[uint]*model.Source)
+	subs := make(map[uint]*model.Subscribe)
+	var results tb.Results
+	for _, content := range result.Contents {
+		source, ok := sources[content.SourceID]
+		if !ok {
+			source, err = q.core.GetSource(stdCtx, content.SourceID)
+			if err != nil {
+				log.Errorf("get source %d failed, %v", content.SourceID, err)
+				continue
+			}
+			sources[content.SourceID] = source
+		}
+		sub, ok := subs[content.SourceID]
+		if !ok {
+			sub, err = q.core.GetSubscription(stdCtx, query.Sender.ID, content.SourceID)
+			if err != nil {
+				log.Errorf("get subscription %d of %d failed, %v", content.SourceID, query.Sender.ID, err)
+				continue
+			}
+			subs[content.SourceID] = sub
+		}
+
+		tpldata := &config.TplData{
+			SourceTitle:     source.Title,
+			ContentTitle:    content.Title,
+			RawLink:         content.RawLink,
+			PreviewText:     preview.TrimDescription(content.Text, preview.Length(sub)),
+			TelegraphURL:    content.TelegraphURL,
+			Tags:            sub.Tag,
+			EnableTelegraph: sub.EnableTelegraph == 1 && content.TelegraphURL != "",
+		}
+		msg, err := tpldata.Render(config.MessageMode)
+		if err != nil {
+			log.Errorf("render content %s failed, %v", content.HashID, err)
+			continue
+		}
+
+		article := &tb.ArticleResult{
+			Title:       content.Title,
+			Description: source.Title + " " + content.CreatedAt.Format("2006-01-02"),
+			URL:         content.RawLink,
+			HideURL:     true,
+		}
+		article.ID = content.HashID
+		article.Content = &tb.InputTextMessageContent{
+			Text:           msg,
+			ParseMode:      string(config.MessageMode),
+			DisablePreview: preview.DisableWebPage(sub),
+		}
+		results = append(results, article)
+	}
+
+	resp := &tb.QueryResponse{
+		Results:    results,
+		CacheTime:  InlineCacheTime,
+		IsPersonal: true,
+	}
+	if result.HasMore {
+		resp.NextOffset = strconv.Itoa(offset + MaxInlineResultsPerPage)
+	}
+	return ctx.Answer(resp)
+}
+
+func (q *InlineQuery) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const (
+	SetLanguageButtonUnique = "set_lang_btn"
+	// languageAuto follows the language of the Telegram user instead of a chat preference
+	languageAuto = "auto"
+)
+
+type Lang struct {
+	core *core.Core
+}
+
+func NewLang(core *core.Core) *Lang {
+	return &Lang{core: core}
+}
+
+func (l *Lang) Command() string {
+	return "/lang"
+}
+
+func (l *Lang) Description() string {
+	return "Choose the reply language of the chat"
+}
+
+func (l *Lang) Handle(ctx tb.Context) error {
+	mentionChat, _ := session.GetMentionChatFromCtxStore(ctx)
+	chatID := ctx.Chat().ID
+	if mentionChat != nil {
+		chatID = mentionChat.ID
+	}
+
+	payload := strings.ToLower(strings.TrimSpace(ctx.Message().Payload))
+	if payload == "" {
+		return ctx.Reply(tr(ctx, "lang.select"), &tb.ReplyMarkup{InlineKeyboard: languageButtons(ctx, chatID)})
+	}
+
+	language := i18n.Normalize(payload)
+	if language == "" && payload != languageAuto {
+		return ctx.Reply(tr(ctx, "lang.usage", strings.Join(i18n.Languages, "|")))
+	}
+	return setChatLanguage(ctx, l.core, chatID, language, ctx.Reply)
+}
+
+func (l *Lang) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// languageButtons generates a button per supported language, the first one clears the chat preference
+func languageButtons(ctx tb.Context, chatID int64) [][]tb.InlineButton {
+	row := []tb.InlineButton{
+		{
+			Unique: SetLanguageButtonUnique,
+			Text:   tr(ctx, "lang.auto"),
+			Data:   session.Marshal(&session.Attachment{UserId: chatID}),
+		},
+	}
+	for i, language := range i18n.Languages {
+		row = append(
+			row, tb.InlineButton{
+				Unique: SetLanguageButtonUnique,
+				Text:   i18n.T(language, "lang.name"),
+				Data:   session.Marshal(&session.Attachment{UserId: chatID, Value: int32(i + 1)}),
+			},
+		)
+	}
+	return [][]tb.InlineButton{row}
+}
+
+// setChatLanguage saves the language of a chat and confirms in the new language
+func setChatLanguage(
+	ctx tb.Context, appCore *core.Core, chatID int64, language string,
+	reply func(what interface{}, opts ...interface{}) error,
+) error {
+	if err := appCore.SetChatLanguage(context.Background(), chatID, language); err != nil {
+		log.Errorf("set chat %d language failed, %v", chatID, err)
+		return reply(tr(ctx, "lang.failed"))
+	}
+
+	if language == "" {
+		language = i18n.Normalize(ctx.Sender().LanguageCode)
+		if language == "" {
+			language = i18n.Default
+		}
+		return reply(i18n.T(language, "lang.auto_success", i18n.T(language, "lang.name")))
+	}
+	return reply(i18n.T(language, "lang.success", i18n.T(language, "lang.name")))
+}
+
+type SetLanguageButton struct {
+	core *core.Core
+}
+
+func NewSetLanguageButton(core *core.Core) *SetLanguageButton {
+	return &SetLanguageButton{core: core}
+}
+
+func (b *SetLanguageButton) CallbackUnique() string {
+	return "\f" + SetLanguageButtonUnique
+}
+
+func (b *SetLanguageButton) Description() string {
+	return ""
+}
+
+func (b *SetLanguageButton) Handle(ctx tb.Context) error {
+	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	if !feedSetAuth(ctx.Bot(), ctx.Callback(), attachData) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	index := int(attachData.GetValue())
+	if index < 0 || index > len(i18n.Languages) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	language := ""
+	if index > 0 {
+		language = i18n.Languages[index-1]
+	}
+
+	_ = ctx.Respond()
+	return setChatLanguage(ctx, b.core, attachData.GetUserId(), language, ctx.Edit)
+}
+
+func (b *SetLanguageButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -2,20 +2,24 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
 	"strings"
 
 	tb "gopkg.in/telebot.v3"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/message"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
 	"github.com/andatoshiki/toshiki-rssbot/internal/log"
-	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 )
 
 const (
-	MaxSubsSizePerPage = 50
+	MaxSubsSizePerPage         = 20
+	ListSubscriptionPageUnique = "list_page_btn"
 )
 
 type ListSubscription struct {
@@ -34,72 +38,175 @@ func (l *ListSubscription) Description() string {
 	return "All subscribed rss source feeds"
 }
 
-func (l *ListSubscription) listChatSubscription(ctx tb.Context) error {
-	// private chat or group
-	if ctx.Chat().Type != tb.ChatPrivate && !chat.IsChatAdmin(ctx.Bot(), ctx.Chat(), ctx.Sender().ID) {
-		return ctx.Send("No permission or access rights")
+func (l *ListSubscription) Handle(ctx tb.Context) error {
+	mention := message.MentionFromMessage(ctx.Message())
+	var chatID int64
+	if mention != "" {
+		channelChat, err := ctx.Bot().ChatByUsername(mention)
+		if err != nil {
+			return ctx.Send(tr(ctx, "channel.fetch_failed"))
+		}
+
+		if !chat.IsChatAdmin(ctx.Bot(), channelChat, ctx.Sender().ID) {
+			return ctx.Send(tr(ctx, "channel.not_admin"))
+		}
+		chatID = channelChat.ID
+	} else {
+		// private chat or group
+		if ctx.Chat().Type != tb.ChatPrivate && !chat.IsChatAdmin(ctx.Bot(), ctx.Chat(), ctx.Sender().ID) {
+			return ctx.Send(tr(ctx, "common.permission_denied"))
+		}
+		chatID = ctx.Chat().ID
 	}
 
-	stdCtx := context.Background()
-	sources, err := l.core.GetUserSubscribedSources(stdCtx, ctx.Chat().ID)
+	text, markup, err := subscriptionPage(ctx, l.core, chatID, tagFromPayload(ctx.Message().Payload), 0)
 	if err != nil {
-		log.Errorf("GetUserSubscribedSources failed, %v", err)
-		return ctx.Send("Errors or failures to fetch subscription list")
+		return ctx.Send(err.Error())
 	}
+	return ctx.Send(text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML}, markup)
+}
 
-	return l.replaySubscribedSources(ctx, sources)
+func (l *ListSubscription) Middlewares() []tb.MiddlewareFunc {
+	return nil
 }
 
-func (l *ListSubscription) listChannelSubscription(ctx tb.Context, channelName string) error {
-	channelChat, err := ctx.Bot().ChatByUsername(channelName)
-	if err != nil {
-		return ctx.Send("Failed to fetch channel information")
+// tagFromPayload gets the #tag filter of a list request
+func tagFromPayload(payload string) string {
+	for _, field := range strings.Fields(payload) {
+		if strings.HasPrefix(field, "#") && len(field) > 1 {
+			return field
+		}
 	}
+	return ""
+}
 
-	if !chat.IsChatAdmin(ctx.Bot(), channelChat, ctx.Sender().ID) {
-		return ctx.Send("Bot operational executions by non-administrative users of chanel are not permitted") 
-	}
+// tagFromHeader gets the #tag filter back from the header line of a rendered list page
+func tagFromHeader(text string) string {
+	header, _, _ := strings.Cut(text, "\n")
+	return tagFromPayload(header)
+}
 
+// subscriptionPage renders one page of the subscription list of a chat
+func subscriptionPage(ctx tb.Context, appCore *core.Core, chatID int64, tag string, page int) (
+	string, *tb.ReplyMarkup, error,
+) {
 	stdCtx := context.Background()
-	sources, err := l.core.GetUserSubscribedSources(stdCtx, channelChat.ID)
+	result, err := appCore.GetUserSubscriptionPage(stdCtx, chatID, tag, page*MaxSubsSizePerPage, MaxSubsSizePerPage)
 	if err != nil {
-		log.Errorf("GetUserSubscribedSources failed, %v", err)
-		return ctx.Send("Errors or failures to fetch subscriptions")
+		log.Errorf("GetUserSubscriptionPage failed, %v", err)
+		return "", nil, errors.New(tr(ctx, "subscriptions.fetch_failed"))
+	}
+	if len(result.Subscriptions) == 0 {
+		if page == 0 {
+			return tr(ctx, "list.empty"), nil, nil
+		}
+		return tr(ctx, "list.no_more"), nil, nil
+	}
+
+	// the tag stays a separate word of the header, the page buttons read it back from there
+	var msg strings.Builder
+	if tag != "" {
+		msg.WriteString(tr(ctx, "list.tag_title", html.EscapeString(tag), page+1))
+	} else {
+		msg.WriteString(tr(ctx, "list.title", page+1))
+	}
+	for _, sub := range result.Subscriptions {
+		source, err := appCore.GetSource(stdCtx, sub.SourceID)
+		if err != nil {
+			log.Errorf("get source %d failed, %v", sub.SourceID, err)
+			continue
+		}
+
+		states := []string{fmt.Sprintf("%dmin", sub.Interval)}
+		if source.ErrorCount >= config.ErrorThreshold {
+			states = append(states, tr(ctx, "list.paused"))
+		}
+		if sub.EnableNotification != 1 {
+			states = append(states, tr(ctx, "list.muted"))
+		}
+		msg.WriteString(
+			fmt.Sprintf(
+				"[%d] <a href=\"%s\">%s</a> %s\n", source.ID, html.EscapeString(source.Link),
+				html.EscapeString(sub.DisplayTitle(source)), strings.Join(states, " · "),
+			),
+		)
 	}
-	return l.replaySubscribedSources(ctx, sources)
+
+	var row []tb.InlineButton
+	if page > 0 {
+		row = append(row, subscriptionPageButton(chatID, page-1, tr(ctx, "common.previous")))
+	}
+	if result.HasMore {
+		row = append(row, subscriptionPageButton(chatID, page+1, tr(ctx, "common.next")))
+	}
+	markup := &tb.ReplyMarkup{}
+	if len(row) > 0 {
+		markup.InlineKeyboard = [][]tb.InlineButton{row}
+	}
+	return msg.String(), markup, nil
 }
 
-func (l *ListSubscription) Handle(ctx tb.Context) error {
-	mention := message.MentionFromMessage(ctx.Message())
-	if mention != "" {
-		return l.listChannelSubscription(ctx, mention)
+func subscriptionPageButton(chatID int64, page int, text string) tb.InlineButton {
+	return tb.InlineButton{
+		Unique: ListSubscriptionPageUnique,
+		Text:   text,
+		Data:   session.Marshal(&session.Attachment{UserId: chatID, Page: uint32(page)}),
 	}
-	return l.listChatSubscription(ctx)
 }
 
-func (l *ListSubscription) Middlewares() []tb.MiddlewareFunc {
-	return nil
+type ListSubscriptionPageButton struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewListSubscriptionPageButton(bot *tb.Bot, core *core.Core) *ListSubscriptionPageButton {
+	return &ListSubscriptionPageButton{bot: bot, core: core}
+}
+
+func (b *ListSubscriptionPageButton) CallbackUnique() string {
+	return "\f" + ListSubscriptionPageUnique
+}
+
+func (b *ListSubscriptionPageButton) Description() string {
+	return ""
 }
 
-func (l *ListSubscription) replaySubscribedSources(ctx tb.Context, sources []*model.Source) error {
-	if len(sources) == 0 {
-		return ctx.Send("The current subscription list is empty") 
+func (b *ListSubscriptionPageButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
-	var msg strings.Builder
-	msg.WriteString(fmt.Sprintf("Total of %d subscription(s), subscription list\n", len(sources)))
-	count := 0
-	for i := range sources {
-		msg.WriteString(fmt.Sprintf("[[%d]] [%s](%s)\n", sources[i].ID, sources[i].Title, sources[i].Link))
-		count++
-		if count == MaxSubsSizePerPage {
-			ctx.Send(msg.String(), &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeMarkdown})
-			count = 0
-			msg.Reset()
+
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	// the list may belong to a channel managed from this chat
+	chatID := attachData.GetUserId()
+	if chatID != c.Sender.ID {
+		targetChat := c.Message.Chat
+		if chatID != targetChat.ID {
+			targetChat, err = b.bot.ChatByID(chatID)
+			if err != nil {
+				return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+			}
+		}
+		if !chat.IsChatAdmin(b.bot, targetChat, c.Sender.ID) {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
 		}
 	}
 
-	if count != 0 {
-		ctx.Send(msg.String(), &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeMarkdown})
+	text, markup, err := subscriptionPage(ctx, b.core, chatID, tagFromHeader(c.Message.Text), int(attachData.GetPage()))
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: err.Error()})
 	}
+	if err := ctx.Edit(text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML}, markup); err != nil {
+		log.Errorf("edit subscription page failed, %v", err)
+	}
+	return ctx.Respond()
+}
+
+func (b *ListSubscriptionPageButton) Middlewares() []tb.MiddlewareFunc {
 	return nil
 }
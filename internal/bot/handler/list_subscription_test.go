@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+)
+
+func TestTagFromHeader(t *testing.T) {
+	for _, lang := range i18n.Languages {
+		header := i18n.T(lang, "list.tag_title", "#news", 2) + "[1] source 10min\n"
+		assert.Equal(t, "#news", tagFromHeader(header), lang)
+
+		header = i18n.T(lang, "list.title", 2) + "[1] #source 10min\n"
+		assert.Equal(t, "", tagFromHeader(header), lang)
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+type MergeTag struct {
+	core *core.Core
+}
+
+func NewMergeTag(core *core.Core) *MergeTag {
+	return &MergeTag{core: core}
+}
+
+func (m *MergeTag) Command() string {
+	return "/mergetag"
+}
+
+func (m *MergeTag) Description() string {
+	return "Merge a tag into another one, /mergetag #from #to"
+}
+
+func (m *MergeTag) Handle(ctx tb.Context) error {
+	tags := tagsFromPayload(ctx.Message().Payload)
+	if len(tags) != 2 || model.NormalizeTag(tags[0]) == model.NormalizeTag(tags[1]) {
+		return ctx.Reply(tr(ctx, "mergetag.usage"))
+	}
+
+	count, err := m.core.MergeTag(context.Background(), tagOwnerID(ctx), tags[0], tags[1])
+	if err == core.ErrTagNotExist {
+		return ctx.Reply(tr(ctx, "mergetag.not_found", tags[0], tags[1]))
+	}
+	if err != nil {
+		return tagErrorReply(ctx, err, tags[0], "mergetag.failed")
+	}
+	return ctx.Reply(tr(ctx, "mergetag.success", count, tags[0], tags[1]))
+}
+
+func (m *MergeTag) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
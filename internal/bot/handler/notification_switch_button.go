@@ -1,15 +1,12 @@
 package handler
 
 import (
-	"bytes"
 	"context"
-	"text/template"
 
 	tb "gopkg.in/telebot.v3"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
-	"github.com/andatoshiki/toshiki-rssbot/internal/config"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
 )
 
@@ -37,46 +34,46 @@ func (b *NotificationSwitchButton) Description() string {
 func (b *NotificationSwitchButton) Handle(ctx tb.Context) error {
 	c := ctx.Callback()
 	if c == nil {
-		return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
 
 	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
 	if err != nil {
-		return ctx.Edit("Internal system error!")
+		return ctx.Edit(tr(ctx, "common.internal_error"))
 	}
 
 	subscriberID := attachData.GetUserId()
 	if subscriberID != c.Sender.ID {
 		channelChat, err := b.bot.ChatByID(subscriberID)
 		if err != nil {
-			return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 		}
 		if !chat.IsChatAdmin(b.bot, channelChat, c.Sender.ID) {
-			return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 		}
 	}
 
 	sourceID := uint(attachData.GetSourceId())
-	source, _ := b.core.GetSource(context.Background(), sourceID)
-	t := template.New("setting template")
-	_, _ = t.Parse(feedSettingTmpl)
+	source, err := b.core.GetSource(context.Background(), sourceID)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
 
 	err = b.core.ToggleSubscriptionNotice(context.Background(), subscriberID, sourceID)
 	if err != nil {
-		return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
 
 	sub, err := b.core.GetSubscription(context.Background(), subscriberID, sourceID)
 	if err != nil {
-		return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
-	text := new(bytes.Buffer)
-	_ = t.Execute(text, map[string]interface{}{"source": source, "sub": sub, "Count": config.ErrorThreshold})
-	_ = ctx.Respond(&tb.CallbackResponse{Text: "Modification success"})
+	text := feedSettingText(ctx, b.core, source, sub)
+	_ = ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.modified")})
 	return ctx.Edit(
-		text.String(),
+		text,
 		&tb.SendOptions{ParseMode: tb.ModeHTML},
-		&tb.ReplyMarkup{InlineKeyboard: genFeedSetBtn(c, sub, source)},
+		&tb.ReplyMarkup{InlineKeyboard: genFeedSetBtn(ctx, sub, source)},
 	)
 }
 
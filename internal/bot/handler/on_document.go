@@ -4,26 +4,100 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
+	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/importer"
 	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 	"github.com/andatoshiki/toshiki-rssbot/internal/opml"
 
 	tb "gopkg.in/telebot.v3"
 )
 
+const (
+	// importWorkerCount number of feeds imported at the same time
+	importWorkerCount = 5
+	// importProgressInterval interval the import status is updated at
+	importProgressInterval = 2 * time.Second
+	// importRetryLimit number of failed feeds listed with retry buttons
+	importRetryLimit = 30
+	// importRetryButtonsPerRow number of retry buttons in a row
+	importRetryButtonsPerRow = 6
+	// importReportTTL time the failed feeds of a report are kept for its retry buttons, as long as buttons stay valid
+	importReportTTL = 48 * time.Hour
+
+	ImportRetryButtonUnique = "import_retry_btn"
+)
+
+type importReportKey struct {
+	chatID    int64
+	messageID int
+}
+
+type importReport struct {
+	outlines  []opml.FeedOutline
+	createdAt time.Time
+}
+
+// ImportReports keeps the failed feeds of the import reports for their retry buttons,
+// a report only links the first feeds and not their folders or titles
+type ImportReports struct {
+	mu      sync.Mutex
+	reports map[importReportKey]*importReport
+}
+
+func NewImportReports() *ImportReports {
+	return &ImportReports{reports: make(map[importReportKey]*importReport)}
+}
+
+// put keeps the failed feeds of a report message, dropping the reports whose buttons expired
+func (r *ImportReports) put(m *tb.Message, outlines []opml.FeedOutline) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, report := range r.reports {
+		if time.Since(report.createdAt) > importReportTTL {
+			delete(r.reports, key)
+		}
+	}
+	r.reports[importReportKey{m.Chat.ID, m.ID}] = &importReport{outlines: outlines, createdAt: time.Now()}
+}
+
+// get gets the failed feeds of a report message, false if they are not kept, after a restart for one
+func (r *ImportReports) get(m *tb.Message) ([]opml.FeedOutline, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report, ok := r.reports[importReportKey{m.Chat.ID, m.ID}]
+	if !ok {
+		return nil, false
+	}
+	return report.outlines, true
+}
+
+func (r *ImportReports) remove(m *tb.Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reports, importReportKey{m.Chat.ID, m.ID})
+}
+
 type OnDocument struct {
-	bot  *tb.Bot
-	core *core.Core
+	bot     *tb.Bot
+	core    *core.Core
+	reports *ImportReports
 }
 
-func NewOnDocument(bot *tb.Bot, core *core.Core) *OnDocument {
+func NewOnDocument(bot *tb.Bot, core *core.Core, reports *ImportReports) *OnDocument {
 	return &OnDocument{
-		bot:  bot,
-		core: core,
+		bot:     bot,
+		core:    core,
+		reports: reports,
 	}
 }
 
@@ -35,112 +109,380 @@ func (o *OnDocument) Description() string {
 	return ""
 }
 
-func (o *OnDocument) getOPML(ctx tb.Context) (*opml.OPML, error) {
-	if !strings.HasSuffix(ctx.Message().Document.FileName, ".opml") {
-		return nil, errors.New("Please send the correct OPML file")
+// getOutlines reads the feeds of a subscription export with the importer matching the file
+func (o *OnDocument) getOutlines(ctx tb.Context) ([]opml.FeedOutline, error) {
+	document := ctx.Message().Document
+	fileRead, err := o.bot.File(&document.File)
+	if err != nil {
+		return nil, errors.New(tr(ctx, "import.file_failed"))
 	}
-
-	fileRead, err := o.bot.File(&ctx.Message().Document.File)
+	defer fileRead.Close()
+	data, err := io.ReadAll(fileRead)
 	if err != nil {
-		return nil, errors.New("Failed to fetch file attachment")
+		return nil, errors.New(tr(ctx, "import.file_failed"))
 	}
 
-	opmlFile, err := opml.ReadOPML(fileRead)
+	fileImporter, err := importer.Find(document.FileName, data)
 	if err != nil {
-		log.Errorf("parser opml failed, %v", err)
-		return nil, errors.New("Failed to fetch file attachment")
+		if strings.HasSuffix(document.FileName, ".json") {
+			return nil, errors.New(tr(ctx, "restore.hint"))
+		}
+		return nil, errors.New(tr(ctx, "import.invalid_file"))
 	}
-	return opmlFile, nil
+	outlines, err := fileImporter.Read(data)
+	if err != nil {
+		log.Errorf("read %s file failed, %v", fileImporter.Name(), err)
+		return nil, errors.New(tr(ctx, "import.file_failed"))
+	}
+	return outlines, nil
 }
 
 func (o *OnDocument) Handle(ctx tb.Context) error {
-	opmlFile, err := o.getOPML(ctx)
+	outlines, err := o.getOutlines(ctx)
 	if err != nil {
 		return ctx.Reply(err.Error())
 	}
-	userID := ctx.Chat().ID
+	outlines = mergeOutlines(outlines)
+	subscriberChat := ctx.Chat()
 	v := ctx.Get(session.StoreKeyMentionChat.String())
 	if mentionChat, ok := v.(*tb.Chat); ok && mentionChat != nil {
-		userID = mentionChat.ID
+		subscriberChat = mentionChat
 	}
+	userID := subscriberChat.ID
+	registerChat(ctx, o.core, subscriberChat)
 
-	outlines, _ := opmlFile.GetFlattenOutlines()
-	var failImportList = make([]opml.Outline, len(outlines))
-	failIndex := 0
-	var successImportList = make([]opml.Outline, len(outlines))
-	successIndex := 0
-	wg := &sync.WaitGroup{}
-	for _, outline := range outlines {
-		outline := outline
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			source, err := o.core.CreateSource(context.Background(), outline.XMLURL)
-			if err != nil {
-				failImportList[failIndex] = outline
-				failIndex++
+	status, err := o.bot.Reply(ctx.Message(), tr(ctx, "import.progress", 0, len(outlines), 0))
+	if err != nil {
+		return err
+	}
+
+	var done, failed int64
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		// edit the status at an interval, editing after every feed would hit the rate limits
+		ticker := time.NewTicker(importProgressInterval)
+		defer ticker.Stop()
+		var lastDone int64
+		for {
+			select {
+			case <-stop:
 				return
+			case <-ticker.C:
+				d, f := atomic.LoadInt64(&done), atomic.LoadInt64(&failed)
+				if d == lastDone {
+					continue
+				}
+				lastDone = d
+				if _, err := o.bot.Edit(status, tr(ctx, "import.progress", d, len(outlines), f)); err != nil {
+					log.Errorf("edit import progress failed, %v", err)
+				}
 			}
+		}
+	}()
 
-			err = o.core.AddSubscription(context.Background(), userID, source.ID)
+	errs := importFeeds(
+		o.core, userID, outlines, outlineApplier(o.core), func(err error) {
 			if err != nil {
-				if err == core.ErrSubscriptionExist {
-					successImportList[successIndex] = outline
-					successIndex++
-				} else {
-					failImportList[failIndex] = outline
-					failIndex++
-				}
-				return
+				atomic.AddInt64(&failed, 1)
+			}
+			atomic.AddInt64(&done, 1)
+		},
+	)
+	close(stop)
+	<-finished
+
+	var succeeded, failedOutlines []opml.FeedOutline
+	for i, err := range errs {
+		if err != nil {
+			failedOutlines = append(failedOutlines, outlines[i])
+			continue
+		}
+		succeeded = append(succeeded, outlines[i])
+	}
+
+	if _, err := o.bot.Edit(
+		status, tr(ctx, "import.summary", len(succeeded), len(failedOutlines)), &tb.SendOptions{ParseMode: tb.ModeHTML},
+	); err != nil {
+		log.Errorf("edit import summary failed, %v", err)
+	}
+	if len(succeeded) > 0 {
+		var msg strings.Builder
+		msg.WriteString(tr(ctx, "import.succeeded"))
+		for i, line := range succeeded {
+			msg.WriteString(outlineLine(i+1, line.Outline))
+		}
+		if err := sendChunks(ctx, msg.String()); err != nil {
+			log.Errorf("send import report failed, %v", err)
+		}
+	}
+	if len(failedOutlines) == 0 {
+		return nil
+	}
+	text, markup := importFailedReport(ctx, userID, failedOutlines)
+	report, err := o.bot.Send(
+		ctx.Chat(), text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML, ReplyMarkup: markup},
+	)
+	if err != nil {
+		return err
+	}
+	o.reports.put(report, failedOutlines)
+	return nil
+}
+
+// mergeOutlines merges the outlines of the same feed, a feed filed in several folders keeps the tags of all of them.
+// The outlines are imported at the same time and would otherwise create the feed and its subscription twice
+func mergeOutlines(outlines []opml.FeedOutline) []opml.FeedOutline {
+	merged := make([]opml.FeedOutline, 0, len(outlines))
+	index := make(map[string]int, len(outlines))
+	for _, outline := range outlines {
+		i, ok := index[outline.XMLURL]
+		if !ok {
+			index[outline.XMLURL] = len(merged)
+			outline.Tags = append([]string(nil), outline.Tags...)
+			merged = append(merged, outline)
+			continue
+		}
+		if merged[i].Name() == "" {
+			merged[i].Outline = outline.Outline
+		}
+		for _, tag := range outline.Tags {
+			if !containsString(merged[i].Tags, tag) {
+				merged[i].Tags = append(merged[i].Tags, tag)
 			}
+		}
+	}
+	return merged
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
 
-			log.Infof("%d subscribe [%d]%s %s", ctx.Chat().ID, source.ID, source.Title, source.Link)
-			successImportList[successIndex] = outline
-			successIndex++
-			return
+// importFeeds subscribes a chat to feeds with a bounded number of workers, returning the error of every feed.
+// The outlines must not repeat a feed, see mergeOutlines.
+// Feeds the chat already subscribed count as imported, onDone is called as each feed finishes
+func importFeeds(
+	appCore *core.Core, userID int64, outlines []opml.FeedOutline,
+	onSubscribed func(userID int64, source *model.Source, outline opml.FeedOutline), onDone func(err error),
+) []error {
+	errs := make([]error, len(outlines))
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < importWorkerCount; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = importFeed(appCore, userID, outlines[i], onSubscribed)
+				onDone(errs[i])
+			}
 		}()
 	}
+	for i := range outlines {
+		jobs <- i
+	}
+	close(jobs)
 	wg.Wait()
+	return errs
+}
+
+func importFeed(
+	appCore *core.Core, userID int64, outline opml.FeedOutline,
+	onSubscribed func(userID int64, source *model.Source, outline opml.FeedOutline),
+) error {
+	source, err := appCore.CreateSource(context.Background(), outline.XMLURL)
+	if err != nil {
+		return err
+	}
+
+	err = appCore.AddSubscription(context.Background(), userID, source.ID)
+	if err != nil {
+		if err == core.ErrSubscriptionExist {
+			return nil
+		}
+		return err
+	}
 
+	log.Infof("%d subscribe [%d]%s %s", userID, source.ID, source.Title, source.Link)
+	if onSubscribed != nil {
+		onSubscribed(userID, source, outline)
+	}
+	return nil
+}
+
+// outlineLine renders an imported feed as a numbered line of a report
+func outlineLine(n int, line opml.Outline) string {
+	name := line.Name()
+	if name == "" {
+		name = line.XMLURL
+	}
+	return fmt.Sprintf("[%d] <a href=\"%s\">%s</a>\n", n, html.EscapeString(line.XMLURL), html.EscapeString(name))
+}
+
+// importFailedReport lists the feeds that failed to import with buttons to retry them.
+// The retry buttons find the feeds by the links of the report, only the first feeds are listed to fit one message
+func importFailedReport(ctx tb.Context, userID int64, outlines []opml.FeedOutline) (string, *tb.ReplyMarkup) {
 	var msg strings.Builder
-	msg.WriteString(fmt.Sprintf("<b>Number of successfully imported sources: %d, numbers of failed sources imported: %d</b>\n", successIndex, failIndex))
-	if successIndex != 0 {
-		msg.WriteString("<b>Successfully imported all subscription sources below:</b>\n")
-		for i := 0; i < successIndex; i++ {
-			line := successImportList[i]
-			if line.Text != "" {
-				msg.WriteString(
-					fmt.Sprintf("[%d] <a href=\"%s\">%s</a>\n", i+1, line.XMLURL, line.Text),
-				)
-			} else {
-				msg.WriteString(fmt.Sprintf("[%d] %s\n", i+1, line.XMLURL))
-			}
+	msg.WriteString(tr(ctx, "import.failed"))
+	listed := outlines
+	if len(listed) > importRetryLimit {
+		listed = listed[:importRetryLimit]
+	}
+	var rows [][]tb.InlineButton
+	var row []tb.InlineButton
+	for i, line := range listed {
+		msg.WriteString(outlineLine(i+1, line.Outline))
+		row = append(row, importRetryButton(userID, i+1, fmt.Sprintf("%d", i+1)))
+		if len(row) == importRetryButtonsPerRow {
+			rows = append(rows, row)
+			row = nil
 		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	if len(outlines) > len(listed) {
+		msg.WriteString(tr(ctx, "import.failed_more", len(outlines)-len(listed)))
+	}
+	rows = append(rows, []tb.InlineButton{importRetryButton(userID, 0, tr(ctx, "import.retry_all"))})
+	return msg.String(), &tb.ReplyMarkup{InlineKeyboard: rows}
+}
 
-		msg.WriteString("\n")
+// importRetryButton retries the nth feed of a failed import report, 0 for all of them
+func importRetryButton(userID int64, n int, text string) tb.InlineButton {
+	return tb.InlineButton{
+		Unique: ImportRetryButtonUnique,
+		Text:   text,
+		Data:   session.Marshal(&session.Attachment{UserId: userID, Value: int32(n)}),
 	}
+}
 
-	if failIndex != 0 {
-		msg.WriteString("<b>Failed to import all subscription sources below:</b>\n")
-		for i := 0; i < failIndex; i++ {
-			line := failImportList[i]
-			if line.Text != "" {
-				msg.WriteString(fmt.Sprintf("[%d] <a href=\"%s\">%s</a>\n", i+1, line.XMLURL, line.Text))
-			} else {
-				msg.WriteString(fmt.Sprintf("[%d] %s\n", i+1, line.XMLURL))
+// outlineApplier keeps the folders of an imported feed as tags and its outline name as the subscription title
+func outlineApplier(appCore *core.Core) func(userID int64, source *model.Source, outline opml.FeedOutline) {
+	return func(userID int64, source *model.Source, outline opml.FeedOutline) {
+		if len(outline.Tags) > 0 {
+			if err := appCore.SetSubscriptionTag(context.Background(), userID, source.ID, outline.Tags); err != nil {
+				log.Errorf("set imported subscription %d %d tags failed, %v", userID, source.ID, err)
 			}
 		}
+		if name := strings.TrimSpace(outline.Name()); name != "" && name != source.Title {
+			if err := appCore.SetSubscriptionTitle(context.Background(), userID, source.ID, name); err != nil {
+				log.Errorf("set imported subscription %d %d title failed, %v", userID, source.ID, err)
+			}
+		}
+	}
+}
+
+func (o *OnDocument) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
 
+// ImportRetryButton imports the feeds of a failed import report again
+type ImportRetryButton struct {
+	core    *core.Core
+	reports *ImportReports
+}
+
+func NewImportRetryButton(core *core.Core, reports *ImportReports) *ImportRetryButton {
+	return &ImportRetryButton{core: core, reports: reports}
+}
+
+func (b *ImportRetryButton) CallbackUnique() string {
+	return "\f" + ImportRetryButtonUnique
+}
+
+func (b *ImportRetryButton) Description() string {
+	return ""
+}
+
+func (b *ImportRetryButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
 
-	return ctx.Reply(
-		msg.String(), &tb.SendOptions{
-			DisableWebPagePreview: true,
-			ParseMode:             tb.ModeHTML,
-		},
+	// the import may have been for a channel managed from this chat
+	userID := attachData.GetUserId()
+	if userID != c.Message.Chat.ID {
+		subscriberChat, err := ctx.Bot().ChatByID(userID)
+		if err != nil || !chat.IsChatAdmin(ctx.Bot(), subscriberChat, c.Sender.ID) {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+		}
+	}
+
+	outlines, ok := b.reports.get(c.Message)
+	if !ok {
+		// the report was made before a restart, only its listed feeds can be retried, without their folders
+		outlines = reportOutlines(c.Message)
+	}
+	n := int(attachData.GetValue())
+	if n < 0 || n > len(outlines) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	retry := outlines
+	if n > 0 {
+		retry = outlines[n-1 : n]
+	}
+	if err := ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "import.retrying", len(retry))}); err != nil {
+		log.Errorf("respond import retry failed, %v", err)
+	}
+
+	errs := importFeeds(b.core, userID, retry, outlineApplier(b.core), func(error) {})
+	imported := make(map[string]bool)
+	for i, err := range errs {
+		if err == nil {
+			imported[retry[i].XMLURL] = true
+		}
+	}
+	// the report keeps the feeds still failing in their order
+	var remaining []opml.FeedOutline
+	for _, outline := range outlines {
+		if !imported[outline.XMLURL] {
+			remaining = append(remaining, outline)
+		}
+	}
+
+	if len(remaining) == 0 {
+		b.reports.remove(c.Message)
+		_, err = ctx.Bot().Edit(c.Message, tr(ctx, "import.retry_done"))
+		return err
+	}
+	text, markup := importFailedReport(ctx, userID, remaining)
+	report, err := ctx.Bot().Edit(
+		c.Message, text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML, ReplyMarkup: markup},
 	)
+	if err != nil {
+		return err
+	}
+	b.reports.put(report, remaining)
+	return nil
 }
 
-func (o *OnDocument) Middlewares() []tb.MiddlewareFunc {
+// reportOutlines gets the feeds listed in an import report from the links of the message
+func reportOutlines(m *tb.Message) []opml.FeedOutline {
+	var outlines []opml.FeedOutline
+	for _, entity := range m.Entities {
+		if entity.Type != tb.EntityTextLink {
+			continue
+		}
+		outlines = append(
+			outlines, opml.FeedOutline{Outline: opml.Outline{Text: m.EntityText(entity), XMLURL: entity.URL}},
+		)
+	}
+	return outlines
+}
+
+func (b *ImportRetryButton) Middlewares() []tb.MiddlewareFunc {
 	return nil
 }
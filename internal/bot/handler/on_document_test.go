@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+	"github.com/andatoshiki/toshiki-rssbot/internal/opml"
+	"github.com/andatoshiki/toshiki-rssbot/internal/storage/mock"
+)
+
+func feedOutline(url, title string, tags ...string) opml.FeedOutline {
+	return opml.FeedOutline{Outline: opml.Outline{Title: title, XMLURL: url}, Tags: tags}
+}
+
+func TestMergeOutlines(t *testing.T) {
+	got := mergeOutlines(
+		[]opml.FeedOutline{
+			feedOutline("https://a.example/feed", "", "news"),
+			feedOutline("https://b.example/feed", "B", "tech"),
+			feedOutline("https://a.example/feed", "A", "tech", "news"),
+		},
+	)
+	assert.Equal(
+		t, []opml.FeedOutline{
+			feedOutline("https://a.example/feed", "A", "news", "tech"),
+			feedOutline("https://b.example/feed", "B", "tech"),
+		}, got,
+	)
+}
+
+func TestImportFeeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	sourceStorage := mock.NewMockSource(ctrl)
+	subscriptionStorage := mock.NewMockSubscription(ctrl)
+	appCore := core.NewCore(
+		nil, nil, sourceStorage, subscriptionStorage, nil, nil, nil, nil, nil, nil, nil, nil,
+	)
+
+	userID := int64(1)
+	urls := []string{
+		"https://a.example/feed", "https://b.example/feed", "https://c.example/feed",
+		"https://d.example/feed", "https://e.example/feed", "https://f.example/feed",
+	}
+	failedURL := "https://failed.example/feed"
+	var outlines []opml.FeedOutline
+	for _, url := range urls {
+		outlines = append(outlines, feedOutline(url, "", "folder1"), feedOutline(url, "", "folder2"))
+	}
+	outlines = append(outlines, feedOutline(failedURL, ""))
+	outlines = mergeOutlines(outlines)
+	assert.Len(t, outlines, len(urls)+1)
+
+	for i, url := range urls {
+		source := &model.Source{ID: uint(i + 1), Link: url}
+		sourceStorage.EXPECT().GetSourceByURL(gomock.Any(), url).Return(source, nil).Times(1)
+		subscriptionStorage.EXPECT().SubscriptionExist(gomock.Any(), userID, source.ID).Return(false, nil).Times(1)
+	}
+	sourceStorage.EXPECT().GetSourceByURL(gomock.Any(), failedURL).Return(nil, errors.New("err")).Times(1)
+	// every feed is subscribed once although it is listed in two folders
+	subscriptionStorage.EXPECT().AddSubscription(gomock.Any(), gomock.Any()).Return(nil).Times(len(urls))
+
+	var mu sync.Mutex
+	subscribed := make(map[string][]string)
+	var done, failed int64
+	errs := importFeeds(
+		appCore, userID, outlines, func(_ int64, source *model.Source, outline opml.FeedOutline) {
+			mu.Lock()
+			defer mu.Unlock()
+			subscribed[source.Link] = outline.Tags
+		}, func(err error) {
+			if err != nil {
+				atomic.AddInt64(&failed, 1)
+			}
+			atomic.AddInt64(&done, 1)
+		},
+	)
+
+	assert.Len(t, errs, len(outlines))
+	assert.Equal(t, int64(len(outlines)), atomic.LoadInt64(&done))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&failed))
+	for i, outline := range outlines {
+		if outline.XMLURL == failedURL {
+			assert.Error(t, errs[i])
+			continue
+		}
+		assert.Nil(t, errs[i])
+		assert.Equal(t, []string{"folder1", "folder2"}, subscribed[outline.XMLURL])
+	}
+	assert.Len(t, subscribed, len(urls))
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+// OnMigration moves the subscriptions of a group to its new chat when it is upgraded to a supergroup
+type OnMigration struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewOnMigration(bot *tb.Bot, core *core.Core) *OnMigration {
+	return &OnMigration{bot: bot, core: core}
+}
+
+func (o *OnMigration) Command() string {
+	return tb.OnMigration
+}
+
+func (o *OnMigration) Description() string {
+	return ""
+}
+
+func (o *OnMigration) Handle(ctx tb.Context) error {
+	from, to := ctx.Migration()
+	moved, err := o.core.MigrateChat(context.Background(), from, to)
+	if err != nil {
+		log.Errorf("migrate chat %d to %d failed, %v", from, to, err)
+		notifyAdmins(o.bot, o.core, "admin.chat_migrate_failed", ctx.Chat().Title, from, to)
+		return err
+	}
+	log.Infof("migrated chat %d to %d, %d subscriptions moved", from, to, moved)
+
+	chat := *ctx.Chat()
+	chat.ID = to
+	chat.Type = tb.ChatSuperGroup
+	registerChat(ctx, o.core, &chat)
+	notifyAdmins(o.bot, o.core, "admin.chat_migrated", chat.Title, from, to, moved)
+	return nil
+}
+
+func (o *OnMigration) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
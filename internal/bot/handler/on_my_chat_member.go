@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+// OnMyChatMember follows the membership of the bot, the subscriptions of a group or channel are removed
+// once the bot is kicked from it
+type OnMyChatMember struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewOnMyChatMember(bot *tb.Bot, core *core.Core) *OnMyChatMember {
+	return &OnMyChatMember{bot: bot, core: core}
+}
+
+func (o *OnMyChatMember) Command() string {
+	return tb.OnMyChatMember
+}
+
+func (o *OnMyChatMember) Description() string {
+	return ""
+}
+
+func (o *OnMyChatMember) Handle(ctx tb.Context) error {
+	update := ctx.ChatMember()
+	if update == nil || update.NewChatMember == nil {
+		return nil
+	}
+	chat := update.Chat
+
+	switch update.NewChatMember.Role {
+	case tb.Kicked, tb.Left:
+		if chat.Type == tb.ChatPrivate {
+			// the user blocked the bot, the subscriptions are removed on the next failed push
+			if err := o.core.ChatLeft(context.Background(), chat.ID); err != nil {
+				log.Errorf("record chat %d left failed, %v", chat.ID, err)
+			}
+			return nil
+		}
+
+		removed, err := o.core.ChatRemoved(context.Background(), chat.ID)
+		if err != nil {
+			log.Errorf("clean up chat %d failed, %v", chat.ID, err)
+			return err
+		}
+		log.Infof("bot removed from chat %d, %d subscriptions removed", chat.ID, removed)
+		notifyAdmins(o.bot, o.core, "admin.chat_removed", chat.Title, chat.ID, removed)
+	default:
+		registerChat(ctx, o.core, chat)
+	}
+	return nil
+}
+
+func (o *OnMyChatMember) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/message"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const (
+	FeedLinkChannelButtonUnique = "feed_link_channel_btn"
+	FeedLinkPreviewButtonUnique = "feed_link_preview_btn"
+)
+
+// OnText offers to subscribe to the feed of a link sent without a command
+type OnText struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewOnText(bot *tb.Bot, core *core.Core) *OnText {
+	return &OnText{bot: bot, core: core}
+}
+
+func (o *OnText) Command() string {
+	return tb.OnText
+}
+
+func (o *OnText) Description() string {
+	return ""
+}
+
+func (o *OnText) Handle(ctx tb.Context) error {
+	// unknown commands end up here as well
+	if strings.HasPrefix(ctx.Message().Text, "/") {
+		return nil
+	}
+	link := message.URLFromMessage(ctx.Message())
+	if link == "" {
+		return nil
+	}
+
+	feed, feedURL, err := o.core.FeedParser().DiscoverFromURL(context.Background(), link)
+	if err != nil {
+		// most links are not feeds, stay silent
+		log.Debugf("discover feed of %s failed, %v", link, err)
+		return nil
+	}
+
+	data := session.Marshal(&session.Attachment{UserId: ctx.Chat().ID})
+	rows := [][]tb.InlineButton{
+		{{Unique: PreviewSubscribeButtonUnique, Text: tr(ctx, "feedlink.subscribe_here"), Data: data}},
+	}
+	// channels are only offered in private, the button list would expose them to the group
+	if ctx.Chat().Type == tb.ChatPrivate {
+		for _, channel := range o.administeredChannels(ctx) {
+			name := channel.Title
+			if channel.Username != "" {
+				name = "@" + channel.Username
+			}
+			rows = append(
+				rows, []tb.InlineButton{
+					{
+						Unique: FeedLinkChannelButtonUnique,
+						Text:   tr(ctx, "feedlink.subscribe_channel", name),
+						Data:   session.Marshal(&session.Attachment{UserId: channel.ID}),
+					},
+				},
+			)
+		}
+	}
+	rows = append(rows, []tb.InlineButton{{Unique: FeedLinkPreviewButtonUnique, Text: tr(ctx, "feedlink.preview"), Data: data}})
+
+	return ctx.Reply(
+		tr(ctx, "feedlink.found", html.EscapeString(feedURL), html.EscapeString(feed.Title)),
+		&tb.SendOptions{
+			DisableWebPagePreview: true,
+			ParseMode:             tb.ModeHTML,
+			ReplyMarkup:           &tb.ReplyMarkup{InlineKeyboard: rows},
+		},
+	)
+}
+
+// administeredChannels gets the channels the sender registered that both the sender and the bot still administer,
+// checking every channel the bot knows would make a request per channel for each link
+func (o *OnText) administeredChannels(ctx tb.Context) []*tb.Chat {
+	channels, err := o.core.GetUserChannels(context.Background(), ctx.Sender().ID)
+	if err != nil {
+		log.Errorf("get channels failed, %v", err)
+		return nil
+	}
+
+	var administered []*tb.Chat
+	for _, channel := range channels {
+		channelChat := &tb.Chat{ID: channel.ID, Type: tb.ChatChannel, Title: channel.Title, Username: channel.Username}
+		hasPrivilege, err := hasChannelPrivilege(ctx, channelChat, ctx.Sender().ID, o.bot.Me.ID)
+		if err != nil || !hasPrivilege {
+			continue
+		}
+		administered = append(administered, channelChat)
+	}
+	return administered
+}
+
+func (o *OnText) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// FeedLinkChannelButton subscribes a channel to the feed linked in the pressed message
+type FeedLinkChannelButton struct {
+	core *core.Core
+}
+
+func NewFeedLinkChannelButton(core *core.Core) *FeedLinkChannelButton {
+	return &FeedLinkChannelButton{core: core}
+}
+
+func (b *FeedLinkChannelButton) CallbackUnique() string {
+	return "\f" + FeedLinkChannelButtonUnique
+}
+
+func (b *FeedLinkChannelButton) Description() string {
+	return ""
+}
+
+func (b *FeedLinkChannelButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	feedURL := message.URLFromMessage(c.Message)
+	if feedURL == "" {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	channelChat, err := ctx.Bot().ChatByID(attachData.GetUserId())
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "channel.fetch_failed")})
+	}
+	hasPrivilege, err := hasChannelPrivilege(ctx, channelChat, c.Sender.ID, ctx.Bot().Me.ID)
+	if err != nil || !hasPrivilege {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "sub.channel_no_privilege")})
+	}
+
+	source, err := b.core.CreateSource(context.Background(), feedURL)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "sub.source_failed", err)})
+	}
+
+	log.Infof("%d subscribe [%d]%s %s", channelChat.ID, source.ID, source.Title, source.Link)
+	if err := b.core.AddSubscription(context.Background(), channelChat.ID, source.ID); err != nil {
+		if err == core.ErrSubscriptionExist {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "sub.exist")})
+		}
+		log.Errorf("add subscription user %d source %d failed %v", channelChat.ID, source.ID, err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "sub.failed")})
+	}
+	registerChat(ctx, b.core, channelChat)
+
+	if err := ctx.Send(
+		tr(ctx, "sub.success", source.ID, source.Title, source.Link),
+		&tb.SendOptions{
+			DisableWebPagePreview: true,
+			ParseMode:             tb.ModeMarkdown,
+		},
+	); err != nil {
+		log.Errorf("send subscription result failed, %v", err)
+	}
+	return ctx.Respond()
+}
+
+func (b *FeedLinkChannelButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// FeedLinkPreviewButton previews the feed linked in the pressed message
+type FeedLinkPreviewButton struct {
+	core *core.Core
+}
+
+func NewFeedLinkPreviewButton(core *core.Core) *FeedLinkPreviewButton {
+	return &FeedLinkPreviewButton{core: core}
+}
+
+func (b *FeedLinkPreviewButton) CallbackUnique() string {
+	return "\f" + FeedLinkPreviewButtonUnique
+}
+
+func (b *FeedLinkPreviewButton) Description() string {
+	return ""
+}
+
+func (b *FeedLinkPreviewButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	feedURL := message.URLFromMessage(c.Message)
+	if feedURL == "" {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	if err := ctx.Respond(); err != nil {
+		log.Errorf("respond preview callback failed, %v", err)
+	}
+	return sendFeedPreview(ctx, b.core, feedURL)
+}
+
+func (b *FeedLinkPreviewButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
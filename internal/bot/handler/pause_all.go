@@ -3,11 +3,14 @@ package handler
 import (
 	"context"
 	"fmt"
+	"html"
+	"strings"
 
 	tb "gopkg.in/telebot.v3"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 )
 
 type PauseAll struct {
@@ -23,7 +26,7 @@ func (p *PauseAll) Command() string {
 }
 
 func (p *PauseAll) Description() string {
-	return "Pause and terminate fetching all subscription updates"
+	return "Pause fetching all subscription updates, /pauseall #tag to pause a tag"
 }
 
 func (p *PauseAll) Handle(ctx tb.Context) error {
@@ -38,21 +41,53 @@ func (p *PauseAll) Handle(ctx tb.Context) error {
 		}
 	}
 
-	source, err := p.core.GetUserSubscribedSources(context.Background(), subscribeUserID)
+	// a #tag pauses only the subscriptions with the tag
+	tag := tagFromPayload(ctx.Message().Payload)
+	source, err := p.core.GetUserTagSubscribedSources(context.Background(), subscribeUserID, tag)
 	if err != nil {
-		return ctx.Reply("Internal system error")
+		return ctx.Reply(tr(ctx, "common.internal_error"))
+	}
+	if tag != "" && len(source) == 0 {
+		return ctx.Reply(tr(ctx, "tag.no_subscriptions", tag))
 	}
 
+	var skipped []*model.Source
 	for _, s := range source {
-		err := p.core.DisableSourceUpdate(context.Background(), s.ID)
+		if tag == "" {
+			if err := p.core.DisableSourceUpdate(context.Background(), s.ID); err != nil {
+				return ctx.Reply(tr(ctx, "pauseall.failed"))
+			}
+			continue
+		}
+
+		// the tag is this chat's own, the sources other chats subscribe to keep running for them
+		paused, err := p.core.DisableUnsharedSourceUpdate(context.Background(), s.ID)
 		if err != nil {
-			return ctx.Reply("Failed to pause")
+			return ctx.Reply(tr(ctx, "pauseall.failed"))
+		}
+		if !paused {
+			skipped = append(skipped, s)
+		}
+	}
+
+	if tag != "" {
+		// tags are often joined with _, which Markdown reads as italics
+		var msg strings.Builder
+		msg.WriteString(tr(ctx, "pauseall.tag_success", len(source)-len(skipped), html.EscapeString(tag)))
+		if len(skipped) > 0 {
+			msg.WriteString("\n" + tr(ctx, "pauseall.pause_skipped", len(skipped)))
+			for i, s := range skipped {
+				msg.WriteString(
+					fmt.Sprintf("[%d] <a href=\"%s\">%s</a>\n", i+1, html.EscapeString(s.Link), html.EscapeString(s.Title)),
+				)
+			}
 		}
+		return ctx.Send(msg.String(), &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML})
 	}
 
-	reply := "All subscription updates have been paused and terminated"
+	reply := tr(ctx, "pauseall.success")
 	if channelChat != nil {
-		reply = fmt.Sprintf("All subscriptions of channel [%s](https://t.me/%s) have been completely paused and terminated", channelChat.Title, channelChat.Username)
+		reply = tr(ctx, "pauseall.channel_success", channelChat.Title, channelChat.Username)
 	}
 	return ctx.Send(
 		reply, &tb.SendOptions{
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/message"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/preview"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const (
+	// previewItemCount number of latest items rendered by /preview
+	previewItemCount = 3
+
+	PreviewSubscribeButtonUnique = "preview_subscribe_btn"
+)
+
+// Preview renders the latest items of a feed the way they would be pushed, without subscribing
+type Preview struct {
+	core *core.Core
+}
+
+func NewPreview(core *core.Core) *Preview {
+	return &Preview{core: core}
+}
+
+func (p *Preview) Command() string {
+	return "/preview"
+}
+
+func (p *Preview) Description() string {
+	return "Preview a feed before subscribing"
+}
+
+func (p *Preview) Handle(ctx tb.Context) error {
+	feedURL := message.URLFromMessage(ctx.Message())
+	if feedURL == "" {
+		return ctx.Reply(tr(ctx, "preview.usage", p.Command()))
+	}
+	return sendFeedPreview(ctx, p.core, feedURL)
+}
+
+func (p *Preview) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// sendFeedPreview sends the latest items of a feed rendered with the message template,
+// followed by the feed metadata with a subscribe button
+func sendFeedPreview(ctx tb.Context, appCore *core.Core, feedURL string) error {
+	feed, err := appCore.FeedParser().ParseFromURL(context.Background(), feedURL)
+	if err != nil {
+		log.Errorf("preview feed %s failed, %v", feedURL, err)
+		return ctx.Reply(tr(ctx, "preview.failed", err))
+	}
+
+	items := latestItems(feed.Items, previewItemCount)
+	for _, item := range items {
+		desc := item.Content
+		if desc == "" {
+			desc = item.Description
+		}
+		tpldata := &config.TplData{
+			SourceTitle:  feed.Title,
+			ContentTitle: strings.TrimSpace(item.Title),
+			RawLink:      item.Link,
+			PreviewText:  preview.TrimDescription(desc, config.PreviewText),
+		}
+		msg, err := tpldata.Render(config.MessageMode)
+		if err != nil {
+			log.Errorf("render preview item of %s failed, %v", feedURL, err)
+			return ctx.Reply(tr(ctx, "common.internal_error"))
+		}
+		if err := ctx.Send(
+			msg, &tb.SendOptions{
+				DisableWebPagePreview: config.DisableWebPagePreview,
+				ParseMode:             config.MessageMode,
+			},
+		); err != nil {
+			log.Errorf("send preview item of %s failed, %v", feedURL, err)
+		}
+	}
+
+	lastPublished := tr(ctx, "preview.unknown")
+	if t := feedLastPublished(feed); !t.IsZero() {
+		lastPublished = t.Format("2006-01-02 15:04:05")
+	}
+	text := tr(
+		ctx, "preview.summary", html.EscapeString(feedURL), html.EscapeString(feed.Title), feedType(feed),
+		len(feed.Items), lastPublished,
+	)
+	subscribeKey := tb.InlineButton{
+		Unique: PreviewSubscribeButtonUnique,
+		Text:   tr(ctx, "preview.subscribe"),
+		Data:   session.Marshal(&session.Attachment{UserId: ctx.Chat().ID}),
+	}
+	return ctx.Reply(
+		text, &tb.SendOptions{
+			DisableWebPagePreview: true,
+			ParseMode:             tb.ModeHTML,
+			ReplyMarkup:           &tb.ReplyMarkup{InlineKeyboard: [][]tb.InlineButton{{subscribeKey}}},
+		},
+	)
+}
+
+// latestItems gets the newest items of a feed, items without a date count as the oldest and keep their feed order
+func latestItems(items []*gofeed.Item, count int) []*gofeed.Item {
+	sorted := make([]*gofeed.Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(
+		sorted, func(i, j int) bool {
+			ti, tj := itemTime(sorted[i]), itemTime(sorted[j])
+			if ti.IsZero() || tj.IsZero() {
+				return !ti.IsZero() && tj.IsZero()
+			}
+			return ti.After(tj)
+		},
+	)
+	if len(sorted) > count {
+		sorted = sorted[:count]
+	}
+	return sorted
+}
+
+func itemTime(item *gofeed.Item) time.Time {
+	if item.PublishedParsed != nil {
+		return *item.PublishedParsed
+	}
+	if item.UpdatedParsed != nil {
+		return *item.UpdatedParsed
+	}
+	return time.Time{}
+}
+
+// feedLastPublished gets the date of the newest item, falling back to the date of the feed
+func feedLastPublished(feed *gofeed.Feed) time.Time {
+	var last time.Time
+	for _, item := range feed.Items {
+		if t := itemTime(item); t.After(last) {
+			last = t
+		}
+	}
+	if last.IsZero() && feed.PublishedParsed != nil {
+		last = *feed.PublishedParsed
+	}
+	if last.IsZero() && feed.UpdatedParsed != nil {
+		last = *feed.UpdatedParsed
+	}
+	return last
+}
+
+// feedType describes the detected format of a feed, like RSS 2.0
+func feedType(feed *gofeed.Feed) string {
+	name := strings.ToUpper(feed.FeedType)
+	if feed.FeedType == "atom" {
+		name = "Atom"
+	}
+	if feed.FeedVersion == "" {
+		return name
+	}
+	return fmt.Sprintf("%s %s", name, feed.FeedVersion)
+}
+
+// PreviewSubscribeButton subscribes the chat to the feed linked in the pressed message,
+// attached to /preview replies and to the feeds found in pasted links
+type PreviewSubscribeButton struct {
+	core *core.Core
+}
+
+func NewPreviewSubscribeButton(core *core.Core) *PreviewSubscribeButton {
+	return &PreviewSubscribeButton{core: core}
+}
+
+func (b *PreviewSubscribeButton) CallbackUnique() string {
+	return "\f" + PreviewSubscribeButtonUnique
+}
+
+func (b *PreviewSubscribeButton) Description() string {
+	return ""
+}
+
+func (b *PreviewSubscribeButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	// the feed link is the first link of the preview summary
+	feedURL := message.URLFromMessage(c.Message)
+	if feedURL == "" {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	source, err := b.core.CreateSource(context.Background(), feedURL)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "sub.source_failed", err)})
+	}
+
+	chatID := c.Message.Chat.ID
+	log.Infof("%d subscribe [%d]%s %s", chatID, source.ID, source.Title, source.Link)
+	if err := b.core.AddSubscription(context.Background(), chatID, source.ID); err != nil {
+		if err == core.ErrSubscriptionExist {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "sub.exist")})
+		}
+		log.Errorf("add subscription user %d source %d failed %v", chatID, source.ID, err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "sub.failed")})
+	}
+	registerChat(ctx, b.core, c.Message.Chat)
+
+	if c.Message.TopicMessage {
+		if err := b.core.SetSubscriptionThread(
+			context.Background(), chatID, source.ID, c.Message.ThreadID,
+		); err != nil {
+			log.Errorf("set subscription user %d source %d thread failed %v", chatID, source.ID, err)
+		}
+	}
+
+	if err := ctx.Edit(
+		tr(ctx, "sub.success", source.ID, source.Title, source.Link),
+		&tb.SendOptions{
+			DisableWebPagePreview: true,
+			ParseMode:             tb.ModeMarkdown,
+		},
+	); err != nil {
+		log.Errorf("edit preview message failed, %v", err)
+	}
+	return ctx.Respond()
+}
+
+func (b *PreviewSubscribeButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+// registerChat saves a chat the bot posts to and the sender of the update, failures are only logged
+func registerChat(ctx tb.Context, appCore *core.Core, chat *tb.Chat) {
+	sender := ctx.Sender()
+	record := &model.Chat{
+		ID:       chat.ID,
+		Type:     string(chat.Type),
+		Title:    chat.Title,
+		Username: chat.Username,
+	}
+	if chat.Type == tb.ChatChannel && sender != nil {
+		record.RegisteredBy = sender.ID
+	}
+	if chat.Type == tb.ChatPrivate {
+		record.Title = strings.TrimSpace(chat.FirstName + " " + chat.LastName)
+		if sender != nil && sender.ID == chat.ID {
+			record.LanguageCode = sender.LanguageCode
+		}
+	}
+	if err := appCore.SaveChat(context.Background(), record); err != nil {
+		log.Errorf("save chat %d failed, %v", chat.ID, err)
+	}
+
+	if sender == nil {
+		return
+	}
+	user := &model.User{
+		ID:           sender.ID,
+		Username:     sender.Username,
+		Name:         strings.TrimSpace(sender.FirstName + " " + sender.LastName),
+		LanguageCode: sender.LanguageCode,
+	}
+	if err := appCore.SaveUser(context.Background(), user); err != nil {
+		log.Errorf("save user %d failed, %v", sender.ID, err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 
 	tb "gopkg.in/telebot.v3"
 
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
 )
 
@@ -20,21 +22,24 @@ func (r RemoveAllSubscription) Command() string {
 }
 
 func (r RemoveAllSubscription) Description() string {
-	return "Cancel all existing subscriptions" // Cancel all existing subscriptions
+	return "Cancel all existing subscriptions"
 }
 
 func (r RemoveAllSubscription) Handle(ctx tb.Context) error {
-	reply := "Unsubscribe all subscription feeds for the current user"
+	reply := tr(ctx, "unsuball.confirm")
+	data := session.Marshal(&session.Attachment{UserId: ctx.Chat().ID})
 	var confirmKeys [][]tb.InlineButton
 	confirmKeys = append(
 		confirmKeys, []tb.InlineButton{
 			{
 				Unique: UnSubAllButtonUnique,
-				Text:   "Confirm",
+				Text:   tr(ctx, "common.confirm"),
+				Data:   data,
 			},
 			{
 				Unique: CancelUnSubAllButtonUnique,
-				Text:   "Cancel",
+				Text:   tr(ctx, "common.cancel"),
+				Data:   data,
 			},
 		},
 	)
@@ -67,11 +72,32 @@ func (r *RemoveAllSubscriptionButton) Description() string {
 }
 
 func (r *RemoveAllSubscriptionButton) Handle(ctx tb.Context) error {
-	err := r.core.UnsubscribeAllSource(context.Background(), ctx.Sender().ID)
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	// the subscriptions removed are the ones of the chat /unsuball was sent in
+	userID := attachData.GetUserId()
+	targetChat := c.Message.Chat
+	if userID != targetChat.ID {
+		if targetChat, err = ctx.Bot().ChatByID(userID); err != nil {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+		}
+	}
+	if !chat.IsChatAdmin(ctx.Bot(), targetChat, c.Sender.ID) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	removalID, err := r.core.UnsubscribeAllSource(context.Background(), userID)
 	if err != nil {
-		return ctx.Edit("Failed to unsubscribe")
+		return ctx.Edit(tr(ctx, "unsub.failed"))
 	}
-	return ctx.Edit("Successfully unsubscribed")
+	return ctx.Edit(tr(ctx, "unsub.success"), undoUnsubscribeMarkup(ctx, userID, removalID))
 }
 
 func (r *RemoveAllSubscriptionButton) Middlewares() []tb.MiddlewareFunc {
@@ -94,7 +120,7 @@ func (r *CancelRemoveAllSubscriptionButton) Description() string {
 }
 
 func (r *CancelRemoveAllSubscriptionButton) Handle(ctx tb.Context) error {
-	return ctx.Edit("Cancel current operations")
+	return ctx.Edit(tr(ctx, "common.cancelled"))
 }
 
 func (r *CancelRemoveAllSubscriptionButton) Middlewares() []tb.MiddlewareFunc {
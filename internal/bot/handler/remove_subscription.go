@@ -30,56 +30,70 @@ func (s *RemoveSubscription) Command() string {
 }
 
 func (s *RemoveSubscription) Description() string {
-	return "Unsubscribe RSS feed sources" // Unsubscribe RSS feed sources
+	return "Unsubscribe RSS feed sources"
 }
 
 func (s *RemoveSubscription) removeForChannel(ctx tb.Context, channelName string) error {
 	sourceURL := message.URLFromMessage(ctx.Message())
-	if sourceURL == "" {
-		return ctx.Send("Please utilize `/unsub @channel_id URL` command to unsubscribe if you need to unsubscribe on behalf of your channel")
+	tag := tagFromPayload(ctx.Message().Payload)
+	if sourceURL == "" && tag == "" {
+		return ctx.Send(tr(ctx, "unsub.channel_usage"))
 	}
 
 	channelChat, err := s.bot.ChatByUsername(channelName)
 	if err != nil {
-		return ctx.Reply("Failed to fetch channel information")
+		return ctx.Reply(tr(ctx, "channel.fetch_failed"))
 	}
 
 	if !chat.IsChatAdmin(s.bot, channelChat, ctx.Sender().ID) {
-		return ctx.Reply("Operations executed by users of channel without administrative privileges are not permitted ")
+		return ctx.Reply(tr(ctx, "channel.not_admin"))
+	}
+
+	if sourceURL == "" {
+		return s.removeTag(ctx, channelChat.ID, tag)
 	}
 
 	source, err := s.core.GetSourceByURL(context.Background(), sourceURL)
 	if err != nil {
-		return ctx.Reply("Bot throw an error when fetching subscription information")
+		return ctx.Reply(tr(ctx, "unsub.not_subscribed"))
 	}
 
 	log.Infof("%d for [%d]%s unsubscribe %s", ctx.Chat().ID, source.ID, source.Title, source.Link)
-	if err := s.core.Unsubscribe(context.Background(), channelChat.ID, source.ID); err != nil {
+	removalID, err := s.core.Unsubscribe(context.Background(), channelChat.ID, source.ID)
+	if err != nil {
 		log.Errorf(
 			"%d for [%d]%s unsubscribe %s failed, %v",
 			ctx.Chat().ID, source.ID, source.Title, source.Link, err,
 		)
-		return ctx.Reply("Failed to unsubscribe") // Failed to unsubscribe
+		return ctx.Reply(tr(ctx, "unsub.failed"))
 	}
 	return ctx.Send(
-		fmt.Sprintf(
-			"Successfully unsubscribed [%s](%s) from channel [%s](https://t.me/%s)",
-			source.Title, source.Link, channelChat.Title, channelChat.Username,
-		),
-		&tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeMarkdown},
+		tr(ctx, "unsub.channel_success", source.Title, source.Link, channelChat.Title, channelChat.Username),
+		&tb.SendOptions{
+			DisableWebPagePreview: true,
+			ParseMode:             tb.ModeMarkdown,
+			ReplyMarkup:           undoUnsubscribeMarkup(ctx, channelChat.ID, removalID),
+		},
 	)
 }
 
 func (s *RemoveSubscription) removeForChat(ctx tb.Context) error {
 	sourceURL := message.URLFromMessage(ctx.Message())
+	if tag := tagFromPayload(ctx.Message().Payload); sourceURL == "" && tag != "" {
+		if !chat.IsChatAdmin(s.bot, ctx.Chat(), ctx.Sender().ID) {
+			return ctx.Reply(tr(ctx, "channel.not_admin"))
+		}
+		return s.removeTag(ctx, ctx.Chat().ID, tag)
+	}
+
 	if sourceURL == "" {
 		sources, err := s.core.GetUserSubscribedSources(context.Background(), ctx.Chat().ID)
 		if err != nil {
-			return ctx.Reply("Failed to fetch subscription list")
+			return ctx.Reply(tr(ctx, "subscriptions.fetch_failed"))
 		}
 
 		if len(sources) == 0 {
-			return ctx.Reply("No active subscription currently")
+			return ctx.Reply(tr(ctx, "subscriptions.none"))
 		}
 
 		var unsubFeedItemButtons [][]tb.InlineButton
@@ -100,32 +114,51 @@ func (s *RemoveSubscription) removeForChat(ctx tb.Context) error {
 				},
 			)
 		}
-		return ctx.Reply("Please select the feed sources to unsubscribe", &tb.ReplyMarkup{InlineKeyboard: unsubFeedItemButtons})
+		return ctx.Reply(tr(ctx, "unsub.select"), &tb.ReplyMarkup{InlineKeyboard: unsubFeedItemButtons})
 	}
 
 	if !chat.IsChatAdmin(s.bot, ctx.Chat(), ctx.Sender().ID) {
-		return ctx.Reply("Bot operational executions by non-administrative users are not permitted")
+		return ctx.Reply(tr(ctx, "channel.not_admin"))
 	}
 
 	source, err := s.core.GetSourceByURL(context.Background(), sourceURL)
 	if err != nil {
-		return ctx.Reply("RSS feed not subscribed")
+		return ctx.Reply(tr(ctx, "unsub.not_subscribed"))
 	}
 
 	log.Infof("%d unsubscribe [%d]%s %s", ctx.Chat().ID, source.ID, source.Title, source.Link)
-	if err := s.core.Unsubscribe(context.Background(), ctx.Chat().ID, source.ID); err != nil {
+	removalID, err := s.core.Unsubscribe(context.Background(), ctx.Chat().ID, source.ID)
+	if err != nil {
 		log.Errorf(
 			"%d for [%d]%s unsubscribe %s failed, %v",
 			ctx.Chat().ID, source.ID, source.Title, source.Link, err,
 		)
-		return ctx.Reply("Failed to unsubscribe!")
+		return ctx.Reply(tr(ctx, "unsub.failed"))
 	}
 	return ctx.Send(
-		fmt.Sprintf("[%s](%s) Successfully unsubscribed!", source.Title, source.Link),
-		&tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeMarkdown},
+		tr(ctx, "unsub.chat_success", source.Title, source.Link),
+		&tb.SendOptions{
+			DisableWebPagePreview: true,
+			ParseMode:             tb.ModeMarkdown,
+			ReplyMarkup:           undoUnsubscribeMarkup(ctx, ctx.Chat().ID, removalID),
+		},
 	)
 }
 
+// removeTag unsubscribes a chat from all sources with the tag
+func (s *RemoveSubscription) removeTag(ctx tb.Context, chatID int64, tag string) error {
+	count, removalID, err := s.core.UnsubscribeTag(context.Background(), chatID, tag)
+	if err != nil {
+		log.Errorf("%d unsubscribe tag %s failed after %d sources, %v", chatID, tag, count, err)
+		return ctx.Reply(tr(ctx, "unsub.failed"))
+	}
+	if count == 0 {
+		return ctx.Reply(tr(ctx, "tag.no_subscriptions", tag))
+	}
+	log.Infof("%d unsubscribe tag %s, %d sources", chatID, tag, count)
+	return ctx.Reply(tr(ctx, "unsub.tag_success", count, tag), undoUnsubscribeMarkup(ctx, chatID, removalID))
+}
+
 func (s *RemoveSubscription) Handle(ctx tb.Context) error {
 	mention := message.MentionFromMessage(ctx.Message())
 	if mention != "" {
@@ -160,28 +193,32 @@ func (r *RemoveSubscriptionItemButton) Description() string {
 
 func (r *RemoveSubscriptionItemButton) Handle(ctx tb.Context) error {
 	if ctx.Callback() == nil {
-		return ctx.Edit("Internal errors!")
+		return ctx.Edit(tr(ctx, "common.internal_error"))
 	}
 
 	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
 	if err != nil {
-		return ctx.Edit("Bot throws an internal error!")
+		return ctx.Edit(tr(ctx, "common.internal_error"))
 	}
 
 	userID := attachData.GetUserId()
 	sourceID := uint(attachData.GetSourceId())
 	source, err := r.core.GetSource(context.Background(), sourceID)
 	if err != nil {
-		return ctx.Edit("Failed to unsubscribe ")
+		return ctx.Edit(tr(ctx, "unsub.failed"))
 	}
 
-	if err := r.core.Unsubscribe(context.Background(), userID, sourceID); err != nil {
+	removalID, err := r.core.Unsubscribe(context.Background(), userID, sourceID)
+	if err != nil {
 		log.Errorf("unsubscribe data %s failed, %v", ctx.Callback().Data, err)
-		return ctx.Edit("Unsubscribe processes threw an error!")
+		return ctx.Edit(tr(ctx, "unsub.failed"))
 	}
 
-	rtnMsg := fmt.Sprintf("[%d] <a href=\"%s\">%s</a> Successfully unsubscribed", sourceID, source.Link, source.Title)
-	return ctx.Edit(rtnMsg, &tb.SendOptions{ParseMode: tb.ModeHTML})
+	rtnMsg := tr(ctx, "unsub.item_success", sourceID, source.Link, source.Title)
+	return ctx.Edit(
+		rtnMsg,
+		&tb.SendOptions{ParseMode: tb.ModeHTML, ReplyMarkup: undoUnsubscribeMarkup(ctx, userID, removalID)},
+	)
 }
 
 func (r *RemoveSubscriptionItemButton) Middlewares() []tb.MiddlewareFunc {
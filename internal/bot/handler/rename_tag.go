@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+)
+
+type RenameTag struct {
+	core *core.Core
+}
+
+func NewRenameTag(core *core.Core) *RenameTag {
+	return &RenameTag{core: core}
+}
+
+func (r *RenameTag) Command() string {
+	return "/renametag"
+}
+
+func (r *RenameTag) Description() string {
+	return "Rename a tag, /renametag #old #new"
+}
+
+func (r *RenameTag) Handle(ctx tb.Context) error {
+	tags := tagsFromPayload(ctx.Message().Payload)
+	if len(tags) != 2 {
+		return ctx.Reply(tr(ctx, "renametag.usage"))
+	}
+
+	count, err := r.core.RenameTag(context.Background(), tagOwnerID(ctx), tags[0], tags[1])
+	if err == core.ErrTagExist {
+		return ctx.Reply(tr(ctx, "renametag.exists", tags[1]))
+	}
+	if err != nil {
+		return tagErrorReply(ctx, err, tags[0], "renametag.failed")
+	}
+	return ctx.Reply(tr(ctx, "renametag.success", tags[0], tags[1], count))
+}
+
+func (r *RenameTag) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
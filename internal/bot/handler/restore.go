@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/backup"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const (
+	RestoreButtonUnique = "restore_btn"
+
+	restoreModeMerge   = 1
+	restoreModeReplace = 2
+)
+
+// Restore restores a /backup file, replying to the file without a mode shows what merging or replacing would do
+type Restore struct {
+	core *core.Core
+}
+
+func NewRestore(core *core.Core) *Restore {
+	return &Restore{core: core}
+}
+
+func (r *Restore) Command() string {
+	return "/restore"
+}
+
+func (r *Restore) Description() string {
+	return "Restore a backup file, reply to the file with /restore [merge|replace]"
+}
+
+func (r *Restore) Handle(ctx tb.Context) error {
+	m := ctx.Message()
+	if m.ReplyTo == nil || m.ReplyTo.Document == nil {
+		return ctx.Reply(tr(ctx, "restore.usage"))
+	}
+	chatID := ctx.Chat().ID
+	if mentionChat, ok := session.GetMentionChatFromCtxStore(ctx); ok {
+		chatID = mentionChat.ID
+	}
+
+	mode := 0
+	for _, arg := range strings.Fields(m.Payload) {
+		switch arg {
+		case "merge":
+			mode = restoreModeMerge
+		case "replace":
+			mode = restoreModeReplace
+		}
+	}
+
+	backupData, err := readBackup(ctx, m.ReplyTo.Document)
+	if err != nil {
+		return ctx.Reply(tr(ctx, "restore.invalid_file", err))
+	}
+	if mode != 0 {
+		return restore(ctx, r.core, chatID, backupData, mode, m.ReplyTo)
+	}
+
+	// dry run, the buttons reply to the file so they can read it again
+	summary, err := r.core.Restore(context.Background(), chatID, backupData, true, true)
+	if err != nil {
+		log.Errorf("dry run restore of chat %d failed, %v", chatID, err)
+		return ctx.Reply(tr(ctx, "restore.failed"))
+	}
+	markup := &tb.ReplyMarkup{
+		InlineKeyboard: [][]tb.InlineButton{
+			{
+				restoreButton(chatID, restoreModeMerge, tr(ctx, "restore.merge")),
+				restoreButton(chatID, restoreModeReplace, tr(ctx, "restore.replace")),
+			},
+			{
+				{
+					Unique: CancelUnSubAllButtonUnique,
+					Text:   tr(ctx, "common.cancel"),
+					Data:   session.Marshal(&session.Attachment{UserId: chatID}),
+				},
+			},
+		},
+	}
+	_, err = ctx.Bot().Reply(
+		m.ReplyTo, tr(
+			ctx, "restore.dry_run", len(backupData.Subscriptions), backupData.CreatedAt.Format("2006-01-02 15:04:05"),
+			summary.Added, summary.Updated, summary.Removed,
+		), markup,
+	)
+	return err
+}
+
+func (r *Restore) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+func restoreButton(chatID int64, mode int, text string) tb.InlineButton {
+	return tb.InlineButton{
+		Unique: RestoreButtonUnique,
+		Text:   text,
+		Data:   session.Marshal(&session.Attachment{UserId: chatID, Value: int32(mode)}),
+	}
+}
+
+// readBackup downloads and parses a backup file, a language the bot does not speak falls back to the user language
+func readBackup(ctx tb.Context, document *tb.Document) (*backup.Backup, error) {
+	file, err := ctx.Bot().File(&document.File)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	backupData, err := backup.Read(file)
+	if err != nil {
+		return nil, err
+	}
+	if backupData.Preference != nil {
+		backupData.Preference.Language = i18n.Normalize(backupData.Preference.Language)
+	}
+	return backupData, nil
+}
+
+// restore applies a backup to a chat and reports the outcome in reply to the file
+func restore(ctx tb.Context, appCore *core.Core, chatID int64, backupData *backup.Backup, mode int, file *tb.Message) error {
+	summary, err := appCore.Restore(context.Background(), chatID, backupData, mode == restoreModeReplace, false)
+	if err != nil {
+		log.Errorf("restore chat %d failed, %v", chatID, err)
+		_, err = ctx.Bot().Reply(file, tr(ctx, "restore.failed"))
+		return err
+	}
+
+	var msg strings.Builder
+	msg.WriteString(tr(ctx, "restore.done", summary.Added, summary.Updated, summary.Removed, len(summary.Failed)))
+	for i, link := range summary.Failed {
+		msg.WriteString(fmt.Sprintf("[%d] %s\n", i+1, html.EscapeString(link)))
+	}
+	if len(summary.PauseSkipped) > 0 {
+		msg.WriteString(tr(ctx, "restore.pause_skipped", len(summary.PauseSkipped)))
+		for i, link := range summary.PauseSkipped {
+			msg.WriteString(fmt.Sprintf("[%d] %s\n", i+1, html.EscapeString(link)))
+		}
+	}
+	_, err = ctx.Bot().Reply(file, msg.String(), &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML})
+	return err
+}
+
+// RestoreButton merges or replaces with the backup file of a dry run
+type RestoreButton struct {
+	core *core.Core
+}
+
+func NewRestoreButton(core *core.Core) *RestoreButton {
+	return &RestoreButton{core: core}
+}
+
+func (b *RestoreButton) CallbackUnique() string {
+	return "\f" + RestoreButtonUnique
+}
+
+func (b *RestoreButton) Description() string {
+	return ""
+}
+
+func (b *RestoreButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil || c.Message.ReplyTo == nil || c.Message.ReplyTo.Document == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	// the backup may be restored to a channel managed from this chat
+	chatID := attachData.GetUserId()
+	if chatID != c.Message.Chat.ID {
+		targetChat, err := ctx.Bot().ChatByID(chatID)
+		if err != nil || !chat.IsChatAdmin(ctx.Bot(), targetChat, c.Sender.ID) {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+		}
+	}
+
+	backupData, err := readBackup(ctx, c.Message.ReplyTo.Document)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "restore.invalid_file", err)})
+	}
+	if err := ctx.Edit(tr(ctx, "restore.restoring")); err != nil {
+		log.Errorf("edit restore message failed, %v", err)
+	}
+	if err := ctx.Respond(); err != nil {
+		log.Errorf("respond restore callback failed, %v", err)
+	}
+	return restore(ctx, b.core, chatID, backupData, int(attachData.GetValue()), c.Message.ReplyTo)
+}
+
+func (b *RestoreButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
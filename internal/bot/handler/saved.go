@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+const (
+	SavedPageButtonUnique = "saved_page_btn"
+	MaxSavedSizePerPage   = 10
+)
+
+type Saved struct {
+	core *core.Core
+}
+
+func NewSaved(core *core.Core) *Saved {
+	return &Saved{core: core}
+}
+
+func (s *Saved) Command() string {
+	return "/saved"
+}
+
+func (s *Saved) Description() string {
+	return "Read later list, /saved export [md|html] to export"
+}
+
+func (s *Saved) Handle(ctx tb.Context) error {
+	args := strings.Fields(ctx.Message().Payload)
+	if len(args) > 0 && args[0] == "export" {
+		format := "md"
+		if len(args) > 1 {
+			format = strings.ToLower(args[1])
+		}
+		return s.export(ctx, format)
+	}
+
+	text, markup, err := savedPage(ctx, s.core, ctx.Sender().ID, 0)
+	if err != nil {
+		log.Errorf("get bookmarks of %d failed, %v", ctx.Sender().ID, err)
+		return ctx.Send(tr(ctx, "saved.fetch_failed"))
+	}
+	return ctx.Send(text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML}, markup)
+}
+
+func (s *Saved) export(ctx tb.Context, format string) error {
+	if format != "md" && format != "html" {
+		return ctx.Send(tr(ctx, "saved.export_usage"), tb.ModeMarkdown)
+	}
+
+	result, err := s.core.GetUserBookmarks(context.Background(), ctx.Sender().ID, 0, -1)
+	if err != nil {
+		log.Errorf("get bookmarks of %d failed, %v", ctx.Sender().ID, err)
+		return ctx.Send(tr(ctx, "export.failed"))
+	}
+	if len(result.Bookmarks) == 0 {
+		return ctx.Send(tr(ctx, "saved.empty"))
+	}
+
+	var content string
+	if format == "html" {
+		content = bookmarksToHTML(result.Bookmarks)
+	} else {
+		content = bookmarksToMarkdown(result.Bookmarks)
+	}
+	file := &tb.Document{File: tb.FromReader(strings.NewReader(content))}
+	file.FileName = fmt.Sprintf("saved_%d.%s", time.Now().Unix(), format)
+	if err := ctx.Send(file); err != nil {
+		log.Errorf("send bookmarks file failed, err:%v", err)
+		return ctx.Send(tr(ctx, "export.failed"))
+	}
+	return nil
+}
+
+func (s *Saved) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// savedPage renders one page of the read later list of a user
+func savedPage(ctx tb.Context, appCore *core.Core, userID int64, page int) (string, *tb.ReplyMarkup, error) {
+	stdCtx := context.Background()
+	total, err := appCore.CountUserBookmarks(stdCtx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if total == 0 {
+		return tr(ctx, "saved.empty"), nil, nil
+	}
+
+	result, err := appCore.GetUserBookmarks(stdCtx, userID, page*MaxSavedSizePerPage, MaxSavedSizePerPage)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var msg strings.Builder
+	msg.WriteString(tr(ctx, "saved.title", total, page+1))
+	for _, bookmark := range result.Bookmarks {
+		msg.WriteString(
+			fmt.Sprintf(
+				"[%d] <a href=\"%s\">%s</a>", bookmark.ID, html.EscapeString(bookmark.RawLink),
+				html.EscapeString(bookmarkTitle(bookmark)),
+			),
+		)
+		if bookmark.TelegraphURL != "" {
+			msg.WriteString(fmt.Sprintf(" | <a href=\"%s\">Telegraph</a>", html.EscapeString(bookmark.TelegraphURL)))
+		}
+		msg.WriteString("\n")
+	}
+	msg.WriteString(tr(ctx, "saved.footer"))
+
+	var row []tb.InlineButton
+	if page > 0 {
+		row = append(row, savedPageButton(userID, page-1, tr(ctx, "common.previous")))
+	}
+	if result.HasMore {
+		row = append(row, savedPageButton(userID, page+1, tr(ctx, "common.next")))
+	}
+	markup := &tb.ReplyMarkup{}
+	if len(row) > 0 {
+		markup.InlineKeyboard = [][]tb.InlineButton{row}
+	}
+	return msg.String(), markup, nil
+}
+
+func savedPageButton(userID int64, page int, text string) tb.InlineButton {
+	return tb.InlineButton{
+		Unique: SavedPageButtonUnique,
+		Text:   text,
+		Data:   session.Marshal(&session.Attachment{UserId: userID, Page: uint32(page)}),
+	}
+}
+
+func bookmarkTitle(bookmark *model.Bookmark) string {
+	if bookmark.Title != "" {
+		return bookmark.Title
+	}
+	return bookmark.RawLink
+}
+
+func bookmarksToMarkdown(bookmarks []*model.Bookmark) string {
+	var b strings.Builder
+	b.WriteString("# Read later\n\n")
+	for _, bookmark := range bookmarks {
+		b.WriteString(fmt.Sprintf("- [%s](%s)", bookmarkTitle(bookmark), bookmark.RawLink))
+		if bookmark.SourceTitle != "" {
+			b.WriteString(fmt.Sprintf(" - %s", bookmark.SourceTitle))
+		}
+		if bookmark.TelegraphURL != "" {
+			b.WriteString(fmt.Sprintf(" ([Telegraph](%s))", bookmark.TelegraphURL))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func bookmarksToHTML(bookmarks []*model.Bookmark) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>Read later</title></head>\n<body>\n<ul>\n")
+	for _, bookmark := range bookmarks {
+		b.WriteString(
+			fmt.Sprintf(
+				"<li><a href=\"%s\">%s</a>", html.EscapeString(bookmark.RawLink),
+				html.EscapeString(bookmarkTitle(bookmark)),
+			),
+		)
+		if bookmark.SourceTitle != "" {
+			b.WriteString(fmt.Sprintf(" - %s", html.EscapeString(bookmark.SourceTitle)))
+		}
+		if bookmark.TelegraphURL != "" {
+			b.WriteString(fmt.Sprintf(" (<a href=\"%s\">Telegraph</a>)", html.EscapeString(bookmark.TelegraphURL)))
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n</body>\n</html>\n")
+	return b.String()
+}
+
+type SavedPageButton struct {
+	core *core.Core
+}
+
+func NewSavedPageButton(core *core.Core) *SavedPageButton {
+	return &SavedPageButton{core: core}
+}
+
+func (b *SavedPageButton) CallbackUnique() string {
+	return "\f" + SavedPageButtonUnique
+}
+
+func (b *SavedPageButton) Description() string {
+	return ""
+}
+
+func (b *SavedPageButton) Handle(ctx tb.Context) error {
+	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	// the list belongs to the user who requested it
+	if attachData.GetUserId() != ctx.Sender().ID {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	text, markup, err := savedPage(ctx, b.core, attachData.GetUserId(), int(attachData.GetPage()))
+	if err != nil {
+		log.Errorf("get bookmarks of %d failed, %v", attachData.GetUserId(), err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	if err := ctx.Edit(text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML}, markup); err != nil {
+		log.Errorf("edit saved page failed, %v", err)
+	}
+	return ctx.Respond()
+}
+
+func (b *SavedPageButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+type Unsave struct {
+	core *core.Core
+}
+
+func NewUnsave(core *core.Core) *Unsave {
+	return &Unsave{core: core}
+}
+
+func (u *Unsave) Command() string {
+	return "/unsave"
+}
+
+func (u *Unsave) Description() string {
+	return "Remove an item from the read later list"
+}
+
+func (u *Unsave) Handle(ctx tb.Context) error {
+	id, err := strconv.ParseUint(strings.TrimSpace(ctx.Message().Payload), 10, 32)
+	if err != nil {
+		return ctx.Send(tr(ctx, "unsave.usage"), tb.ModeMarkdown)
+	}
+
+	if err := u.core.RemoveBookmark(context.Background(), ctx.Sender().ID, uint(id)); err != nil {
+		if err == core.ErrBookmarkNotExist {
+			return ctx.Send(tr(ctx, "unsave.not_exist"))
+		}
+		log.Errorf("remove bookmark %d of %d failed, %v", id, ctx.Sender().ID, err)
+		return ctx.Send(tr(ctx, "unsave.failed"))
+	}
+	return ctx.Send(tr(ctx, "unsave.success"))
+}
+
+func (u *Unsave) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/storage"
+)
+
+const (
+	SearchPageButtonUnique = "search_page_btn"
+	MaxSearchSizePerPage   = 10
+)
+
+// errSearchUsage the query can not be parsed, the usage is shown instead
+var errSearchUsage = errors.New("invalid search query")
+
+type Search struct {
+	core *core.Core
+}
+
+func NewSearch(core *core.Core) *Search {
+	return &Search{core: core}
+}
+
+func (s *Search) Command() string {
+	return "/search"
+}
+
+func (s *Search) Description() string {
+	return "Search received items of subscribed feeds"
+}
+
+func (s *Search) Handle(ctx tb.Context) error {
+	text, markup, err := searchPage(ctx, s.core, ctx.Chat().ID, ctx.Message().Payload, 0)
+	if err != nil {
+		return ctx.Send(err.Error(), tb.ModeMarkdown)
+	}
+	// results reply to the command so page buttons can read the query back
+	return ctx.Send(
+		text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML, ReplyTo: ctx.Message()}, markup,
+	)
+}
+
+func (s *Search) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// parseSearchQuery parses the filters and keywords of a search
+func parseSearchQuery(payload string) (*storage.SearchContentsOptions, uint, error) {
+	opts := &storage.SearchContentsOptions{}
+	var sourceID uint
+	var keywords []string
+	for _, field := range strings.Fields(payload) {
+		key, value, found := strings.Cut(field, ":")
+		if !found || value == "" {
+			keywords = append(keywords, field)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "source":
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, 0, errSearchUsage
+			}
+			sourceID = uint(id)
+		case "since":
+			since, err := parseSearchDate(value)
+			if err != nil {
+				return nil, 0, errSearchUsage
+			}
+			opts.Since = since
+		case "until":
+			until, err := parseSearchDate(value)
+			if err != nil {
+				return nil, 0, errSearchUsage
+			}
+			opts.Until = until.AddDate(0, 0, 1)
+		default:
+			keywords = append(keywords, field)
+		}
+	}
+
+	if len(keywords) == 0 {
+		return nil, 0, errSearchUsage
+	}
+	opts.Query = strings.Join(keywords, " ")
+	return opts, sourceID, nil
+}
+
+// parseSearchDate parses a date like 2006-01-02 or a relative day count like 7d
+func parseSearchDate(value string) (time.Time, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, errSearchUsage
+		}
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -n), nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// searchPage renders one page of search results in the subscriptions of a chat
+func searchPage(ctx tb.Context, appCore *core.Core, chatID int64, payload string, page int) (
+	string, *tb.ReplyMarkup, error,
+) {
+	opts, sourceID, err := parseSearchQuery(payload)
+	if err != nil {
+		return "", nil, errors.New(tr(ctx, "search.usage"))
+	}
+	opts.Offset = page * MaxSearchSizePerPage
+	opts.Count = MaxSearchSizePerPage
+
+	stdCtx := context.Background()
+	result, err := appCore.SearchContents(stdCtx, chatID, sourceID, opts)
+	if err != nil {
+		if err == core.ErrSubscriptionNotExist {
+			return "", nil, errors.New(tr(ctx, "search.not_subscribed"))
+		}
+		log.Errorf("search contents of %d failed, %v", chatID, err)
+		return "", nil, errors.New(tr(ctx, "search.failed"))
+	}
+	if len(result.Contents) == 0 {
+		return tr(ctx, "search.no_result"), nil, nil
+	}
+
+	sourceTitles := make(map[uint]string)
+	var msg strings.Builder
+	msg.WriteString(tr(ctx, "search.title", page+1))
+	for _, content := range result.Contents {
+		title, ok := sourceTitles[content.SourceID]
+		if !ok {
+			if source, err := appCore.GetSource(stdCtx, content.SourceID); err == nil {
+				title = source.Title
+			}
+			sourceTitles[content.SourceID] = title
+		}
+		msg.WriteString(
+			fmt.Sprintf(
+				"%s <a href=\"%s\">%s</a> | %s\n", content.CreatedAt.Format("2006-01-02"),
+				html.EscapeString(content.RawLink), html.EscapeString(content.Title), html.EscapeString(title),
+			),
+		)
+	}
+
+	var row []tb.InlineButton
+	if page > 0 {
+		row = append(row, searchPageButton(chatID, page-1, tr(ctx, "common.previous")))
+	}
+	if result.HasMore {
+		row = append(row, searchPageButton(chatID, page+1, tr(ctx, "common.next")))
+	}
+	markup := &tb.ReplyMarkup{}
+	if len(row) > 0 {
+		markup.InlineKeyboard = [][]tb.InlineButton{row}
+	}
+	return msg.String(), markup, nil
+}
+
+func searchPageButton(chatID int64, page int, text string) tb.InlineButton {
+	return tb.InlineButton{
+		Unique: SearchPageButtonUnique,
+		Text:   text,
+		Data:   session.Marshal(&session.Attachment{UserId: chatID, Page: uint32(page)}),
+	}
+}
+
+type SearchPageButton struct {
+	core *core.Core
+}
+
+func NewSearchPageButton(core *core.Core) *SearchPageButton {
+	return &SearchPageButton{core: core}
+}
+
+func (b *SearchPageButton) CallbackUnique() string {
+	return "\f" + SearchPageButtonUnique
+}
+
+func (b *SearchPageButton) Description() string {
+	return ""
+}
+
+func (b *SearchPageButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c.Message == nil || c.Message.ReplyTo == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "search.expired")})
+	}
+
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	if attachData.GetUserId() != c.Message.Chat.ID {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	// strip the command from the original request to get the query
+	_, payload, _ := strings.Cut(c.Message.ReplyTo.Text, " ")
+	text, markup, err := searchPage(ctx, b.core, c.Message.Chat.ID, payload, int(attachData.GetPage()))
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: err.Error()})
+	}
+	if err := ctx.Edit(text, &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML}, markup); err != nil {
+		log.Errorf("edit search page failed, %v", err)
+	}
+	return ctx.Respond()
+}
+
+func (b *SearchPageButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"html"
 	"text/template"
 
 	tb "gopkg.in/telebot.v3"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/preview"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
 	"github.com/andatoshiki/toshiki-rssbot/internal/config"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
 	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 )
 
@@ -44,25 +47,25 @@ func (s *Set) Handle(ctx tb.Context) error {
 
 	sources, err := s.core.GetUserSubscribedSources(context.Background(), ownerID)
 	if err != nil {
-		return ctx.Reply("Failed to fetch subscriptions")
+		return ctx.Reply(tr(ctx, "subscriptions.fetch_failed"))
 	}
 	if len(sources) <= 0 {
-		return ctx.Reply("Currently no active subscriptions")
+		return ctx.Reply(tr(ctx, "subscriptions.none"))
 	}
 
-	// Configuration button
-	var replyButton []tb.ReplyButton
-	replyKeys := [][]tb.ReplyButton{}
+	return ctx.Reply(
+		tr(ctx, "set.select"), &tb.ReplyMarkup{
+			InlineKeyboard: feedListButtons(sources, ownerID),
+		},
+	)
+}
+
+// feedListButtons generates a button per subscribed source to open its settings
+func feedListButtons(sources []*model.Source, ownerID int64) [][]tb.InlineButton {
 	setFeedItemBtns := [][]tb.InlineButton{}
 	for _, source := range sources {
-		// Add button
-		text := fmt.Sprintf("%s %s", source.Title, source.Link)
-		replyButton = []tb.ReplyButton{
-			{Text: text},
-		}
-		replyKeys = append(replyKeys, replyButton)
 		attachData := &session.Attachment{
-			UserId:   ctx.Chat().ID,
+			UserId:   ownerID,
 			SourceId: uint32(source.ID),
 		}
 
@@ -77,12 +80,7 @@ func (s *Set) Handle(ctx tb.Context) error {
 			},
 		)
 	}
-
-	return ctx.Reply(
-		"Please select the target subscription to configure", &tb.ReplyMarkup{
-			InlineKeyboard: setFeedItemBtns,
-		},
-	)
+	return setFeedItemBtns
 }
 
 func (s *Set) Middlewares() []tb.MiddlewareFunc {
@@ -91,17 +89,6 @@ func (s *Set) Middlewares() []tb.MiddlewareFunc {
 
 const (
 	SetFeedItemButtonUnique = "set_feed_item_btn"
-	feedSettingTmpl         = `
-Subscription<b>Setting</b>
-[id] {{ .source.ID }}
-[Title] {{ .source.Title }}
-[Link] {{.source.Link }}
-[Interval] {{if ge .source.ErrorCount .Count }}Pause{{else if lt .source.ErrorCount .Count }}Fetching in progress{{end}}
-[Frequency] {{ .sub.Interval }}minute(s)
-[Notification] {{if eq .sub.EnableNotification 0}}Disable{{else if eq .sub.EnableNotification 1}}Enable{{end}}
-[Telegraph] {{if eq .sub.EnableTelegraph 0}}Disable{{else if eq .sub.EnableTelegraph 1}}Enable{{end}}
-[Tag] {{if .sub.Tag}}{{ .sub.Tag }}{{else}}None{{end}}
-`
 )
 
 type SetFeedItemButton struct {
@@ -124,7 +111,7 @@ func (r *SetFeedItemButton) Description() string {
 func (r *SetFeedItemButton) Handle(ctx tb.Context) error {
 	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
 	if err != nil {
-		return ctx.Edit("Un")
+		return ctx.Edit(tr(ctx, "common.internal_error"))
 	}
 
 	subscriberID := attachData.GetUserId()
@@ -132,71 +119,126 @@ func (r *SetFeedItemButton) Handle(ctx tb.Context) error {
 	if subscriberID != ctx.Callback().Sender.ID {
 		channelChat, err := r.bot.ChatByUsername(fmt.Sprintf("%d", subscriberID))
 		if err != nil {
-			return ctx.Edit("Failed to fetch subscription information")
+			return ctx.Edit(tr(ctx, "set.fetch_failed"))
 		}
 
 		if !chat.IsChatAdmin(r.bot, channelChat, ctx.Callback().Sender.ID) {
-			return ctx.Edit("Failed to fetch subscription information") 
+			return ctx.Edit(tr(ctx, "set.fetch_failed"))
 		}
 	}
 
 	sourceID := uint(attachData.GetSourceId())
 	source, err := r.core.GetSource(context.Background(), sourceID)
 	if err != nil {
-		return ctx.Edit("Unable to find target subscription sources")
+		return ctx.Edit(tr(ctx, "set.source_not_found"))
 	}
 
 	sub, err := r.core.GetSubscription(context.Background(), subscriberID, source.ID)
 	if err != nil {
-		return ctx.Edit("RSS feed not subscribed by user")
+		return ctx.Edit(tr(ctx, "unsub.not_subscribed"))
 	}
 
-	t := template.New("setting template")
-	_, _ = t.Parse(feedSettingTmpl)
-	text := new(bytes.Buffer)
-	_ = t.Execute(text, map[string]interface{}{"source": source, "sub": sub, "Count": config.ErrorThreshold})
 	return ctx.Edit(
-		text.String(),
+		feedSettingText(ctx, r.core, source, sub),
 		&tb.SendOptions{ParseMode: tb.ModeHTML},
-		&tb.ReplyMarkup{InlineKeyboard: genFeedSetBtn(ctx.Callback(), sub, source)},
+		&tb.ReplyMarkup{InlineKeyboard: genFeedSetBtn(ctx, sub, source)},
+	)
+}
+
+// feedSettingText renders the settings and the health of a subscription
+func feedSettingText(ctx tb.Context, appCore *core.Core, source *model.Source, sub *model.Subscribe) string {
+	subscriberCount, err := appCore.CountSourceSubscriptions(context.Background(), source.ID)
+	if err != nil {
+		log.Errorf("count source %d subscriptions failed, %v", source.ID, err)
+	}
+
+	lastFetch := tr(ctx, "feedset.never")
+	if source.LastFetchedAt != nil {
+		lastFetch = source.LastFetchedAt.Format("2006-01-02 15:04:05")
+	}
+
+	previewLength := tr(ctx, "feedset.default", config.PreviewText)
+	if sub.PreviewLength < 0 {
+		previewLength = tr(ctx, "feedset.hidden")
+	} else if sub.PreviewLength > 0 {
+		previewLength = tr(ctx, "feedset.characters", sub.PreviewLength)
+	}
+
+	webPagePreview := tr(ctx, "feedset.enable")
+	if preview.DisableWebPage(sub) {
+		webPagePreview = tr(ctx, "feedset.disable")
+	}
+	if sub.WebPagePreview == preview.WebPagePreviewDefault {
+		webPagePreview = tr(ctx, "feedset.default", webPagePreview)
+	}
+
+	t := template.New("setting template")
+	_, _ = t.Parse(tr(ctx, "feedset.template"))
+	text := new(bytes.Buffer)
+	_ = t.Execute(
+		text, map[string]interface{}{
+			"source":          source,
+			"sub":             sub,
+			"Count":           config.ErrorThreshold,
+			"subscriberCount": subscriberCount,
+			"lastFetch":       lastFetch,
+			"lastError":       html.EscapeString(source.LastError),
+			"previewLength":   previewLength,
+			"webPagePreview":  webPagePreview,
+		},
 	)
+	return text.String()
 }
 
-func genFeedSetBtn(
-	c *tb.Callback, sub *model.Subscribe, source *model.Source,
-) [][]tb.InlineButton {
+func genFeedSetBtn(ctx tb.Context, sub *model.Subscribe, source *model.Source) [][]tb.InlineButton {
+	data := session.Marshal(
+		&session.Attachment{
+			UserId:   sub.UserID,
+			SourceId: uint32(source.ID),
+		},
+	)
+
 	setSubTagKey := tb.InlineButton{
 		Unique: SetSubscriptionTagButtonUnique,
-		Text:   "Configure tags",
-		Data:   c.Data,
+		Text:   tr(ctx, "feedset.btn_tags"),
+		Data:   data,
 	}
 
 	toggleNoticeKey := tb.InlineButton{
 		Unique: NotificationSwitchButtonUnique,
-		Text:   "Enable notification",
-		Data:   c.Data,
+		Text:   tr(ctx, "feedset.btn_notice_on"),
+		Data:   data,
 	}
 	if sub.EnableNotification == 1 {
-		toggleNoticeKey.Text = "Disable notification"
+		toggleNoticeKey.Text = tr(ctx, "feedset.btn_notice_off")
 	}
 
 	toggleTelegraphKey := tb.InlineButton{
 		Unique: TelegraphSwitchButtonUnique,
-		Text:   "Enable Telegraph transcoding",
-		Data:   c.Data,
+		Text:   tr(ctx, "feedset.btn_telegraph_on"),
+		Data:   data,
 	}
 	if sub.EnableTelegraph == 1 {
-		toggleTelegraphKey.Text = "Disable Telegraph transcoding"
+		toggleTelegraphKey.Text = tr(ctx, "feedset.btn_telegraph_off")
+	}
+
+	toggleEditUpdateKey := tb.InlineButton{
+		Unique: EditUpdateSwitchButtonUnique,
+		Text:   tr(ctx, "feedset.btn_edit_on"),
+		Data:   data,
+	}
+	if sub.EnableEditUpdate == 1 {
+		toggleEditUpdateKey.Text = tr(ctx, "feedset.btn_edit_off")
 	}
 
 	toggleEnabledKey := tb.InlineButton{
 		Unique: SubscriptionSwitchButtonUnique,
-		Text:   "Pause update",
-		Data:   c.Data,
+		Text:   tr(ctx, "feedset.btn_pause"),
+		Data:   data,
 	}
 
 	if source.ErrorCount >= config.ErrorThreshold {
-		toggleEnabledKey.Text = "Restart update"
+		toggleEnabledKey.Text = tr(ctx, "feedset.btn_restart")
 	}
 
 	feedSettingKeys := [][]tb.InlineButton{
@@ -208,6 +250,20 @@ func genFeedSetBtn(
 			toggleTelegraphKey,
 			setSubTagKey,
 		},
+		{
+			toggleEditUpdateKey,
+		},
+		{
+			feedSetPageButton(sub, feedSetPageInterval, tr(ctx, "feedset.btn_interval")),
+			feedSetPageButton(sub, feedSetPagePreview, tr(ctx, "feedset.btn_preview")),
+		},
+		{
+			tb.InlineButton{
+				Unique: SetFeedBackButtonUnique,
+				Text:   tr(ctx, "feedset.btn_back_list"),
+				Data:   data,
+			},
+		},
 	}
 	return feedSettingKeys
 }
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/preview"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+const (
+	SetFeedPageButtonUnique       = "set_feed_page_btn"
+	SetFeedBackButtonUnique       = "set_feed_back_btn"
+	SetIntervalButtonUnique       = "set_interval_btn"
+	SetPreviewLengthButtonUnique  = "set_preview_len_btn"
+	SetWebPagePreviewButtonUnique = "set_web_preview_btn"
+)
+
+// sub pages of the subscription settings panel
+const (
+	feedSetPageInterval = iota + 1
+	feedSetPagePreview
+)
+
+var (
+	intervalPresets      = []int{5, 10, 30, 60, 180, 720, 1440}
+	previewLengthPresets = []int{0, -1, 100, 200, 500}
+)
+
+func feedSetPageButton(sub *model.Subscribe, page int, text string) tb.InlineButton {
+	return tb.InlineButton{
+		Unique: SetFeedPageButtonUnique,
+		Text:   text,
+		Data: session.Marshal(
+			&session.Attachment{UserId: sub.UserID, SourceId: uint32(sub.SourceID), Page: uint32(page)},
+		),
+	}
+}
+
+func feedSetValueButton(unique string, sub *model.Subscribe, value int, text string, selected bool) tb.InlineButton {
+	if selected {
+		text = "• " + text
+	}
+	return tb.InlineButton{
+		Unique: unique,
+		Text:   text,
+		Data: session.Marshal(
+			&session.Attachment{UserId: sub.UserID, SourceId: uint32(sub.SourceID), Value: int32(value)},
+		),
+	}
+}
+
+// genFeedSetPageBtn generates the buttons of a sub page of the settings panel
+func genFeedSetPageBtn(ctx tb.Context, sub *model.Subscribe, page int) [][]tb.InlineButton {
+	var keys [][]tb.InlineButton
+	switch page {
+	case feedSetPageInterval:
+		var row []tb.InlineButton
+		for _, interval := range intervalPresets {
+			text := fmt.Sprintf("%dmin", interval)
+			if interval >= 60 {
+				text = fmt.Sprintf("%dh", interval/60)
+			}
+			row = append(row, feedSetValueButton(SetIntervalButtonUnique, sub, interval, text, sub.Interval == interval))
+			if len(row) == 4 {
+				keys = append(keys, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			keys = append(keys, row)
+		}
+	case feedSetPagePreview:
+		var row []tb.InlineButton
+		for _, length := range previewLengthPresets {
+			text := fmt.Sprintf("%d", length)
+			if length == 0 {
+				text = tr(ctx, "feedset.btn_default")
+			} else if length < 0 {
+				text = tr(ctx, "feedset.hidden")
+			}
+			row = append(
+				row, feedSetValueButton(SetPreviewLengthButtonUnique, sub, length, text, sub.PreviewLength == length),
+			)
+		}
+		keys = append(keys, row)
+		keys = append(
+			keys, []tb.InlineButton{
+				feedSetValueButton(
+					SetWebPagePreviewButtonUnique, sub, preview.WebPagePreviewDefault, tr(ctx, "feedset.btn_web_default"),
+					sub.WebPagePreview == preview.WebPagePreviewDefault,
+				),
+				feedSetValueButton(
+					SetWebPagePreviewButtonUnique, sub, preview.WebPagePreviewShow, tr(ctx, "feedset.btn_show"),
+					sub.WebPagePreview == preview.WebPagePreviewShow,
+				),
+				feedSetValueButton(
+					SetWebPagePreviewButtonUnique, sub, preview.WebPagePreviewHide, tr(ctx, "feedset.btn_hide"),
+					sub.WebPagePreview == preview.WebPagePreviewHide,
+				),
+			},
+		)
+	}
+
+	back := session.Marshal(&session.Attachment{UserId: sub.UserID, SourceId: uint32(sub.SourceID)})
+	keys = append(keys, []tb.InlineButton{{Unique: SetFeedItemButtonUnique, Text: tr(ctx, "feedset.btn_back"), Data: back}})
+	return keys
+}
+
+// feedSetAuth checks that the sender may configure the subscriptions of the subscriber
+func feedSetAuth(bot *tb.Bot, c *tb.Callback, attachData *session.Attachment) bool {
+	subscriberID := attachData.GetUserId()
+	if subscriberID == c.Sender.ID {
+		return true
+	}
+
+	channelChat, err := bot.ChatByID(subscriberID)
+	if err != nil {
+		return false
+	}
+	return chat.IsChatAdmin(bot, channelChat, c.Sender.ID)
+}
+
+// editFeedSetPage redraws a sub page of the settings panel
+func editFeedSetPage(ctx tb.Context, appCore *core.Core, attachData *session.Attachment, page int) error {
+	sourceID := uint(attachData.GetSourceId())
+	source, err := appCore.GetSource(context.Background(), sourceID)
+	if err != nil {
+		return err
+	}
+
+	sub, err := appCore.GetSubscription(context.Background(), attachData.GetUserId(), sourceID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Edit(
+		feedSettingText(ctx, appCore, source, sub),
+		&tb.SendOptions{ParseMode: tb.ModeHTML},
+		&tb.ReplyMarkup{InlineKeyboard: genFeedSetPageBtn(ctx, sub, page)},
+	)
+}
+
+type SetFeedPageButton struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewSetFeedPageButton(bot *tb.Bot, core *core.Core) *SetFeedPageButton {
+	return &SetFeedPageButton{bot: bot, core: core}
+}
+
+func (b *SetFeedPageButton) CallbackUnique() string {
+	return "\f" + SetFeedPageButtonUnique
+}
+
+func (b *SetFeedPageButton) Description() string {
+	return ""
+}
+
+func (b *SetFeedPageButton) Handle(ctx tb.Context) error {
+	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	if !feedSetAuth(b.bot, ctx.Callback(), attachData) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	if err := editFeedSetPage(ctx, b.core, attachData, int(attachData.GetPage())); err != nil {
+		log.Errorf("edit feed setting page failed, %v", err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	return ctx.Respond()
+}
+
+func (b *SetFeedPageButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+type SetFeedBackButton struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewSetFeedBackButton(bot *tb.Bot, core *core.Core) *SetFeedBackButton {
+	return &SetFeedBackButton{bot: bot, core: core}
+}
+
+func (b *SetFeedBackButton) CallbackUnique() string {
+	return "\f" + SetFeedBackButtonUnique
+}
+
+func (b *SetFeedBackButton) Description() string {
+	return ""
+}
+
+func (b *SetFeedBackButton) Handle(ctx tb.Context) error {
+	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	if !feedSetAuth(b.bot, ctx.Callback(), attachData) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	sources, err := b.core.GetUserSubscribedSources(context.Background(), attachData.GetUserId())
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "subscriptions.fetch_failed")})
+	}
+	if len(sources) == 0 {
+		return ctx.Edit(tr(ctx, "subscriptions.none"))
+	}
+	return ctx.Edit(
+		tr(ctx, "set.select"), &tb.ReplyMarkup{
+			InlineKeyboard: feedListButtons(sources, attachData.GetUserId()),
+		},
+	)
+}
+
+func (b *SetFeedBackButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+type SetIntervalButton struct {
+	bot  *tb.Bot
+	core *core.Core
+}
+
+func NewSetIntervalButton(bot *tb.Bot, core *core.Core) *SetIntervalButton {
+	return &SetIntervalButton{bot: bot, core: core}
+}
+
+func (b *SetIntervalButton) CallbackUnique() string {
+	return "\f" + SetIntervalButtonUnique
+}
+
+func (b *SetIntervalButton) Description() string {
+	return ""
+}
+
+func (b *SetIntervalButton) Handle(ctx tb.Context) error {
+	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
+	if err != nil || attachData.GetValue() <= 0 {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	if !feedSetAuth(b.bot, ctx.Callback(), attachData) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	err = b.core.SetSubscriptionInterval(
+		context.Background(), attachData.GetUserId(), uint(attachData.GetSourceId()), int(attachData.GetValue()),
+	)
+	if err != nil {
+		log.Errorf("SetSubscriptionInterval failed, %v", err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	if err := editFeedSetPage(ctx, b.core, attachData, feedSetPageInterval); err != nil {
+		log.Errorf("edit feed setting page failed, %v", err)
+	}
+	return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.modified")})
+}
+
+func (b *SetIntervalButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+type SetPreviewButton struct {
+	bot    *tb.Bot
+	core   *core.Core
+	unique string
+}
+
+// NewSetPreviewLengthButton sets the preview text length of a subscription
+func NewSetPreviewLengthButton(bot *tb.Bot, core *core.Core) *SetPreviewButton {
+	return &SetPreviewButton{bot: bot, core: core, unique: SetPreviewLengthButtonUnique}
+}
+
+// NewSetWebPagePreviewButton sets the web page preview mode of a subscription
+func NewSetWebPagePreviewButton(bot *tb.Bot, core *core.Core) *SetPreviewButton {
+	return &SetPreviewButton{bot: bot, core: core, unique: SetWebPagePreviewButtonUnique}
+}
+
+func (b *SetPreviewButton) CallbackUnique() string {
+	return "\f" + b.unique
+}
+
+func (b *SetPreviewButton) Description() string {
+	return ""
+}
+
+func (b *SetPreviewButton) Handle(ctx tb.Context) error {
+	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	if !feedSetAuth(b.bot, ctx.Callback(), attachData) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	stdCtx := context.Background()
+	sourceID := uint(attachData.GetSourceId())
+	sub, err := b.core.GetSubscription(stdCtx, attachData.GetUserId(), sourceID)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	previewLength, webPagePreview := sub.PreviewLength, sub.WebPagePreview
+	if b.unique == SetPreviewLengthButtonUnique {
+		previewLength = int(attachData.GetValue())
+	} else {
+		webPagePreview = int(attachData.GetValue())
+	}
+	err = b.core.SetSubscriptionPreview(stdCtx, attachData.GetUserId(), sourceID, previewLength, webPagePreview)
+	if err != nil {
+		log.Errorf("SetSubscriptionPreview failed, %v", err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	if err := editFeedSetPage(ctx, b.core, attachData, feedSetPagePreview); err != nil {
+		log.Errorf("edit feed setting page failed, %v", err)
+	}
+	return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.modified")})
+}
+
+func (b *SetPreviewButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/preview"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+func TestGenFeedSetPageBtn(t *testing.T) {
+	ctx := (&tb.Bot{}).NewContext(tb.Update{})
+	sub := &model.Subscribe{
+		UserID: -100, SourceID: 1, Interval: 60, PreviewLength: -1, WebPagePreview: preview.WebPagePreviewHide,
+	}
+
+	t.Run(
+		"interval page", func(t *testing.T) {
+			keys := genFeedSetPageBtn(ctx, sub, feedSetPageInterval)
+			assert.Len(t, keys, 3)
+			assert.Len(t, keys[0], 4)
+			assert.Len(t, keys[1], 3)
+			assert.Equal(t, "5min", keys[0][0].Text)
+			assert.Equal(t, "• 1h", keys[0][3].Text)
+			assert.Equal(t, "24h", keys[1][2].Text)
+
+			attachData, err := session.UnmarshalAttachment(keys[1][0].Data)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(-100), attachData.GetUserId())
+			assert.Equal(t, uint32(1), attachData.GetSourceId())
+			assert.Equal(t, int32(180), attachData.GetValue())
+			assert.Equal(t, SetFeedItemButtonUnique, keys[2][0].Unique)
+		},
+	)
+
+	t.Run(
+		"preview page", func(t *testing.T) {
+			keys := genFeedSetPageBtn(ctx, sub, feedSetPagePreview)
+			assert.Len(t, keys, 3)
+			assert.Len(t, keys[0], 5)
+			assert.Len(t, keys[1], 3)
+			for _, btn := range keys[0] {
+				assert.Equal(t, SetPreviewLengthButtonUnique, btn.Unique)
+			}
+			assert.Equal(t, "• "+tr(ctx, "feedset.hidden"), keys[0][1].Text)
+			assert.Equal(t, "• "+tr(ctx, "feedset.btn_hide"), keys[1][2].Text)
+
+			attachData, err := session.UnmarshalAttachment(keys[0][4].Data)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(500), attachData.GetValue())
+			assert.Equal(t, SetFeedItemButtonUnique, keys[2][0].Unique)
+		},
+	)
+}
@@ -40,7 +40,7 @@ func (s *SetFeedTag) Handle(ctx tb.Context) error {
 	msg := s.getMessageWithoutMention(ctx)
 	args := strings.Split(strings.TrimSpace(msg), " ")
 	if len(args) < 1 {
-		return ctx.Reply("/setfeedtag `[source_id] [tag1] [tag2]` to set tags for subscription feeds; a maximum of 3 tags could be appended to each feed source and tags are required to split by spaces to match the internal grammatical syntax of the bot respectfully")
+		return ctx.Reply(tr(ctx, "setfeedtag.usage"))
 	}
 
 	// truncate properties
@@ -56,9 +56,9 @@ func (s *SetFeedTag) Handle(ctx tb.Context) error {
 	}
 
 	if err := s.core.SetSubscriptionTag(context.Background(), subscribeUserID, sourceID, args[1:]); err != nil {
-		return ctx.Reply("Failed to set tag(s) for subscription feed!")
+		return ctx.Reply(tr(ctx, "setfeedtag.failed"))
 	}
-	return ctx.Reply("Successfully set tag(s) for subscription feed!")
+	return ctx.Reply(tr(ctx, "setfeedtag.success"))
 }
 
 func (s *SetFeedTag) Middlewares() []tb.MiddlewareFunc {
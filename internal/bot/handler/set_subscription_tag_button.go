@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"fmt"
-
 	tb "gopkg.in/telebot.v3"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
@@ -48,18 +46,14 @@ func (b *SetSubscriptionTagButton) Handle(ctx tb.Context) error {
 	c := ctx.Callback()
 	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
 	if err != nil {
-		return ctx.Edit("System error")
+		return ctx.Edit(tr(ctx, "common.internal_error"))
 	}
 
 	if !b.feedSetAuth(c, attachData) {
-		return ctx.Send("Permission or access rights not granted")
+		return ctx.Send(tr(ctx, "common.permission_denied"))
 	}
 	sourceID := uint(attachData.GetSourceId())
-	msg := fmt.Sprintf(
-		"Please utilize `/setfeedtag %d tags` command to configure topic tags for the subscription source, `tags` indicates the target tags to be configured. A maximum of 3 tags could be appended to each feed source and tags are required to split by spaces to match the internal grammatical syntax of the bot respectfully \n"+
-			"E.g.:`/setfeedtag %d anime moe`",
-		sourceID, sourceID,
-	)
+	msg := tr(ctx, "setfeedtag.button_hint", sourceID, sourceID)
 	return ctx.Edit(msg, &tb.SendOptions{ParseMode: tb.ModeMarkdown})
 }
 
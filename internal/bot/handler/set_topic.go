@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cast"
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+type SetTopic struct {
+	core *core.Core
+}
+
+func NewSetTopic(core *core.Core) *SetTopic {
+	return &SetTopic{core: core}
+}
+
+func (s *SetTopic) Command() string {
+	return "/settopic"
+}
+
+func (s *SetTopic) Description() string {
+	return "Deliver subscriptions to a forum topic"
+}
+
+func (s *SetTopic) Handle(ctx tb.Context) error {
+	if ctx.Chat().Type != tb.ChatSuperGroup {
+		return ctx.Reply(tr(ctx, "settopic.not_forum"))
+	}
+
+	args := strings.Fields(ctx.Message().Payload)
+	if len(args) == 0 {
+		return ctx.Reply(tr(ctx, "settopic.usage"))
+	}
+
+	if args[0] == "auto" {
+		return s.autoCreateTopics(ctx)
+	}
+
+	threadID := 0
+	if ctx.Message().TopicMessage {
+		threadID = ctx.Message().ThreadID
+	}
+	for _, id := range args {
+		sourceID := cast.ToUint(id)
+		if err := s.core.SetSubscriptionThread(context.Background(), ctx.Chat().ID, sourceID, threadID); err != nil {
+			log.Errorf("SetSubscriptionThread failed, %v", err)
+			return ctx.Reply(tr(ctx, "settopic.failed"))
+		}
+	}
+
+	if threadID == 0 {
+		return ctx.Reply(tr(ctx, "settopic.general"))
+	}
+	return ctx.Reply(tr(ctx, "settopic.success"))
+}
+
+func (s *SetTopic) autoCreateTopics(ctx tb.Context) error {
+	var topicErr error
+	created, err := s.core.AutoCreateTopics(
+		context.Background(), ctx.Chat().ID, func(name string) (int, error) {
+			topic, err := ctx.Bot().CreateTopic(ctx.Chat(), &tb.Topic{Name: topicName(name)})
+			if err != nil {
+				topicErr = err
+				return 0, err
+			}
+			return topic.ThreadID, nil
+		},
+	)
+	if topicErr != nil {
+		log.Errorf("create topic for chat %d failed after %d topics, %v", ctx.Chat().ID, created, topicErr)
+		return ctx.Reply(tr(ctx, "settopic.create_failed"))
+	}
+	if err != nil {
+		return ctx.Reply(tr(ctx, "subscriptions.fetch_failed"))
+	}
+	return ctx.Reply(tr(ctx, "settopic.created", created))
+}
+
+// topicName forum topic names are limited to 128 characters
+func topicName(title string) string {
+	name := []rune(strings.TrimSpace(title))
+	if len(name) == 0 {
+		return "RSS"
+	}
+	if len(name) > 128 {
+		name = name[:128]
+	}
+	return string(name)
+}
+
+func (s *SetTopic) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
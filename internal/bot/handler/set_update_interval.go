@@ -42,12 +42,12 @@ func (s *SetUpdateInterval) Handle(ctx tb.Context) error {
 	msg := s.getMessageWithoutMention(ctx)
 	args := strings.Split(strings.TrimSpace(msg), " ")
 	if len(args) < 2 {
-		return ctx.Reply("/setinterval [interval] [source_id] Configure the refresh or update interval for subscription feeds, default unit of time is minute (Configuration for multiple sub_id is allowed by splitting with spaces)") 
+		return ctx.Reply(tr(ctx, "setinterval.usage"))
 	}
 
 	interval, err := strconv.Atoi(args[0])
 	if interval <= 0 || err != nil {
-		return ctx.Reply("Please enter the correct update time interval") 
+		return ctx.Reply(tr(ctx, "setinterval.invalid"))
 	}
 
 	subscribeUserID := ctx.Message().Chat.ID
@@ -62,10 +62,10 @@ func (s *SetUpdateInterval) Handle(ctx tb.Context) error {
 			context.Background(), subscribeUserID, sourceID, interval,
 		); err != nil {
 			log.Errorf("SetSubscriptionInterval failed, %v", err)
-			return ctx.Reply("Failed to configure time intervals!")
+			return ctx.Reply(tr(ctx, "setinterval.failed"))
 		}
 	}
-	return ctx.Reply("Successfully configured time intervals!")
+	return ctx.Reply(tr(ctx, "setinterval.success"))
 }
 
 func (s *SetUpdateInterval) Middlewares() []tb.MiddlewareFunc {
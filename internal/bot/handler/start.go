@@ -1,18 +1,21 @@
 package handler
 
 import (
-	"fmt"
+	"context"
+	"strings"
 
 	tb "gopkg.in/telebot.v3"
 
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
 	"github.com/andatoshiki/toshiki-rssbot/internal/log"
 )
 
 type Start struct {
+	core *core.Core
 }
 
-func NewStart() *Start {
-	return &Start{}
+func NewStart(core *core.Core) *Start {
+	return &Start{core: core}
 }
 
 func (s *Start) Command() string {
@@ -25,7 +28,28 @@ func (s *Start) Description() string {
 
 func (s *Start) Handle(ctx tb.Context) error {
 	log.Infof("/start id: %d", ctx.Chat().ID)
-	return ctx.Send(fmt.Sprintf("Hello! Welcome to Toshiki's RSS bot, run /help to view a simplified truncate of help information"))
+	if code := strings.TrimSpace(ctx.Message().Payload); code != "" {
+		return s.useInvite(ctx, code)
+	}
+	registerChat(ctx, s.core, ctx.Chat())
+	return ctx.Send(tr(ctx, "start.welcome"))
+}
+
+// useInvite allowlists the sender with an invite code of the deep link,
+// the user filter lets anyone through with a code so the chat is only recorded once it is valid
+func (s *Start) useInvite(ctx tb.Context, code string) error {
+	err := s.core.UseInvite(context.Background(), code, ctx.Sender().ID)
+	if err == core.ErrInviteNotExist {
+		return ctx.Send(tr(ctx, "start.invite_invalid"))
+	}
+	if err != nil {
+		log.Errorf("user %d use invite failed, %v", ctx.Sender().ID, err)
+		return ctx.Send(tr(ctx, "common.internal_error"))
+	}
+
+	log.Infof("user %d joined with invite %s", ctx.Sender().ID, code)
+	registerChat(ctx, s.core, ctx.Chat())
+	return ctx.Send(tr(ctx, "start.invite_success") + "\n\n" + tr(ctx, "start.welcome"))
 }
 
 func (s *Start) Middlewares() []tb.MiddlewareFunc {
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+	"github.com/andatoshiki/toshiki-rssbot/internal/storage"
+)
+
+const (
+	// staleSourceDays days without new items after which a source is listed as stale
+	staleSourceDays = 30
+	// maxMessageLength max length of a Telegram text message
+	maxMessageLength = 4096
+)
+
+type Stats struct {
+	core *core.Core
+}
+
+func NewStats(core *core.Core) *Stats {
+	return &Stats{core: core}
+}
+
+func (s *Stats) Command() string {
+	return "/stats"
+}
+
+func (s *Stats) Description() string {
+	return "Fetch statistics of the subscribed sources"
+}
+
+func (s *Stats) Handle(ctx tb.Context) error {
+	mentionChat, _ := session.GetMentionChatFromCtxStore(ctx)
+	chatID := ctx.Chat().ID
+	if mentionChat != nil {
+		chatID = mentionChat.ID
+	}
+
+	sources, err := s.core.GetUserSubscribedSources(context.Background(), chatID)
+	if err != nil {
+		return ctx.Reply(tr(ctx, "subscriptions.fetch_failed"))
+	}
+	if len(sources) == 0 {
+		return ctx.Reply(tr(ctx, "subscriptions.none"))
+	}
+
+	sourceIDs := make([]uint, 0, len(sources))
+	for _, source := range sources {
+		sourceIDs = append(sourceIDs, source.ID)
+	}
+	stats, err := s.core.GetSourceFetchStats(context.Background(), sourceIDs)
+	if err != nil {
+		log.Errorf("get chat %d source stats failed, %v", chatID, err)
+		return ctx.Reply(tr(ctx, "stats.failed"))
+	}
+	return sendChunks(ctx, sourceStatsText(ctx, sources, stats))
+}
+
+func (s *Stats) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// sourceStatsText renders the fetch statistics of the sources followed by the stale ones
+func sourceStatsText(ctx tb.Context, sources []*model.Source, stats []*storage.SourceFetchStats) string {
+	statsBySource := make(map[uint]*storage.SourceFetchStats, len(stats))
+	for _, stat := range stats {
+		statsBySource[stat.SourceID] = stat
+	}
+
+	var msg strings.Builder
+	msg.WriteString(tr(ctx, "stats.title", config.FetchHistoryDays))
+	staleBefore := time.Now().AddDate(0, 0, -staleSourceDays)
+	var stale []*model.Source
+	for _, source := range sources {
+		stat, ok := statsBySource[source.ID]
+		if !ok {
+			stat = &storage.SourceFetchStats{SourceID: source.ID}
+		}
+
+		lastNewItem := tr(ctx, "feedset.never")
+		lastActive := source.CreatedAt
+		if source.LastNewItemAt != nil {
+			lastNewItem = source.LastNewItemAt.Format("2006-01-02")
+			lastActive = *source.LastNewItemAt
+		}
+		if lastActive.Before(staleBefore) {
+			stale = append(stale, source)
+		}
+
+		msg.WriteString(fmt.Sprintf("[%d] <a href=\"%s\">%s</a>\n", source.ID, html.EscapeString(source.Link), html.EscapeString(source.Title)))
+		msg.WriteString(
+			tr(
+				ctx, "stats.source", float64(stat.NewItems)/float64(config.FetchHistoryDays), lastNewItem,
+				stat.Fetches, stat.Failures, int64(stat.AvgDurationMs),
+			),
+		)
+		msg.WriteString("\n")
+	}
+
+	if len(stale) > 0 {
+		msg.WriteString("\n")
+		msg.WriteString(tr(ctx, "stats.stale", staleSourceDays))
+		for _, source := range stale {
+			msg.WriteString(fmt.Sprintf("[%d] %s\n", source.ID, html.EscapeString(source.Title)))
+		}
+	}
+	return msg.String()
+}
+
+// sendChunks sends a HTML text split at line breaks into messages Telegram accepts
+func sendChunks(ctx tb.Context, text string) error {
+	opts := &tb.SendOptions{DisableWebPagePreview: true, ParseMode: tb.ModeHTML}
+	var chunk strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if chunk.Len()+len(line) > maxMessageLength && chunk.Len() > 0 {
+			if err := ctx.Send(chunk.String(), opts); err != nil {
+				return err
+			}
+			chunk.Reset()
+		}
+		chunk.WriteString(line)
+	}
+	if chunk.Len() == 0 {
+		return nil
+	}
+	return ctx.Send(chunk.String(), opts)
+}
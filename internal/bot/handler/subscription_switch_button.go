@@ -1,15 +1,12 @@
 package handler
 
 import (
-	"bytes"
 	"context"
-	"text/template"
 
 	tb "gopkg.in/telebot.v3"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
-	"github.com/andatoshiki/toshiki-rssbot/internal/config"
 	"github.com/andatoshiki/toshiki-rssbot/internal/core"
 )
 
@@ -37,7 +34,7 @@ func (b *SubscriptionSwitchButton) Description() string {
 func (b *SubscriptionSwitchButton) Handle(ctx tb.Context) error {
 	c := ctx.Callback()
 	if c == nil {
-		return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
 
 	attachData, err := session.UnmarshalAttachment(ctx.Callback().Data)
@@ -46,35 +43,35 @@ func (b *SubscriptionSwitchButton) Handle(ctx tb.Context) error {
 		// If the subscriber ID is different from the button user's ID, administrator permission needs to be verified.
 		channelChat, err := b.bot.ChatByID(subscriberID)
 		if err != nil {
-			return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 		}
 		if !chat.IsChatAdmin(b.bot, channelChat, c.Sender.ID) {
-			return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 		}
 	}
 
 	sourceID := uint(attachData.GetSourceId())
 	sub, err := b.core.GetSubscription(context.Background(), subscriberID, sourceID)
 	if sub == nil || err != nil {
-		return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
 
 	err = b.core.ToggleSourceUpdateStatus(context.Background(), sourceID)
 	if err != nil {
-		return ctx.Respond(&tb.CallbackResponse{Text: "error"})
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
 	}
 
-	source, _ := b.core.GetSource(context.Background(), sourceID)
-	t := template.New("setting template")
-	_, _ = t.Parse(feedSettingTmpl)
+	source, err := b.core.GetSource(context.Background(), sourceID)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
 
-	text := new(bytes.Buffer)
-	_ = t.Execute(text, map[string]interface{}{"source": source, "sub": sub, "Count": config.ErrorThreshold})
-	_ = ctx.Respond(&tb.CallbackResponse{Text: "Successfully modified"})
+	text := feedSettingText(ctx, b.core, source, sub)
+	_ = ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.modified")})
 	return ctx.Edit(
-		text.String(),
+		text,
 		&tb.SendOptions{ParseMode: tb.ModeHTML},
-		&tb.ReplyMarkup{InlineKeyboard: genFeedSetBtn(c, sub, source)},
+		&tb.ReplyMarkup{InlineKeyboard: genFeedSetBtn(ctx, sub, source)},
 	)
 }
 
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+type Tags struct {
+	core *core.Core
+}
+
+func NewTags(core *core.Core) *Tags {
+	return &Tags{core: core}
+}
+
+func (t *Tags) Command() string {
+	return "/tags"
+}
+
+func (t *Tags) Description() string {
+	return "List tags with their subscription counts"
+}
+
+func (t *Tags) Handle(ctx tb.Context) error {
+	chatID := tagOwnerID(ctx)
+	counts, err := t.core.GetTagCounts(context.Background(), chatID)
+	if err != nil {
+		log.Errorf("get chat %d tag counts failed, %v", chatID, err)
+		return ctx.Reply(tr(ctx, "tags.fetch_failed"))
+	}
+	if len(counts) == 0 {
+		return ctx.Reply(tr(ctx, "tags.empty"))
+	}
+
+	var msg strings.Builder
+	msg.WriteString(tr(ctx, "tags.title"))
+	for _, count := range counts {
+		msg.WriteString(tr(ctx, "tags.item", html.EscapeString(count.Name), count.Count))
+		msg.WriteString("\n")
+	}
+	msg.WriteString("\n")
+	msg.WriteString(tr(ctx, "tags.footer"))
+	return ctx.Reply(msg.String(), &tb.SendOptions{ParseMode: tb.ModeHTML})
+}
+
+func (t *Tags) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
+
+// tagOwnerID gets the chat whose tags a command manages, the mentioned channel if any
+func tagOwnerID(ctx tb.Context) int64 {
+	mentionChat, _ := session.GetMentionChatFromCtxStore(ctx)
+	if mentionChat != nil {
+		return mentionChat.ID
+	}
+	return ctx.Chat().ID
+}
+
+// tagsFromPayload gets the #tag arguments of a tag command in order
+func tagsFromPayload(payload string) []string {
+	var tags []string
+	for _, field := range strings.Fields(payload) {
+		if strings.HasPrefix(field, "#") && len(field) > 1 {
+			tags = append(tags, field)
+		}
+	}
+	return tags
+}
+
+// tagErrorReply replies the failure of a tag command
+func tagErrorReply(ctx tb.Context, err error, tag string, failedKey string) error {
+	if err == core.ErrTagNotExist {
+		return ctx.Reply(tr(ctx, "tag.not_found", tag))
+	}
+	log.Errorf("%s %s failed, %v", strings.TrimSuffix(failedKey, ".failed"), tag, err)
+	return ctx.Reply(tr(ctx, failedKey))
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const (
+	UndoUnsubscribeButtonUnique = "undo_unsub_btn"
+)
+
+// undoUnsubscribeMarkup returns the Undo button of an unsubscription confirmation,
+// it restores the subscriptions of the chat removed by the removal removalID
+func undoUnsubscribeMarkup(ctx tb.Context, userID int64, removalID uint32) *tb.ReplyMarkup {
+	attachData := &session.Attachment{
+		UserId:    userID,
+		RemovalId: removalID,
+	}
+	return &tb.ReplyMarkup{
+		InlineKeyboard: [][]tb.InlineButton{
+			{
+				{
+					Unique: UndoUnsubscribeButtonUnique,
+					Text:   tr(ctx, "unsub.undo"),
+					Data:   session.Marshal(attachData),
+				},
+			},
+		},
+	}
+}
+
+// UndoUnsubscribeButton restores the subscriptions removed by an unsubscription within the undo period
+type UndoUnsubscribeButton struct {
+	core *core.Core
+}
+
+func NewUndoUnsubscribeButton(core *core.Core) *UndoUnsubscribeButton {
+	return &UndoUnsubscribeButton{core: core}
+}
+
+func (b *UndoUnsubscribeButton) CallbackUnique() string {
+	return "\f" + UndoUnsubscribeButtonUnique
+}
+
+func (b *UndoUnsubscribeButton) Description() string {
+	return ""
+}
+
+func (b *UndoUnsubscribeButton) Handle(ctx tb.Context) error {
+	c := ctx.Callback()
+	if c == nil || c.Message == nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+	attachData, err := session.UnmarshalAttachment(c.Data)
+	if err != nil {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.error")})
+	}
+
+	// the unsubscription may be made for a channel managed from this chat
+	userID := attachData.GetUserId()
+	targetChat := c.Message.Chat
+	if userID != targetChat.ID {
+		if targetChat, err = ctx.Bot().ChatByID(userID); err != nil {
+			return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+		}
+	}
+	if !chat.IsChatAdmin(ctx.Bot(), targetChat, c.Sender.ID) {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.permission_denied")})
+	}
+
+	removalID := attachData.GetRemovalId()
+	restored, err := b.core.UndoUnsubscribe(context.Background(), userID, removalID)
+	if err != nil {
+		log.Errorf("%d undo unsubscribe %d failed, %v", userID, removalID, err)
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "common.internal_error")})
+	}
+	if restored == 0 {
+		return ctx.Respond(&tb.CallbackResponse{Text: tr(ctx, "unsub.undo_expired"), ShowAlert: true})
+	}
+
+	log.Infof("%d undo unsubscribe %d, %d subscriptions restored", userID, removalID, restored)
+	if err := ctx.Respond(); err != nil {
+		log.Errorf("respond undo callback failed, %v", err)
+	}
+	return ctx.Edit(tr(ctx, "unsub.undone", restored))
+}
+
+func (b *UndoUnsubscribeButton) Middlewares() []tb.MiddlewareFunc {
+	return nil
+}
@@ -0,0 +1,295 @@
+package i18n
+
+// en English messages, every key used by the handlers must be defined here
+var en = map[string]string{
+	"action.content_gone": "The content is no longer available",
+	"action.mute":         "Mute",
+	"action.pause":        "Pause",
+	"action.resume":       "Resume",
+	"action.save":         "Save",
+	"action.save_failed":  "Failed to save",
+	"action.save_success": "Saved, use /saved to read later",
+	"action.saved_exist":  "Already saved",
+	"action.unmute":       "Unmute",
+	"action.unsubscribe":  "Unsubscribe",
+
+	"activeall.channel_success": "Channel [%s](https://t.me/%s) has enabled and activated all feed subscription sources update",
+	"activeall.failed":          "Activation failed",
+	"activeall.success":         "All subscriptions has been enabled and activated",
+	"activeall.tag_success":     "%d subscription(s) tagged %s have been activated",
+
+	"admin.allowed":             "User %d is allowed to use the bot",
+	"admin.allowlist_disabled":  "The allowlist is disabled in the config, everyone may use the bot",
+	"admin.broadcast_done":      "Broadcast finished, %d sent, %d failed",
+	"admin.broadcast_started":   "Broadcasting to %d chats",
+	"admin.chat":                "%d: %d subscription(s)",
+	"admin.chat_migrate_failed": "Failed to move the subscriptions of %s (%d) to its supergroup %d, check the logs",
+	"admin.chat_migrated":       "%s (%d) was upgraded to the supergroup %d, %d subscription(s) moved",
+	"admin.chat_removed":        "The bot was removed from %s (%d), %d subscription(s) removed",
+	"admin.chats_title":         "<b>%d chats with subscriptions</b>\n",
+	"admin.denied":              "User %d is no longer allowed to use the bot",
+	"admin.failed":              "Admin command failed",
+	"admin.invite":              "One-time invite link, whoever starts the bot with it is allowed to use the bot:\n%s",
+	"admin.no_chats":            "No chat has subscriptions",
+	"admin.no_users":            "The allowlist is empty",
+	"admin.paused":              "Paused source [%d] %s for all subscribers",
+	"admin.resumed":             "Resumed source [%d] %s for all subscribers",
+	"admin.source_subscribers":  "    %d subscriber(s), %d errors",
+	"admin.sources_title":       "<b>%d sources</b>\n",
+	"admin.usage":               "/admin sources lists all sources\n/admin chats lists the chats with subscriptions\n/admin pause [source_id] pauses a source for everyone, /admin resume [source_id] resumes it\n/admin broadcast [text] sends a notice to all chats\n/admin stats shows the fetch statistics of all sources\n/admin users lists the allowlist, /admin allow [user_id] and /admin deny [user_id] edit it\n/admin invite creates a one-time invite link",
+	"admin.users_title":         "<b>%d allowed users</b>\n",
+
+	"backup.caption": "Backup of %d subscription(s), reply to this file with /restore to restore it",
+	"backup.failed":  "Failed to back up the subscriptions",
+
+	"buttons.disabled": "Pushed messages will no longer carry action buttons",
+	"buttons.enabled":  "Pushed messages will carry mute, pause and unsubscribe buttons",
+	"buttons.failed":   "Failed to configure action buttons",
+
+	"channel.admins_failed": "Failed to fetch information of channel administrator",
+	"channel.fetch_failed":  "Failed to fetch channel information",
+	"channel.not_admin":     "Bot operational executions by non-administrative users are not permitted",
+
+	"common.cancel":            "Cancel",
+	"common.cancelled":         "Cancel current operations",
+	"common.confirm":           "Confirm",
+	"common.error":             "error",
+	"common.internal_error":    "Internal service error",
+	"common.menu_expired":      "This menu has expired, please send the command again",
+	"common.modified":          "Successfully modified",
+	"common.next":              "Next",
+	"common.not_chat_admin":    "You are not the administrator of the current session",
+	"common.permission_denied": "Permission or access rights not granted",
+	"common.previous":          "Previous",
+
+	"deltag.failed":  "Failed to delete the tag",
+	"deltag.success": "Removed tag %s from %d subscription(s)",
+	"deltag.usage":   "Usage: /deltag #tag",
+
+	"export.failed": "Failed to export",
+
+	"feedlink.found":             "Found a feed:\n%s\n<b>%s</b>",
+	"feedlink.preview":           "Preview",
+	"feedlink.subscribe_channel": "Subscribe to channel %s",
+	"feedlink.subscribe_here":    "Subscribe here",
+
+	"feedset.btn_back":          "Back",
+	"feedset.btn_back_list":     "Back to subscriptions",
+	"feedset.btn_default":       "Default",
+	"feedset.btn_edit_off":      "Disable edit on update",
+	"feedset.btn_edit_on":       "Enable edit on update",
+	"feedset.btn_hide":          "Hide",
+	"feedset.btn_interval":      "Update interval",
+	"feedset.btn_notice_off":    "Disable notification",
+	"feedset.btn_notice_on":     "Enable notification",
+	"feedset.btn_pause":         "Pause update",
+	"feedset.btn_preview":       "Preview",
+	"feedset.btn_restart":       "Restart update",
+	"feedset.btn_show":          "Show",
+	"feedset.btn_tags":          "Configure tags",
+	"feedset.btn_telegraph_off": "Disable Telegraph transcoding",
+	"feedset.btn_telegraph_on":  "Enable Telegraph transcoding",
+	"feedset.btn_web_default":   "Default web preview",
+	"feedset.characters":        "%d characters",
+	"feedset.default":           "Default (%v)",
+	"feedset.disable":           "Disable",
+	"feedset.enable":            "Enable",
+	"feedset.hidden":            "Hidden",
+	"feedset.never":             "Never",
+	"feedset.template": `
+Subscription<b>Setting</b>
+[id] {{ .source.ID }}
+[Title] {{ .source.Title }}
+[Link] {{.source.Link }}
+[Interval] {{if ge .source.ErrorCount .Count }}Pause{{else if lt .source.ErrorCount .Count }}Fetching in progress{{end}}
+[Frequency] {{ .sub.Interval }}minute(s)
+[Preview length] {{ .previewLength }}
+[Web page preview] {{ .webPagePreview }}
+[Notification] {{if eq .sub.EnableNotification 0}}Disable{{else if eq .sub.EnableNotification 1}}Enable{{end}}
+[Telegraph] {{if eq .sub.EnableTelegraph 0}}Disable{{else if eq .sub.EnableTelegraph 1}}Enable{{end}}
+[Edit on update] {{if eq .sub.EnableEditUpdate 0}}Disable{{else if eq .sub.EnableEditUpdate 1}}Enable{{end}}
+[Tag] {{if .sub.Tag}}{{ .sub.Tag }}{{else}}None{{end}}
+[Subscribers] {{ .subscriberCount }}
+[Last fetch] {{ .lastFetch }}
+[Last error] {{if .source.LastError}}{{ .lastError }}{{else}}None{{end}}
+`,
+
+	"help.text": `	/sub Subscribe an RSS feed source to your feed list
+	/preview See how a feed would look before subscribing
+	Send a link in private, or mention the bot with it in a group, to subscribe without a command
+	/unsub  Remove a subscription source from your existing feed list, /unsub #tag to remove a tag
+	/list View all existing subscription sources, /list #tag to filter by tag
+	/set Configure & manage subscription list
+	/check Inspect the existing subscribed feed list status
+	/setfeedtag Append a custom tag to a subscription source
+	/tags List tags, /renametag, /mergetag and /deltag to manage them
+	/setinterval Configure the refresh interval for a subscription source
+	/settopic Deliver a subscription source to a forum topic
+	/stats View fetch statistics of your subscription sources
+	/buttons Toggle mute, pause and unsubscribe buttons on pushed messages
+	/lang Choose the reply language of the chat
+	/search Search received items, filter with source:id since:7d until:2006-01-02
+	@bot keywords Search & share items of your subscriptions in any chat (inline mode)
+	/saved View & export your read later list
+	/unsave Remove an item from your read later list
+	/activeall Resume & enable all existing subscription sources, /activeall #tag to resume a tag
+	/pauseall Pause & terminate all existing subscription sources, /pauseall #tag to pause a tag
+	/help View help & support information
+	/import Import your subscription list to an OPML file
+	/export Export your subscription list to an OPML file, /export #tag to export a tag
+	/backup Back up subscriptions and settings, reply to the file with /restore to restore it
+	/unsuball Remove and cancel all existing subscriptions
+	Visit for more detailed bot usage & affiliated documentation at https://note.toshiki.dev/
+	Made with love and coffee by @andatoshiki at @toshikidev in Arizona State University & proudly open sourced on GitLab
+`,
+
+	"import.failed":       "<b>Failed to import all subscription sources below:</b>\n",
+	"import.failed_more":  "…and %d more\n",
+	"import.file_failed":  "Failed to fetch file attachment",
+	"import.hint":         "Please send your OPML file, a Miniflux JSON export or a .txt list of feed urls, one per line, as a direct message to the bot; if you need to import feeds on behalf of your channel, please affix the channel ID with the attachment; eg: @toshikidev",
+	"import.invalid_file": "Please send an OPML file, a Miniflux JSON export or a .txt list of feed urls",
+	"import.progress":     "Importing, %d/%d done, %d failed",
+	"import.retry_all":    "Retry all",
+	"import.retry_done":   "All failed sources were imported",
+	"import.retrying":     "Retrying %d source(s)",
+	"import.succeeded":    "<b>Successfully imported all subscription sources below:</b>\n",
+	"import.summary":      "<b>Number of successfully imported sources: %d, numbers of failed sources imported: %d</b>\n",
+
+	"lang.auto":         "Auto",
+	"lang.auto_success": "The reply language follows your Telegram language, currently %s",
+	"lang.failed":       "Failed to configure the reply language",
+	"lang.name":         "English",
+	"lang.select":       "Please select the reply language of the chat",
+	"lang.success":      "The reply language of the chat has been set to %s",
+	"lang.usage":        "Please utilize `/lang [%s|auto]` command to choose the reply language",
+
+	"list.empty":     "The current subscription list is empty",
+	"list.muted":     "muted",
+	"list.no_more":   "No more subscriptions",
+	"list.paused":    "paused",
+	"list.tag_title": "Subscription list of %s (page %d)\n",
+	"list.title":     "Subscription list, page %d\n",
+
+	"mergetag.failed":    "Failed to merge the tags",
+	"mergetag.not_found": "Both %s and %s must be existing tags",
+	"mergetag.success":   "Merged %d subscription(s) of tag %s into %s",
+	"mergetag.usage":     "Usage: /mergetag #from #to",
+
+	"pauseall.channel_success": "All subscriptions of channel [%s](https://t.me/%s) have been completely paused and terminated",
+	"pauseall.failed":          "Failed to pause",
+	"pauseall.pause_skipped":   "<b>%d feed(s) were left running, other chats subscribe to them as well</b>\n",
+	"pauseall.success":         "All subscription updates have been paused and terminated",
+	"pauseall.tag_success":     "%d subscription(s) tagged %s have been paused",
+
+	"ping.pong": "Ping request successfully received and responds `pong`",
+
+	"preview.failed":    "Failed to fetch the feed, %s",
+	"preview.subscribe": "Subscribe",
+	"preview.summary":   "<b>Feed preview</b>\n%s\nTitle: %s\nType: %s\nItems: %d\nLast published: %s",
+	"preview.unknown":   "unknown",
+	"preview.usage":     "Please append the feed url to preview at the end of the command; e.g.: %s https://github.blog/feed/",
+
+	"renametag.exists":  "Tag %s already exists, use /mergetag to merge into it",
+	"renametag.failed":  "Failed to rename the tag",
+	"renametag.success": "Renamed tag %s to %s on %d subscription(s)",
+	"renametag.usage":   "Usage: /renametag #old #new",
+
+	"restore.done":          "<b>Restore finished: %d added, %d updated, %d removed, %d failed</b>\n",
+	"restore.dry_run":       "Backup of %d subscription(s) made at %s\nMerge adds %d and updates %d subscription(s)\nReplace also removes the %d subscription(s) missing from the backup",
+	"restore.failed":        "Failed to restore the backup",
+	"restore.hint":          "This looks like a backup, reply to it with /restore to restore it",
+	"restore.invalid_file":  "Invalid backup file, %s",
+	"restore.merge":         "Merge",
+	"restore.pause_skipped": "<b>%d paused feeds were left running, other chats subscribe to them as well</b>\n",
+	"restore.replace":       "Replace",
+	"restore.restoring":     "Restoring the backup…",
+	"restore.usage":         "Reply to a /backup file with /restore to see what it changes, or with /restore merge or /restore replace to restore it",
+
+	"saved.empty":        "The read later list is currently empty",
+	"saved.export_usage": "Please utilize `/saved export md` or `/saved export html` command",
+	"saved.fetch_failed": "Failed to fetch the read later list",
+	"saved.footer":       "Use /unsave [id] to remove an item",
+	"saved.title":        "Total of %d saved item(s), page %d\n",
+
+	"search.expired":        "The search request is no longer available",
+	"search.failed":         "Failed to search",
+	"search.no_result":      "No matching items found",
+	"search.not_subscribed": "The subscription source does not exist in your subscription list",
+	"search.title":          "Search results, page %d\n",
+	"search.usage":          "Please utilize `/search [source:id] [since:2006-01-02|7d] [until:2006-01-02] keywords` command to search",
+
+	"set.fetch_failed":     "Failed to fetch subscription information",
+	"set.select":           "Please select the target subscription to configure",
+	"set.source_not_found": "Unable to find target subscription sources",
+
+	"setfeedtag.button_hint": "Please utilize `/setfeedtag %d tags` command to configure topic tags for the subscription source, `tags` indicates the target tags to be configured. A maximum of 3 tags could be appended to each feed source and tags are required to split by spaces to match the internal grammatical syntax of the bot respectfully \nE.g.:`/setfeedtag %d anime moe`",
+	"setfeedtag.failed":      "Failed to set tag(s) for subscription feed!",
+	"setfeedtag.success":     "Successfully set tag(s) for subscription feed!",
+	"setfeedtag.usage":       "/setfeedtag `[source_id] [tag1] [tag2]` to set tags for subscription feeds; a maximum of 3 tags could be appended to each feed source and tags are required to split by spaces to match the internal grammatical syntax of the bot respectfully",
+
+	"setinterval.failed":  "Failed to configure time intervals!",
+	"setinterval.invalid": "Please enter the correct update time interval",
+	"setinterval.success": "Successfully configured time intervals!",
+	"setinterval.usage":   "/setinterval [interval] [source_id] Configure the refresh or update interval for subscription feeds, default unit of time is minute (Configuration for multiple sub_id is allowed by splitting with spaces)",
+
+	"settopic.create_failed": "Failed to create forum topics, please make sure the bot is allowed to manage topics",
+	"settopic.created":       "Created %d forum topic(s) for subscription feeds",
+	"settopic.failed":        "Failed to configure forum topic!",
+	"settopic.general":       "Subscription feeds will be delivered to the General topic",
+	"settopic.not_forum":     "Forum topics are only available in supergroups with topics enabled",
+	"settopic.success":       "Subscription feeds will be delivered to this topic",
+	"settopic.usage":         "/settopic [source_id] run inside a topic delivers the subscription feeds to that topic, run inside the General topic to restore the default delivery (Configuration for multiple source_id is allowed by splitting with spaces)\n/settopic auto creates a topic named after the feed for every subscription not bound to a topic yet",
+
+	"source.update_paused": "[%s](%s) has failed to update %d times in a row, its updates are paused",
+
+	"start.invite_invalid": "The invite link is invalid or was already used",
+	"start.invite_success": "Invite accepted, you can use the bot now",
+	"start.welcome":        "Hello! Welcome to Toshiki's RSS bot, run /help to view a simplified truncate of help information",
+
+	"stats.cycles":     "<b>Last fetch cycle</b> at %s: %d sources, %d failed, took %dms\nAverage of the last %d cycles: %dms",
+	"stats.failed":     "Failed to fetch the statistics",
+	"stats.no_sources": "No sources yet",
+	"stats.source":     "    %.1f items/day, last new item %s, %d fetches, %d failed, %dms on average",
+	"stats.stale":      "<b>No new items in %d days</b>\n",
+	"stats.title":      "<b>Fetch statistics of the last %d days</b>\n",
+
+	"sub.channel_no_privilege": "Either you or the bot is currently not the administrator of the channel provided, failed to configure subscription",
+	"sub.channel_usage":        "Please run ' /sub `@channel_id` URL ' command for subscription on behalf of a specific channel; e.g.: @toshikidev",
+	"sub.exist":                "Source subscribed and exist in present feed list already, please do not repeatedly duplicate subscription",
+	"sub.failed":               "Failed to subscribe from source",
+	"sub.source_failed":        "%s, failed to subscribe",
+	"sub.success":              "[[%d]] [%s](%s) Successfully subscribed from source to feed list",
+	"sub.usage":                "Please append the target subscription url for RSS feed at the end of the command; e.g.: %s https://github.blog/feed/",
+
+	"subscriptions.fetch_failed": "Failed to fetch subscription list",
+	"subscriptions.none":         "Currently no active subscriptions",
+
+	"tag.no_subscriptions": "No subscriptions are tagged %s",
+	"tag.not_found":        "Tag %s does not exist",
+
+	"tags.empty":        "No tags yet, use /setfeedtag to tag subscriptions",
+	"tags.fetch_failed": "Failed to fetch tags",
+	"tags.footer":       "/list #tag views a tag, /renametag #old #new, /mergetag #from #to and /deltag #tag manage tags",
+	"tags.item":         "#%s: %d subscription(s)",
+	"tags.title":        "<b>Tags</b>\n",
+
+	"unsave.failed":    "Failed to remove the saved item",
+	"unsave.not_exist": "The saved item does not exist",
+	"unsave.success":   "Successfully removed the saved item",
+	"unsave.usage":     "Please utilize `/unsave [id]` command to remove a saved item",
+
+	"unsub.channel_success": "Successfully unsubscribed [%s](%s) from channel [%s](https://t.me/%s)",
+	"unsub.channel_usage":   "Please utilize `/unsub @channel_id URL` or `/unsub @channel_id #tag` command to unsubscribe if you need to unsubscribe on behalf of your channel",
+	"unsub.chat_success":    "[%s](%s) Successfully unsubscribed!",
+	"unsub.failed":          "Failed to unsubscribe",
+	"unsub.item_success":    "[%d] <a href=\"%s\">%s</a> Successfully unsubscribed",
+	"unsub.not_subscribed":  "RSS feed not subscribed",
+	"unsub.select":          "Please select the feed sources to unsubscribe",
+	"unsub.success":         "Successfully unsubscribed",
+	"unsub.tag_success":     "Successfully unsubscribed %d subscription(s) tagged %s",
+	"unsub.undo":            "Undo",
+	"unsub.undo_expired":    "The undo period has ended or the feed was subscribed again, please subscribe again if needed",
+	"unsub.undone":          "Restored %d subscription(s)",
+
+	"unsuball.confirm": "Unsubscribe all subscription feeds for the current user",
+}
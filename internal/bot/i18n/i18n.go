@@ -0,0 +1,58 @@
+// Package i18n holds the message catalogs of the bot replies
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	English  = "en"
+	Chinese  = "zh"
+	Japanese = "ja"
+
+	// Default language used when a chat has no preference and the user language is not supported
+	Default = English
+)
+
+// Languages supported languages, in the order they are offered to users
+var Languages = []string{English, Chinese, Japanese}
+
+var catalogs = map[string]map[string]string{
+	English:  en,
+	Chinese:  zh,
+	Japanese: ja,
+}
+
+// Normalize maps a Telegram language code like zh-hans to a supported language, empty if not supported
+func Normalize(code string) string {
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := catalogs[code]; ok {
+		return code
+	}
+	return ""
+}
+
+// Lookup gets the message of a key in a language without falling back
+func Lookup(lang string, key string) (string, bool) {
+	msg, ok := catalogs[lang][key]
+	return msg, ok
+}
+
+// T translates a key to the language, formatting the args into the message.
+// Messages missing in the language fall back to the default language, then to the key itself.
+func T(lang string, key string, args ...interface{}) string {
+	msg, ok := Lookup(lang, key)
+	if !ok {
+		if msg, ok = Lookup(Default, key); !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
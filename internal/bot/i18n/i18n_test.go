@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z]`)
+
+func TestCatalogs(t *testing.T) {
+	for _, lang := range Languages {
+		t.Run(lang, func(t *testing.T) {
+			for key, msg := range en {
+				translated, ok := Lookup(lang, key)
+				if !ok {
+					t.Errorf("key %s is missing", key)
+					continue
+				}
+				if want, got := len(verbPattern.FindAllString(msg, -1)), len(verbPattern.FindAllString(translated, -1)); got != want {
+					t.Errorf("key %s has %d format verbs, want %d", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"english", "en", English},
+		{"region", "en-US", English},
+		{"script", "zh-hans", Chinese},
+		{"upper", "JA", Japanese},
+		{"unsupported", "fr", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.code); got != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		key  string
+		args []interface{}
+		want string
+	}{
+		{"translated", Chinese, "common.confirm", nil, "确认"},
+		{"format", English, "list.title", []interface{}{2}, "Subscription list, page 2\n"},
+		{"unknown language", "fr", "common.confirm", nil, "Confirm"},
+		{"unknown key", Japanese, "missing.key", nil, "missing.key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := T(tt.lang, tt.key, tt.args...); got != tt.want {
+				t.Errorf("T() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package i18n
+
+// ja Japanese messages, command.* keys localize the command descriptions
+var ja = map[string]string{
+	"action.content_gone": "このコンテンツはもう利用できません",
+	"action.mute":         "ミュート",
+	"action.pause":        "一時停止",
+	"action.resume":       "再開",
+	"action.save":         "あとで読む",
+	"action.save_failed":  "保存に失敗しました",
+	"action.save_success": "保存しました。/saved であとで読めます",
+	"action.saved_exist":  "すでに保存されています",
+	"action.unmute":       "ミュート解除",
+	"action.unsubscribe":  "購読解除",
+
+	"activeall.channel_success": "チャンネル [%s](https://t.me/%s) のすべての購読の更新を有効にしました",
+	"activeall.failed":          "有効化に失敗しました",
+	"activeall.success":         "すべての購読の更新を有効にしました",
+	"activeall.tag_success":     "タグ %[2]s の購読 %[1]d 件を有効にしました",
+
+	"admin.allowed":             "ユーザー %d が Bot を使えるようにしました",
+	"admin.allowlist_disabled":  "設定で許可リストが無効になっています。誰でも Bot を使えます",
+	"admin.broadcast_done":      "一斉送信が完了しました。成功 %d 件、失敗 %d 件",
+	"admin.broadcast_started":   "%d 件のチャットに一斉送信しています",
+	"admin.chat":                "%d：購読 %d 件",
+	"admin.chat_migrate_failed": "%s（%d）の購読をスーパーグループ %d へ移行できませんでした。ログを確認してください",
+	"admin.chat_migrated":       "%s（%d）はスーパーグループ %d にアップグレードされ、購読 %d 件を移行しました",
+	"admin.chat_removed":        "Bot は %s（%d）から削除され、購読 %d 件を削除しました",
+	"admin.chats_title":         "<b>購読のあるチャット %d 件</b>\n",
+	"admin.denied":              "ユーザー %d が Bot を使えないようにしました",
+	"admin.failed":              "管理コマンドに失敗しました",
+	"admin.invite":              "1 回限りの招待リンクです。このリンクで Bot を開始したユーザーが Bot を使えるようになります：\n%s",
+	"admin.no_chats":            "購読のあるチャットはありません",
+	"admin.no_users":            "許可リストは空です",
+	"admin.paused":              "すべての購読者のフィード [%d] %s を一時停止しました",
+	"admin.resumed":             "すべての購読者のフィード [%d] %s を再開しました",
+	"admin.source_subscribers":  "    購読者 %d 人、エラー %d 回",
+	"admin.sources_title":       "<b>フィード %d 件</b>\n",
+	"admin.usage":               "/admin sources すべてのフィードを表示\n/admin chats 購読のあるチャットを表示\n/admin pause [source_id] フィードを全員分一時停止、/admin resume [source_id] で再開\n/admin broadcast [text] すべてのチャットにお知らせを送信\n/admin stats すべてのフィードの取得統計を表示\n/admin users 許可リストを表示、/admin allow [user_id] と /admin deny [user_id] で編集\n/admin invite 1 回限りの招待リンクを作成",
+	"admin.users_title":         "<b>許可ユーザー %d 人</b>\n",
+
+	"backup.caption": "購読 %d 件のバックアップです。このファイルに /restore で返信すると復元できます",
+	"backup.failed":  "購読をバックアップできませんでした",
+
+	"buttons.disabled": "配信メッセージに操作ボタンを付けなくなります",
+	"buttons.enabled":  "配信メッセージにミュート・一時停止・購読解除ボタンを付けます",
+	"buttons.failed":   "操作ボタンの設定に失敗しました",
+
+	"channel.admins_failed": "チャンネル管理者の情報を取得できませんでした",
+	"channel.fetch_failed":  "チャンネル情報を取得できませんでした",
+	"channel.not_admin":     "管理者以外のユーザーはこの操作を実行できません",
+
+	"command.activeall":   "すべての購読を再開する",
+	"command.admin":       "Bot 管理、/admin sources|chats|pause|resume|broadcast|stats|users|allow|deny|invite",
+	"command.backup":      "購読と設定を JSON ファイルにバックアップする",
+	"command.buttons":     "配信メッセージの操作ボタンを切り替える",
+	"command.deltag":      "すべての購読からタグを削除する、/deltag #タグ",
+	"command.export":      "購読を OPML にエクスポートする",
+	"command.help":        "ヘルプ",
+	"command.import":      "OPML ファイルをインポートする",
+	"command.lang":        "このチャットの返信言語を選択する",
+	"command.list":        "購読一覧を表示する",
+	"command.mergetag":    "タグを統合する、/mergetag #元 #先",
+	"command.pauseall":    "すべての購読を一時停止する",
+	"command.ping":        "Bot の応答を確認する",
+	"command.preview":     "購読前にフィードをプレビューする",
+	"command.renametag":   "タグの名前を変更する、/renametag #旧 #新",
+	"command.restore":     "バックアップを復元する、ファイルに /restore [merge|replace] で返信",
+	"command.saved":       "あとで読むリスト、/saved export [md|html] でエクスポート",
+	"command.search":      "購読で受信した記事を検索する",
+	"command.set":         "購読を設定する",
+	"command.setfeedtag":  "購読にタグを設定する",
+	"command.setinterval": "購読の更新間隔を設定する",
+	"command.settopic":    "購読をフォーラムのトピックに配信する",
+	"command.start":       "Bot の利用を開始する",
+	"command.stats":       "購読フィードの取得統計を表示する",
+	"command.sub":         "RSS フィードを購読する",
+	"command.tags":        "タグと購読数を表示する",
+	"command.unsave":      "あとで読むリストから削除する",
+	"command.unsub":       "RSS フィードの購読を解除する",
+	"command.unsuball":    "すべての購読を解除する",
+	"command.version":     "Bot のバージョン情報",
+
+	"common.cancel":            "キャンセル",
+	"common.cancelled":         "操作をキャンセルしました",
+	"common.confirm":           "確認",
+	"common.error":             "エラー",
+	"common.internal_error":    "内部サービスエラー",
+	"common.menu_expired":      "このメニューは期限切れです。もう一度コマンドを送信してください",
+	"common.modified":          "変更しました",
+	"common.next":              "次へ",
+	"common.not_chat_admin":    "あなたはこのチャットの管理者ではありません",
+	"common.permission_denied": "操作する権限がありません",
+	"common.previous":          "前へ",
+
+	"deltag.failed":  "タグを削除できませんでした",
+	"deltag.success": "%[2]d 件の購読からタグ %[1]s を削除しました",
+	"deltag.usage":   "使い方：/deltag #タグ",
+
+	"export.failed": "エクスポートに失敗しました",
+
+	"feedlink.found":             "フィードが見つかりました：\n%s\n<b>%s</b>",
+	"feedlink.preview":           "プレビュー",
+	"feedlink.subscribe_channel": "チャンネル %s で購読する",
+	"feedlink.subscribe_here":    "ここで購読する",
+
+	"feedset.btn_back":          "戻る",
+	"feedset.btn_back_list":     "購読一覧に戻る",
+	"feedset.btn_default":       "デフォルト",
+	"feedset.btn_edit_off":      "更新時の編集を無効化",
+	"feedset.btn_edit_on":       "更新時の編集を有効化",
+	"feedset.btn_hide":          "非表示",
+	"feedset.btn_interval":      "更新間隔",
+	"feedset.btn_notice_off":    "通知を無効化",
+	"feedset.btn_notice_on":     "通知を有効化",
+	"feedset.btn_pause":         "更新を一時停止",
+	"feedset.btn_preview":       "プレビュー",
+	"feedset.btn_restart":       "更新を再開",
+	"feedset.btn_show":          "表示",
+	"feedset.btn_tags":          "タグを設定",
+	"feedset.btn_telegraph_off": "Telegraph 変換を無効化",
+	"feedset.btn_telegraph_on":  "Telegraph 変換を有効化",
+	"feedset.btn_web_default":   "デフォルトのウェブプレビュー",
+	"feedset.characters":        "%d 文字",
+	"feedset.default":           "デフォルト（%v）",
+	"feedset.disable":           "無効",
+	"feedset.enable":            "有効",
+	"feedset.hidden":            "非表示",
+	"feedset.never":             "なし",
+	"feedset.template": `
+購読<b>設定</b>
+[id] {{ .source.ID }}
+[タイトル] {{ .source.Title }}
+[リンク] {{.source.Link }}
+[取得状態] {{if ge .source.ErrorCount .Count }}一時停止{{else if lt .source.ErrorCount .Count }}取得中{{end}}
+[取得間隔] {{ .sub.Interval }}分
+[プレビューの長さ] {{ .previewLength }}
+[ウェブプレビュー] {{ .webPagePreview }}
+[通知] {{if eq .sub.EnableNotification 0}}無効{{else if eq .sub.EnableNotification 1}}有効{{end}}
+[Telegraph] {{if eq .sub.EnableTelegraph 0}}無効{{else if eq .sub.EnableTelegraph 1}}有効{{end}}
+[更新時の編集] {{if eq .sub.EnableEditUpdate 0}}無効{{else if eq .sub.EnableEditUpdate 1}}有効{{end}}
+[タグ] {{if .sub.Tag}}{{ .sub.Tag }}{{else}}なし{{end}}
+[購読者数] {{ .subscriberCount }}
+[最終取得] {{ .lastFetch }}
+[最終エラー] {{if .source.LastError}}{{ .lastError }}{{else}}なし{{end}}
+`,
+
+	"help.text": `	/sub RSS フィードを購読する
+	/preview 購読前にフィードの表示を確認する
+	プライベートでリンクを送るか、グループで Bot をメンションしてリンクを送ると、コマンドなしで購読できます
+	/unsub  RSS フィードの購読を解除する、/unsub #tag でタグごと解除
+	/list 購読一覧を表示する、/list #タグ でタグ絞り込み
+	/set 購読を設定する
+	/check 購読の状態を確認する
+	/tags タグを表示する、/renametag・/mergetag・/deltag でタグを管理
+	/setfeedtag 購読にタグを設定する
+	/setinterval 購読の更新間隔を設定する
+	/settopic 購読をフォーラムのトピックに配信する
+	/stats 購読フィードの取得統計を表示する
+	/buttons 配信メッセージのミュート・一時停止・購読解除ボタンを切り替える
+	/lang このチャットの返信言語を選択する
+	/search 受信した記事を検索する、source:id since:7d until:2006-01-02 で絞り込み
+	@bot キーワード 任意のチャットで購読記事を検索・共有する（インラインモード）
+	/saved あとで読むリストを表示・エクスポートする
+	/unsave あとで読むリストから削除する
+	/activeall すべての購読を再開する、/activeall #tag でタグごと再開
+	/pauseall すべての購読を一時停止する、/pauseall #tag でタグごと一時停止
+	/help ヘルプを表示する
+	/import OPML ファイルをインポートする
+	/export OPML ファイルにエクスポートする、/export #tag でタグごとエクスポート
+	/backup 購読と設定をバックアップする、ファイルに /restore で返信すると復元
+	/unsuball すべての購読を解除する
+	詳しい使い方は https://note.toshiki.dev/ をご覧ください
+	Made with love and coffee by @andatoshiki at @toshikidev in Arizona State University & proudly open sourced on GitLab
+`,
+
+	"import.failed":       "<b>以下の購読のインポートに失敗しました：</b>\n",
+	"import.failed_more":  "…ほか %d 件\n",
+	"import.file_failed":  "ファイルを取得できませんでした",
+	"import.hint":         "OPML ファイル、Miniflux の JSON エクスポート、または 1 行に 1 つの URL を書いた .txt リストを Bot に直接送信してください。チャンネル用にインポートする場合は、ファイルにチャンネル ID を添えてください。例：@toshikidev",
+	"import.invalid_file": "OPML ファイル、Miniflux の JSON エクスポート、または .txt の URL リストを送信してください",
+	"import.progress":     "インポート中、%d/%d 完了、失敗 %d",
+	"import.retry_all":    "すべて再試行",
+	"import.retry_done":   "失敗した購読はすべてインポートされました",
+	"import.retrying":     "%d 件の購読を再試行しています",
+	"import.succeeded":    "<b>以下の購読のインポートに成功しました：</b>\n",
+	"import.summary":      "<b>インポート成功：%d、インポート失敗：%d</b>\n",
+
+	"lang.auto":         "自動",
+	"lang.auto_success": "返信言語は Telegram の言語に従います。現在は%sです",
+	"lang.failed":       "返信言語の設定に失敗しました",
+	"lang.name":         "日本語",
+	"lang.select":       "このチャットの返信言語を選択してください",
+	"lang.success":      "このチャットの返信言語を%sに設定しました",
+	"lang.usage":        "`/lang [%s|auto]` コマンドで返信言語を選択してください",
+
+	"list.empty":     "購読一覧は空です",
+	"list.muted":     "ミュート中",
+	"list.no_more":   "これ以上の購読はありません",
+	"list.paused":    "一時停止中",
+	"list.tag_title": "%s の購読一覧、%d ページ目\n",
+	"list.title":     "購読一覧、%d ページ目\n",
+
+	"mergetag.failed":    "タグを統合できませんでした",
+	"mergetag.not_found": "%s と %s はどちらも既存のタグである必要があります",
+	"mergetag.success":   "タグ %[2]s の購読 %[1]d 件を %[3]s に統合しました",
+	"mergetag.usage":     "使い方：/mergetag #元タグ #統合先タグ",
+
+	"pauseall.channel_success": "チャンネル [%s](https://t.me/%s) のすべての購読の更新を一時停止しました",
+	"pauseall.failed":          "一時停止に失敗しました",
+	"pauseall.pause_skipped":   "<b>%d 件のフィードは、他のチャットも購読しているため停止しませんでした</b>\n",
+	"pauseall.success":         "すべての購読の更新を一時停止しました",
+	"pauseall.tag_success":     "タグ %[2]s の購読 %[1]d 件を一時停止しました",
+
+	"ping.pong": "Ping を受信しました。`pong` を返します",
+
+	"preview.failed":    "フィードを取得できませんでした、%s",
+	"preview.subscribe": "購読する",
+	"preview.summary":   "<b>フィードのプレビュー</b>\n%s\nタイトル：%s\n形式：%s\n記事数：%d\n最終公開：%s",
+	"preview.unknown":   "不明",
+	"preview.usage":     "コマンドの後にプレビューするフィードの URL を付けてください。例：%s https://github.blog/feed/",
+
+	"renametag.exists":  "タグ %s は既に存在します。/mergetag で統合してください",
+	"renametag.failed":  "タグの名前を変更できませんでした",
+	"renametag.success": "タグ %s を %s に変更しました（%d 件の購読）",
+	"renametag.usage":   "使い方：/renametag #旧タグ #新タグ",
+
+	"restore.done":          "<b>復元が完了しました：追加 %d、更新 %d、削除 %d、失敗 %d</b>\n",
+	"restore.dry_run":       "%[2]s に作成された購読 %[1]d 件のバックアップ\nマージすると %[3]d 件を追加し %[4]d 件を更新します\n置換するとバックアップにない購読 %[5]d 件も削除します",
+	"restore.failed":        "バックアップを復元できませんでした",
+	"restore.hint":          "バックアップファイルのようです。/restore で返信すると復元できます",
+	"restore.invalid_file":  "無効なバックアップファイルです、%s",
+	"restore.merge":         "マージ",
+	"restore.pause_skipped": "<b>一時停止中の %d 件のフィードは、他のチャットも購読しているため停止しませんでした</b>\n",
+	"restore.replace":       "置換",
+	"restore.restoring":     "バックアップを復元しています…",
+	"restore.usage":         "/backup のファイルに /restore で返信すると変更内容を確認できます。/restore merge または /restore replace で復元します",
+
+	"saved.empty":        "あとで読むリストは空です",
+	"saved.export_usage": "`/saved export md` または `/saved export html` コマンドを使用してください",
+	"saved.fetch_failed": "あとで読むリストを取得できませんでした",
+	"saved.footer":       "/unsave [id] で削除できます",
+	"saved.title":        "保存済み %d 件、%d ページ目\n",
+
+	"search.expired":        "この検索リクエストは無効になりました",
+	"search.failed":         "検索に失敗しました",
+	"search.no_result":      "一致する記事は見つかりませんでした",
+	"search.not_subscribed": "この購読元はあなたの購読一覧にありません",
+	"search.title":          "検索結果、%d ページ目\n",
+	"search.usage":          "`/search [source:id] [since:2006-01-02|7d] [until:2006-01-02] キーワード` コマンドで検索してください",
+
+	"set.fetch_failed":     "購読情報を取得できませんでした",
+	"set.select":           "設定する購読を選択してください",
+	"set.source_not_found": "購読元が見つかりません",
+
+	"setfeedtag.button_hint": "`/setfeedtag %d tags` コマンドでこの購読にタグを設定してください。`tags` は設定するタグで、購読ごとに最大 3 個まで、スペースで区切ります\n例：`/setfeedtag %d anime moe`",
+	"setfeedtag.failed":      "購読タグの設定に失敗しました！",
+	"setfeedtag.success":     "購読タグを設定しました！",
+	"setfeedtag.usage":       "/setfeedtag `[source_id] [tag1] [tag2]` で購読にタグを設定します。購読ごとに最大 3 個まで、スペースで区切ります",
+
+	"setinterval.failed":  "更新間隔の設定に失敗しました！",
+	"setinterval.invalid": "正しい更新間隔を入力してください",
+	"setinterval.success": "更新間隔を設定しました！",
+	"setinterval.usage":   "/setinterval [interval] [source_id] 購読の更新間隔を分単位で設定します（複数の source_id をスペースで区切って指定できます）",
+
+	"settopic.create_failed": "フォーラムのトピックを作成できませんでした。Bot にトピックの管理権限があるか確認してください",
+	"settopic.created":       "購読用に %d 個のフォーラムトピックを作成しました",
+	"settopic.failed":        "フォーラムトピックの設定に失敗しました！",
+	"settopic.general":       "購読は General トピックに配信されます",
+	"settopic.not_forum":     "フォーラムのトピックはトピックを有効にしたスーパーグループでのみ利用できます",
+	"settopic.success":       "購読はこのトピックに配信されます",
+	"settopic.usage":         "/settopic [source_id] トピック内で実行すると購読をそのトピックに配信し、General トピック内で実行するとデフォルトの配信に戻します（複数の source_id をスペースで区切って指定できます）\n/settopic auto トピックに紐付いていないすべての購読に、フィード名のトピックを作成します",
+
+	"source.update_paused": "[%s](%s) の更新が %d 回連続で失敗したため、更新を一時停止しました",
+
+	"start.invite_invalid": "招待リンクが無効か、既に使用されています",
+	"start.invite_success": "招待を受け付けました。Bot を使えるようになりました",
+	"start.welcome":        "こんにちは！Toshiki の RSS Bot へようこそ。/help でヘルプを表示します",
+
+	"stats.cycles":     "<b>最後の取得サイクル</b> %s：フィード %d 件、失敗 %d 件、所要 %dms\n直近 %d サイクルの平均：%dms",
+	"stats.failed":     "統計を取得できませんでした",
+	"stats.no_sources": "フィードはまだありません",
+	"stats.source":     "    1 日 %.1f 件、最新の記事 %s、取得 %d 回、失敗 %d 回、平均 %dms",
+	"stats.stale":      "<b>%d 日間新しい記事がありません</b>\n",
+	"stats.title":      "<b>直近 %d 日間の取得統計</b>\n",
+
+	"sub.channel_no_privilege": "あなたまたは Bot がこのチャンネルの管理者ではないため、購読を設定できません",
+	"sub.channel_usage":        "チャンネル用に購読するには ' /sub `@channel_id` URL ' コマンドを使用してください。例：@toshikidev",
+	"sub.exist":                "この購読元はすでに購読済みです。重複して購読しないでください",
+	"sub.failed":               "購読に失敗しました",
+	"sub.source_failed":        "%s、購読に失敗しました",
+	"sub.success":              "[[%d]] [%s](%s) を購読しました",
+	"sub.usage":                "コマンドの後に RSS フィードの URL を付けてください。例：%s https://github.blog/feed/",
+
+	"subscriptions.fetch_failed": "購読一覧を取得できませんでした",
+	"subscriptions.none":         "現在、購読はありません",
+
+	"tag.no_subscriptions": "タグ %s の購読はありません",
+	"tag.not_found":        "タグ %s は存在しません",
+
+	"tags.empty":        "タグはまだありません。/setfeedtag で購読にタグを付けてください",
+	"tags.fetch_failed": "タグを取得できませんでした",
+	"tags.footer":       "/list #tag でタグを表示、/renametag #旧 #新、/mergetag #元 #先、/deltag #tag でタグを管理",
+	"tags.item":         "#%s：%d 件の購読",
+	"tags.title":        "<b>タグ</b>\n",
+
+	"unsave.failed":    "保存した記事の削除に失敗しました",
+	"unsave.not_exist": "保存した記事が存在しません",
+	"unsave.success":   "保存した記事を削除しました",
+	"unsave.usage":     "`/unsave [id]` コマンドで保存した記事を削除してください",
+
+	"unsub.channel_success": "チャンネル [%[3]s](https://t.me/%[4]s) の [%[1]s](%[2]s) の購読を解除しました",
+	"unsub.channel_usage":   "チャンネル用に購読を解除するには `/unsub @channel_id URL` または `/unsub @channel_id #tag` コマンドを使用してください",
+	"unsub.chat_success":    "[%s](%s) の購読を解除しました！",
+	"unsub.failed":          "購読解除に失敗しました",
+	"unsub.item_success":    "[%d] <a href=\"%s\">%s</a> の購読を解除しました",
+	"unsub.not_subscribed":  "この RSS フィードは購読していません",
+	"unsub.select":          "購読を解除するフィードを選択してください",
+	"unsub.success":         "購読を解除しました",
+	"unsub.tag_success":     "タグ %[2]s の購読 %[1]d 件の購読を解除しました",
+	"unsub.undo":            "元に戻す",
+	"unsub.undo_expired":    "取り消し期間が過ぎたか、既に再購読されています。必要であれば再度購読してください",
+	"unsub.undone":          "%d 件の購読を復元しました",
+
+	"unsuball.confirm": "現在のユーザーのすべての購読を解除しますか？",
+}
@@ -0,0 +1,326 @@
+package i18n
+
+// zh Simplified Chinese messages, command.* keys localize the command descriptions
+var zh = map[string]string{
+	"action.content_gone": "该内容已不可用",
+	"action.mute":         "静音",
+	"action.pause":        "暂停",
+	"action.resume":       "恢复",
+	"action.save":         "稍后阅读",
+	"action.save_failed":  "保存失败",
+	"action.save_success": "已保存，使用 /saved 稍后阅读",
+	"action.saved_exist":  "已经保存过了",
+	"action.unmute":       "取消静音",
+	"action.unsubscribe":  "退订",
+
+	"activeall.channel_success": "频道 [%s](https://t.me/%s) 已开启全部订阅更新",
+	"activeall.failed":          "启用失败",
+	"activeall.success":         "已开启全部订阅更新",
+	"activeall.tag_success":     "已开启 %d 个标签为 %s 的订阅",
+
+	"admin.allowed":             "已允许用户 %d 使用 Bot",
+	"admin.allowlist_disabled":  "配置中未启用白名单，所有人都可以使用 Bot",
+	"admin.broadcast_done":      "广播完成，成功 %d 个，失败 %d 个",
+	"admin.broadcast_started":   "正在向 %d 个会话广播",
+	"admin.chat":                "%d：%d 个订阅",
+	"admin.chat_migrate_failed": "%s（%d）的订阅迁移到超级群组 %d 失败，请检查日志",
+	"admin.chat_migrated":       "%s（%d）已升级为超级群组 %d，已迁移 %d 个订阅",
+	"admin.chat_removed":        "Bot 已被移出 %s（%d），已删除 %d 个订阅",
+	"admin.chats_title":         "<b>%d 个有订阅的会话</b>\n",
+	"admin.denied":              "已禁止用户 %d 使用 Bot",
+	"admin.failed":              "管理命令执行失败",
+	"admin.invite":              "一次性邀请链接，通过它启动 Bot 的用户即可使用 Bot：\n%s",
+	"admin.no_chats":            "没有会话有订阅",
+	"admin.no_users":            "白名单为空",
+	"admin.paused":              "已为所有订阅者暂停订阅源 [%d] %s",
+	"admin.resumed":             "已为所有订阅者恢复订阅源 [%d] %s",
+	"admin.source_subscribers":  "    %d 个订阅者，%d 次错误",
+	"admin.sources_title":       "<b>%d 个订阅源</b>\n",
+	"admin.usage":               "/admin sources 列出所有订阅源\n/admin chats 列出有订阅的会话\n/admin pause [source_id] 为所有人暂停订阅源，/admin resume [source_id] 恢复\n/admin broadcast [text] 向所有会话发送通知\n/admin stats 查看所有订阅源的抓取统计\n/admin users 查看白名单，/admin allow [user_id] 和 /admin deny [user_id] 修改白名单\n/admin invite 生成一次性邀请链接",
+	"admin.users_title":         "<b>%d 个白名单用户</b>\n",
+
+	"backup.caption": "%d 个订阅的备份，回复此文件 /restore 即可恢复",
+	"backup.failed":  "备份订阅失败",
+
+	"buttons.disabled": "推送的消息将不再附带操作按钮",
+	"buttons.enabled":  "推送的消息将附带静音、暂停和退订按钮",
+	"buttons.failed":   "设置操作按钮失败",
+
+	"channel.admins_failed": "获取频道管理员信息失败",
+	"channel.fetch_failed":  "获取频道信息失败",
+	"channel.not_admin":     "非管理员用户无权执行此操作",
+
+	"command.activeall":   "开启所有订阅",
+	"command.admin":       "Bot 管理，/admin sources|chats|pause|resume|broadcast|stats|users|allow|deny|invite",
+	"command.backup":      "备份订阅与设置为 JSON 文件",
+	"command.buttons":     "开关推送消息上的操作按钮",
+	"command.deltag":      "从所有订阅移除标签，/deltag #标签",
+	"command.export":      "导出订阅为 OPML",
+	"command.help":        "帮助",
+	"command.import":      "导入 OPML 文件",
+	"command.lang":        "选择当前会话的回复语言",
+	"command.list":        "查看当前订阅",
+	"command.mergetag":    "合并标签，/mergetag #源 #目标",
+	"command.pauseall":    "暂停所有订阅",
+	"command.ping":        "检查 Bot 是否在线",
+	"command.preview":     "订阅前预览 RSS 源",
+	"command.renametag":   "重命名标签，/renametag #旧 #新",
+	"command.restore":     "恢复备份文件，回复文件 /restore [merge|replace]",
+	"command.saved":       "稍后阅读列表，/saved export [md|html] 导出",
+	"command.search":      "搜索订阅收到的内容",
+	"command.set":         "设置订阅",
+	"command.setfeedtag":  "设置订阅标签",
+	"command.setinterval": "设置订阅刷新频率",
+	"command.settopic":    "将订阅推送到论坛话题",
+	"command.start":       "开始使用",
+	"command.stats":       "查看订阅源的抓取统计",
+	"command.sub":         "订阅 RSS 源",
+	"command.tags":        "查看标签及订阅数",
+	"command.unsave":      "从稍后阅读列表中移除",
+	"command.unsub":       "退订 RSS 源",
+	"command.unsuball":    "取消所有订阅",
+	"command.version":     "Bot 版本信息",
+
+	"common.cancel":            "取消",
+	"common.cancelled":         "已取消当前操作",
+	"common.confirm":           "确认",
+	"common.error":             "错误",
+	"common.internal_error":    "内部服务错误",
+	"common.menu_expired":      "此菜单已过期，请重新发送命令",
+	"common.modified":          "修改成功",
+	"common.next":              "下一页",
+	"common.not_chat_admin":    "您不是当前会话的管理员",
+	"common.permission_denied": "没有操作权限",
+	"common.previous":          "上一页",
+
+	"deltag.failed":  "删除标签失败",
+	"deltag.success": "已从 %[2]d 个订阅移除标签 %[1]s",
+	"deltag.usage":   "用法：/deltag #标签",
+
+	"export.failed": "导出失败",
+
+	"feedlink.found":             "发现 RSS 源：\n%s\n<b>%s</b>",
+	"feedlink.preview":           "预览",
+	"feedlink.subscribe_channel": "订阅到频道 %s",
+	"feedlink.subscribe_here":    "在此订阅",
+
+	"feedset.btn_back":          "返回",
+	"feedset.btn_back_list":     "返回订阅列表",
+	"feedset.btn_default":       "默认",
+	"feedset.btn_edit_off":      "关闭更新时编辑",
+	"feedset.btn_edit_on":       "开启更新时编辑",
+	"feedset.btn_hide":          "隐藏",
+	"feedset.btn_interval":      "更新频率",
+	"feedset.btn_notice_off":    "关闭通知",
+	"feedset.btn_notice_on":     "开启通知",
+	"feedset.btn_pause":         "暂停更新",
+	"feedset.btn_preview":       "预览",
+	"feedset.btn_restart":       "重启更新",
+	"feedset.btn_show":          "显示",
+	"feedset.btn_tags":          "设置标签",
+	"feedset.btn_telegraph_off": "关闭 Telegraph 转码",
+	"feedset.btn_telegraph_on":  "开启 Telegraph 转码",
+	"feedset.btn_web_default":   "默认网页预览",
+	"feedset.characters":        "%d 字",
+	"feedset.default":           "默认（%v）",
+	"feedset.disable":           "关闭",
+	"feedset.enable":            "开启",
+	"feedset.hidden":            "隐藏",
+	"feedset.never":             "从未",
+	"feedset.template": `
+订阅<b>设置</b>
+[id] {{ .source.ID }}
+[标题] {{ .source.Title }}
+[链接] {{.source.Link }}
+[抓取状态] {{if ge .source.ErrorCount .Count }}暂停{{else if lt .source.ErrorCount .Count }}抓取中{{end}}
+[抓取频率] {{ .sub.Interval }}分钟
+[预览长度] {{ .previewLength }}
+[网页预览] {{ .webPagePreview }}
+[通知] {{if eq .sub.EnableNotification 0}}关闭{{else if eq .sub.EnableNotification 1}}开启{{end}}
+[Telegraph] {{if eq .sub.EnableTelegraph 0}}关闭{{else if eq .sub.EnableTelegraph 1}}开启{{end}}
+[更新时编辑] {{if eq .sub.EnableEditUpdate 0}}关闭{{else if eq .sub.EnableEditUpdate 1}}开启{{end}}
+[标签] {{if .sub.Tag}}{{ .sub.Tag }}{{else}}无{{end}}
+[订阅人数] {{ .subscriberCount }}
+[上次抓取] {{ .lastFetch }}
+[上次错误] {{if .source.LastError}}{{ .lastError }}{{else}}无{{end}}
+`,
+
+	"help.text": `	/sub 订阅 RSS 源
+	/preview 订阅前预览 RSS 源的推送效果
+	在私聊中直接发送链接，或在群组中提及 Bot 并附上链接，无需命令即可订阅
+	/unsub  退订 RSS 源，/unsub #tag 退订整个标签
+	/list 查看当前订阅，/list #标签 按标签筛选
+	/set 设置订阅
+	/check 检查当前订阅状态
+	/tags 查看标签，/renametag、/mergetag、/deltag 管理标签
+	/setfeedtag 设置订阅标签
+	/setinterval 设置订阅刷新频率
+	/settopic 将订阅推送到论坛话题
+	/stats 查看订阅源的抓取统计
+	/buttons 开关推送消息上的静音、暂停和退订按钮
+	/lang 选择当前会话的回复语言
+	/search 搜索收到的内容，可用 source:id since:7d until:2006-01-02 筛选
+	@bot 关键词 在任意会话中搜索并分享订阅内容（内联模式）
+	/saved 查看并导出稍后阅读列表
+	/unsave 从稍后阅读列表中移除
+	/activeall 开启所有订阅，/activeall #tag 开启整个标签
+	/pauseall 暂停所有订阅，/pauseall #tag 暂停整个标签
+	/help 帮助
+	/import 导入 OPML 文件
+	/export 导出 OPML 文件，/export #tag 导出整个标签
+	/backup 备份订阅与设置，回复备份文件 /restore 即可恢复
+	/unsuball 取消所有订阅
+	详细使用方法请访问 https://note.toshiki.dev/
+	Made with love and coffee by @andatoshiki at @toshikidev in Arizona State University & proudly open sourced on GitLab
+`,
+
+	"import.failed":       "<b>以下订阅源导入失败：</b>\n",
+	"import.failed_more":  "……以及其他 %d 个\n",
+	"import.file_failed":  "获取文件失败",
+	"import.hint":         "请直接向 Bot 发送 OPML 文件、Miniflux JSON 导出文件或每行一个链接的 .txt 链接列表；如需为频道导入，请在发送文件时附上频道 ID，例如：@toshikidev",
+	"import.invalid_file": "请发送 OPML 文件、Miniflux JSON 导出文件或 .txt 链接列表",
+	"import.progress":     "正在导入，已完成 %d/%d，失败 %d",
+	"import.retry_all":    "全部重试",
+	"import.retry_done":   "失败的订阅源已全部导入",
+	"import.retrying":     "正在重试 %d 个订阅源",
+	"import.succeeded":    "<b>以下订阅源导入成功：</b>\n",
+	"import.summary":      "<b>导入成功：%d，导入失败：%d</b>\n",
+
+	"lang.auto":         "自动",
+	"lang.auto_success": "回复语言将跟随您的 Telegram 语言，当前为%s",
+	"lang.failed":       "设置回复语言失败",
+	"lang.name":         "中文",
+	"lang.select":       "请选择当前会话的回复语言",
+	"lang.success":      "当前会话的回复语言已设置为%s",
+	"lang.usage":        "请使用 `/lang [%s|auto]` 命令选择回复语言",
+
+	"list.empty":     "当前订阅列表为空",
+	"list.muted":     "已静音",
+	"list.no_more":   "没有更多订阅了",
+	"list.paused":    "已暂停",
+	"list.tag_title": "%s 的订阅列表，第 %d 页\n",
+	"list.title":     "订阅列表，第 %d 页\n",
+
+	"mergetag.failed":    "合并标签失败",
+	"mergetag.not_found": "%s 和 %s 都必须是已存在的标签",
+	"mergetag.success":   "已将 %[2]s 的 %[1]d 个订阅合并到 %[3]s",
+	"mergetag.usage":     "用法：/mergetag #源标签 #目标标签",
+
+	"pauseall.channel_success": "频道 [%s](https://t.me/%s) 已暂停全部订阅更新",
+	"pauseall.failed":          "暂停失败",
+	"pauseall.pause_skipped":   "<b>%d 个订阅源未被暂停，其他聊天也订阅了它们</b>\n",
+	"pauseall.success":         "已暂停全部订阅更新",
+	"pauseall.tag_success":     "已暂停 %d 个标签为 %s 的订阅",
+
+	"ping.pong": "已收到 Ping 请求，回复 `pong`",
+
+	"preview.failed":    "获取 RSS 源失败，%s",
+	"preview.subscribe": "订阅",
+	"preview.summary":   "<b>RSS 源预览</b>\n%s\n标题：%s\n类型：%s\n条目数：%d\n最近发布：%s",
+	"preview.unknown":   "未知",
+	"preview.usage":     "请在命令后附上要预览的 RSS 源链接，例如：%s https://github.blog/feed/",
+
+	"renametag.exists":  "标签 %s 已存在，请使用 /mergetag 合并",
+	"renametag.failed":  "重命名标签失败",
+	"renametag.success": "已将标签 %s 重命名为 %s，涉及 %d 个订阅",
+	"renametag.usage":   "用法：/renametag #旧标签 #新标签",
+
+	"restore.done":          "<b>恢复完成：新增 %d，更新 %d，删除 %d，失败 %d</b>\n",
+	"restore.dry_run":       "%[2]s 创建的备份，共 %[1]d 个订阅\n合并将新增 %[3]d 个、更新 %[4]d 个订阅\n替换还会删除备份中没有的 %[5]d 个订阅",
+	"restore.failed":        "恢复备份失败",
+	"restore.hint":          "这似乎是一个备份文件，回复它 /restore 即可恢复",
+	"restore.invalid_file":  "无效的备份文件，%s",
+	"restore.merge":         "合并",
+	"restore.pause_skipped": "<b>%d 个已暂停的订阅源未被暂停，其他聊天也订阅了它们</b>\n",
+	"restore.replace":       "替换",
+	"restore.restoring":     "正在恢复备份……",
+	"restore.usage":         "回复 /backup 生成的文件 /restore 查看将发生的变更，或回复 /restore merge、/restore replace 直接恢复",
+
+	"saved.empty":        "稍后阅读列表为空",
+	"saved.export_usage": "请使用 `/saved export md` 或 `/saved export html` 命令",
+	"saved.fetch_failed": "获取稍后阅读列表失败",
+	"saved.footer":       "使用 /unsave [id] 移除条目",
+	"saved.title":        "共 %d 条保存的内容，第 %d 页\n",
+
+	"search.expired":        "该搜索请求已失效",
+	"search.failed":         "搜索失败",
+	"search.no_result":      "没有找到匹配的内容",
+	"search.not_subscribed": "您的订阅列表中不存在该订阅源",
+	"search.title":          "搜索结果，第 %d 页\n",
+	"search.usage":          "请使用 `/search [source:id] [since:2006-01-02|7d] [until:2006-01-02] 关键词` 命令进行搜索",
+
+	"set.fetch_failed":     "获取订阅信息失败",
+	"set.select":           "请选择要设置的订阅",
+	"set.source_not_found": "找不到该订阅源",
+
+	"setfeedtag.button_hint": "请使用 `/setfeedtag %d tags` 命令为该订阅设置标签，`tags` 为需要设置的标签，每个订阅最多 3 个标签，以空格分隔\n例如：`/setfeedtag %d 动画 萌`",
+	"setfeedtag.failed":      "订阅标签设置失败！",
+	"setfeedtag.success":     "订阅标签设置成功！",
+	"setfeedtag.usage":       "/setfeedtag `[source_id] [tag1] [tag2]` 设置订阅标签，每个订阅最多 3 个标签，以空格分隔",
+
+	"setinterval.failed":  "抓取频率设置失败！",
+	"setinterval.invalid": "请输入正确的抓取频率",
+	"setinterval.success": "抓取频率设置成功！",
+	"setinterval.usage":   "/setinterval [interval] [source_id] 设置订阅刷新频率，单位为分钟（可设置多个 source_id，以空格分隔）",
+
+	"settopic.create_failed": "创建论坛话题失败，请确认 Bot 有管理话题的权限",
+	"settopic.created":       "已为订阅创建 %d 个论坛话题",
+	"settopic.failed":        "论坛话题设置失败！",
+	"settopic.general":       "订阅将推送到 General 话题",
+	"settopic.not_forum":     "论坛话题仅在开启话题的超级群组中可用",
+	"settopic.success":       "订阅将推送到此话题",
+	"settopic.usage":         "/settopic [source_id] 在话题内执行，将订阅推送到该话题；在 General 话题内执行则恢复默认推送（可设置多个 source_id，以空格分隔）\n/settopic auto 为所有尚未绑定话题的订阅创建以订阅源命名的话题",
+
+	"source.update_paused": "[%s](%s) 已连续 %d 次更新失败，已暂停更新",
+
+	"start.invite_invalid": "邀请链接无效或已被使用",
+	"start.invite_success": "已接受邀请，现在可以使用 Bot 了",
+	"start.welcome":        "你好，欢迎使用 Toshiki 的 RSS Bot，发送 /help 查看帮助信息",
+
+	"stats.cycles":     "<b>最近一轮抓取</b>于 %s：%d 个订阅源，%d 个失败，耗时 %dms\n最近 %d 轮平均耗时：%dms",
+	"stats.failed":     "获取统计失败",
+	"stats.no_sources": "还没有订阅源",
+	"stats.source":     "    每天 %.1f 条，最新条目 %s，抓取 %d 次，失败 %d 次，平均 %dms",
+	"stats.stale":      "<b>%d 天内没有新条目</b>\n",
+	"stats.title":      "<b>最近 %d 天的抓取统计</b>\n",
+
+	"sub.channel_no_privilege": "您或 Bot 不是该频道的管理员，无法设置订阅",
+	"sub.channel_usage":        "为频道订阅请使用 ' /sub `@channel_id` URL ' 命令，例如：@toshikidev",
+	"sub.exist":                "已订阅该源，请勿重复订阅",
+	"sub.failed":               "订阅失败",
+	"sub.source_failed":        "%s，订阅失败",
+	"sub.success":              "[[%d]] [%s](%s) 订阅成功",
+	"sub.usage":                "请在命令后附上 RSS 订阅链接，例如：%s https://github.blog/feed/",
+
+	"subscriptions.fetch_failed": "获取订阅列表失败",
+	"subscriptions.none":         "当前没有订阅",
+
+	"tag.no_subscriptions": "没有标签为 %s 的订阅",
+	"tag.not_found":        "标签 %s 不存在",
+
+	"tags.empty":        "还没有标签，使用 /setfeedtag 为订阅设置标签",
+	"tags.fetch_failed": "获取标签失败",
+	"tags.footer":       "/list #tag 查看标签，/renametag #旧 #新、/mergetag #源 #目标 和 /deltag #tag 管理标签",
+	"tags.item":         "#%s：%d 个订阅",
+	"tags.title":        "<b>标签</b>\n",
+
+	"unsave.failed":    "移除保存的内容失败",
+	"unsave.not_exist": "保存的内容不存在",
+	"unsave.success":   "已移除保存的内容",
+	"unsave.usage":     "请使用 `/unsave [id]` 命令移除保存的内容",
+
+	"unsub.channel_success": "频道 [%[3]s](https://t.me/%[4]s) 已退订 [%[1]s](%[2]s)",
+	"unsub.channel_usage":   "如需为频道退订，请使用 `/unsub @channel_id URL` 或 `/unsub @channel_id #tag` 命令",
+	"unsub.chat_success":    "[%s](%s) 退订成功！",
+	"unsub.failed":          "退订失败",
+	"unsub.item_success":    "[%d] <a href=\"%s\">%s</a> 退订成功",
+	"unsub.not_subscribed":  "未订阅该 RSS 源",
+	"unsub.select":          "请选择要退订的订阅源",
+	"unsub.success":         "退订成功",
+	"unsub.tag_success":     "已退订 %d 个标签为 %s 的订阅",
+	"unsub.undo":            "撤销",
+	"unsub.undo_expired":    "撤销期限已过或已重新订阅，如有需要请重新订阅",
+	"unsub.undone":          "已恢复 %d 个订阅",
+
+	"unsuball.confirm": "是否退订当前用户的所有订阅？",
+}
@@ -2,6 +2,7 @@ package message
 
 import (
 	"regexp"
+	"strings"
 
 	tb "gopkg.in/telebot.v3"
 )
@@ -42,3 +43,16 @@ func URLFromMessage(m *tb.Message) string {
 	}
 	return ""
 }
+
+// MentionsUsername checks if a message mentions a username, like the bot itself
+func MentionsUsername(m *tb.Message, username string) bool {
+	for _, entity := range m.Entities {
+		if entity.Type != tb.EntityMention {
+			continue
+		}
+		if strings.EqualFold(m.Text[entity.Offset:entity.Offset+entity.Length], "@"+username) {
+			return true
+		}
+	}
+	return false
+}
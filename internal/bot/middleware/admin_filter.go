@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"fmt"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+)
+
+// AdminFilter only lets bot admins through
+func AdminFilter() tb.MiddlewareFunc {
+	return func(next tb.HandlerFunc) tb.HandlerFunc {
+		return func(c tb.Context) error {
+			userID := c.Sender().ID
+			if !config.IsAdmin(userID) {
+				return fmt.Errorf("deny non admin user %d", userID)
+			}
+			return next(c)
+		}
+	}
+}
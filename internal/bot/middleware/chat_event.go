@@ -0,0 +1,10 @@
+package middleware
+
+import tb "gopkg.in/telebot.v3"
+
+// isChatEvent reports updates about the chat itself, like the bot being removed or a group migration,
+// they are handled whoever triggered them
+func isChatEvent(c tb.Context) bool {
+	u := c.Update()
+	return u.MyChatMember != nil || (u.Message != nil && u.Message.MigrateTo != 0)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/chat"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
 	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
 
 	tb "gopkg.in/telebot.v3"
@@ -10,18 +11,23 @@ import (
 func IsChatAdmin() tb.MiddlewareFunc {
 	return func(next tb.HandlerFunc) tb.HandlerFunc {
 		return func(c tb.Context) error {
+			// inline queries are not sent from a chat, they only read the sender's own subscriptions
+			if c.Query() != nil || isChatEvent(c) {
+				return next(c)
+			}
+
 			if !chat.IsChatAdmin(c.Bot(), c.Chat(), c.Sender().ID) {
-				return c.Reply("You are not the administrator of the current session")
+				return c.Reply(i18n.T(session.GetLanguageFromCtxStore(c), "common.not_chat_admin"))
 			}
 
 			v := c.Get(session.StoreKeyMentionChat.String())
 			if v != nil {
 				mentionChat, ok := v.(*tb.Chat)
 				if !ok {
-					return c.Reply("Internal service error")
+					return c.Reply(i18n.T(session.GetLanguageFromCtxStore(c), "common.internal_error"))
 				}
 				if !chat.IsChatAdmin(c.Bot(), mentionChat, c.Sender().ID) {
-					return c.Reply("You are not the administrator of the current session")
+					return c.Reply(i18n.T(session.GetLanguageFromCtxStore(c), "common.not_chat_admin"))
 				}
 			}
 			return next(c)
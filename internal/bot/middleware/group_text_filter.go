@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strings"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/message"
+)
+
+// GroupTextFilter drops the chatter of groups, plain text messages only reach the bot if they mention it
+func GroupTextFilter() tb.MiddlewareFunc {
+	return func(next tb.HandlerFunc) tb.HandlerFunc {
+		return func(c tb.Context) error {
+			m := c.Message()
+			if m == nil || m.Text == "" || strings.HasPrefix(m.Text, "/") || c.Callback() != nil {
+				return next(c)
+			}
+			if m.Chat.Type != tb.ChatGroup && m.Chat.Type != tb.ChatSuperGroup {
+				return next(c)
+			}
+			if !message.MentionsUsername(m, c.Bot().Me.Username) {
+				return nil
+			}
+			return next(c)
+		}
+	}
+}
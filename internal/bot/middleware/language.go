@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+// Language resolves the reply language, the chat preference wins over the language of the sender
+func Language(appCore *core.Core) tb.MiddlewareFunc {
+	return func(next tb.HandlerFunc) tb.HandlerFunc {
+		return func(c tb.Context) error {
+			var lang string
+			var chatID int64
+			if c.Chat() != nil {
+				chatID = c.Chat().ID
+			} else if c.Sender() != nil {
+				chatID = c.Sender().ID
+			}
+			if chatID != 0 {
+				preference, err := appCore.GetChatPreference(context.Background(), chatID)
+				if err != nil {
+					log.Errorf("get chat %d preference failed, %v", chatID, err)
+				} else {
+					lang = preference.Language
+				}
+			}
+
+			if lang == "" && c.Sender() != nil {
+				lang = i18n.Normalize(c.Sender().LanguageCode)
+			}
+			if lang == "" {
+				lang = i18n.Default
+			}
+			c.Set(session.StoreKeyLanguage.String(), lang)
+			return next(c)
+		}
+	}
+}
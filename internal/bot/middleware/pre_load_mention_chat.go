@@ -11,6 +11,10 @@ import (
 func PreLoadMentionChat() tb.MiddlewareFunc {
 	return func(next tb.HandlerFunc) tb.HandlerFunc {
 		return func(c tb.Context) error {
+			if c.Message() == nil {
+				return next(c)
+			}
+
 			mention := message.MentionFromMessage(c.Message())
 			if mention != "" {
 				chat, err := c.Bot().ChatByUsername(mention)
@@ -1,26 +1,37 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	tb "gopkg.in/telebot.v3"
 
-	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
 )
 
-func UserFilter() tb.MiddlewareFunc {
+func UserFilter(appCore *core.Core) tb.MiddlewareFunc {
 	return func(next tb.HandlerFunc) tb.HandlerFunc {
 		return func(c tb.Context) error {
-			if len(config.AllowUsers) == 0 {
+			if isChatEvent(c) {
 				return next(c)
 			}
+
 			userID := c.Sender().ID
-			for _, allowUserID := range config.AllowUsers {
-				if allowUserID == userID {
-					return next(c)
-				}
+			// /start with an invite code lets users not on the allowlist yet redeem it
+			if m := c.Message(); m != nil && strings.HasPrefix(m.Text, "/start") && m.Payload != "" {
+				return next(c)
+			}
+
+			allowed, err := appCore.IsUserAllowed(context.Background(), userID)
+			if err != nil {
+				log.Errorf("check user %d allowed failed, %v", userID, err)
+			}
+			if !allowed {
+				return fmt.Errorf("deny user %d", userID)
 			}
-			return fmt.Errorf("deny user %d", userID)
+			return next(c)
 		}
 	}
 }
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/i18n"
+	"github.com/andatoshiki/toshiki-rssbot/internal/bot/session"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+// VerifyCallback rejects the button callbacks whose data is not signed by the bot,
+// or was issued longer than ttl ago, 0 ttl never expires
+func VerifyCallback(ttl time.Duration) tb.MiddlewareFunc {
+	return func(next tb.HandlerFunc) tb.HandlerFunc {
+		return func(c tb.Context) error {
+			callback := c.Callback()
+			if callback == nil {
+				return next(c)
+			}
+
+			if _, err := session.VerifyAttachment(callback.Data, ttl); err != nil {
+				if !errors.Is(err, session.ErrAttachmentExpired) {
+					log.Warnf("user %d sent callback %s with invalid data %q", c.Sender().ID, callback.Unique, callback.Data)
+				}
+				return c.Respond(
+					&tb.CallbackResponse{
+						Text:      i18n.T(session.GetLanguageFromCtxStore(c), "common.menu_expired"),
+						ShowAlert: true,
+					},
+				)
+			}
+			return next(c)
+		}
+	}
+}
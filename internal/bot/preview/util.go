@@ -6,8 +6,39 @@ import (
 	"strings"
 
 	strip "github.com/grokify/html-strip-tags-go"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+const (
+	WebPagePreviewDefault = iota
+	WebPagePreviewShow
+	WebPagePreviewHide
 )
 
+// Length gets the preview text length of a subscription
+func Length(sub *model.Subscribe) int {
+	if sub.PreviewLength < 0 {
+		return 0
+	}
+	if sub.PreviewLength == 0 {
+		return config.PreviewText
+	}
+	return sub.PreviewLength
+}
+
+// DisableWebPage checks whether the web page preview of a subscription is disabled
+func DisableWebPage(sub *model.Subscribe) bool {
+	switch sub.WebPagePreview {
+	case WebPagePreviewShow:
+		return false
+	case WebPagePreviewHide:
+		return true
+	}
+	return config.DisableWebPagePreview
+}
+
 func TrimDescription(desc string, limit int) string {
 	if limit == 0 {
 		return ""
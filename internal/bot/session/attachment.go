@@ -1,26 +1,74 @@
 package session
 
 import (
-	"encoding/hex"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/log"
 )
 
-// Marshal encodes to a string
+// signatureLength bytes of the HMAC-SHA256 kept in the attachment, the callback data is limited to 64 bytes
+const signatureLength = 8
+
+var (
+	ErrAttachmentExpired   = errors.New("attachment expired")
+	ErrAttachmentSignature = errors.New("invalid attachment signature")
+
+	secret = sign(nil, []byte("attachment"))
+	now    = time.Now
+)
+
+// SetSecret sets the key the attachments are signed with, buttons signed with another key are rejected
+func SetSecret(key string) {
+	secret = sign([]byte(key), []byte("attachment"))
+}
+
+func sign(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// signature signs the attachment without its signature, the fields are encoded in a deterministic order
+func (x *Attachment) signature() ([]byte, error) {
+	unsigned := proto.Clone(x).(*Attachment)
+	unsigned.Signature = nil
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	return sign(secret, data)[:signatureLength], nil
+}
+
+// Marshal signs the attachment with the issue time and encodes it as callback data
 func Marshal(a *Attachment) string {
-	bytes, err := proto.Marshal(a)
+	signed := proto.Clone(a).(*Attachment)
+	signed.IssuedAt = uint32(now().Unix() / 60)
+	signature, err := signed.signature()
+	if err != nil {
+		log.Errorf("sign attachment failed, %v", err)
+		return ""
+	}
+	signed.Signature = signature
+
+	bytes, err := proto.Marshal(signed)
 	if err != nil {
 		log.Errorf("marshal attachment failed, %v", err)
 		return ""
 	}
-	return hex.EncodeToString(bytes)
+	return base64.RawURLEncoding.EncodeToString(bytes)
 }
 
-// Unmarshal Attachment parses the transmitted information from a string
+// UnmarshalAttachment decodes the callback data, the signature is checked by VerifyAttachment
 func UnmarshalAttachment(data string) (*Attachment, error) {
-	bytes, err := hex.DecodeString(data)
+	bytes, err := base64.RawURLEncoding.DecodeString(data)
 	if err != nil {
 		return nil, err
 	}
@@ -30,3 +78,39 @@ func UnmarshalAttachment(data string) (*Attachment, error) {
 	}
 	return a, nil
 }
+
+// VerifyAttachment decodes the callback data and checks its signature,
+// attachments issued longer than ttl ago are expired, they never expire when ttl is 0
+func VerifyAttachment(data string, ttl time.Duration) (*Attachment, error) {
+	a, err := UnmarshalAttachment(data)
+	if err != nil {
+		return nil, ErrAttachmentSignature
+	}
+	signature, err := a.signature()
+	if err != nil || !hmac.Equal(signature, a.GetSignature()) {
+		return nil, ErrAttachmentSignature
+	}
+	if ttl > 0 && now().Sub(a.IssuedTime()) > ttl {
+		return nil, ErrAttachmentExpired
+	}
+	return a, nil
+}
+
+// IssuedTime the time the attachment is signed, in minute precision
+func (x *Attachment) IssuedTime() time.Time {
+	return time.Unix(int64(x.GetIssuedAt())*60, 0)
+}
+
+func ContentHash(hashID string) uint32 {
+	hash, err := strconv.ParseUint(hashID, 16, 32)
+	if err != nil {
+		log.Errorf("parse content hash id %s failed, %v", hashID, err)
+		return 0
+	}
+	return uint32(hash)
+}
+
+// GetHashID gets the content hash id carried by the attachment
+func (x *Attachment) GetHashID() string {
+	return fmt.Sprintf("%08x", x.GetContentHash())
+}
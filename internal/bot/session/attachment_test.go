@@ -1,9 +1,12 @@
 package session
 
 import (
+	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestAttachment(t *testing.T) {
@@ -17,4 +20,101 @@ func TestAttachment(t *testing.T) {
 			assert.Equal(t, a.GetSourceId(), a2.GetSourceId())
 		},
 	)
+
+	t.Run(
+		"content hash", func(t *testing.T) {
+			a := &Attachment{UserId: -1001234567890, SourceId: 321, ContentHash: ContentHash("06b2e254")}
+			data := Marshal(a)
+			assert.LessOrEqual(t, len(data), 40)
+			a2, err := UnmarshalAttachment(data)
+			assert.Nil(t, err)
+			assert.Equal(t, "06b2e254", a2.GetHashID())
+		},
+	)
+}
+
+func TestVerifyAttachment(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	issued := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return issued }
+
+	SetSecret("token")
+	defer SetSecret("")
+
+	data := Marshal(&Attachment{UserId: -1001234567890, SourceId: 321, Value: 30})
+
+	t.Run(
+		"signed", func(t *testing.T) {
+			a, err := VerifyAttachment(data, time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(-1001234567890), a.GetUserId())
+			assert.Equal(t, uint32(321), a.GetSourceId())
+			assert.Equal(t, int32(30), a.GetValue())
+			assert.Equal(t, issued, a.IssuedTime().UTC())
+		},
+	)
+
+	t.Run(
+		"expired", func(t *testing.T) {
+			now = func() time.Time { return issued.Add(2 * time.Hour) }
+			defer func() { now = func() time.Time { return issued } }()
+
+			_, err := VerifyAttachment(data, time.Hour)
+			assert.ErrorIs(t, err, ErrAttachmentExpired)
+			_, err = VerifyAttachment(data, 0)
+			assert.Nil(t, err)
+		},
+	)
+
+	t.Run(
+		"tampered", func(t *testing.T) {
+			a, err := UnmarshalAttachment(data)
+			assert.Nil(t, err)
+			a.UserId = -1009876543210
+			tampered, err := proto.Marshal(a)
+			assert.Nil(t, err)
+			_, err = VerifyAttachment(base64.RawURLEncoding.EncodeToString(tampered), time.Hour)
+			assert.ErrorIs(t, err, ErrAttachmentSignature)
+
+			_, err = VerifyAttachment("", time.Hour)
+			assert.ErrorIs(t, err, ErrAttachmentSignature)
+			_, err = VerifyAttachment("not base64!", time.Hour)
+			assert.ErrorIs(t, err, ErrAttachmentSignature)
+		},
+	)
+
+	t.Run(
+		"other secret", func(t *testing.T) {
+			SetSecret("other token")
+			defer SetSecret("token")
+
+			_, err := VerifyAttachment(data, time.Hour)
+			assert.ErrorIs(t, err, ErrAttachmentSignature)
+		},
+	)
+}
+
+// TestAttachmentCallbackLength checks the largest attachments of the buttons fit Telegram's 64 bytes callback data,
+// telebot sends it as "\f<unique>|<data>"
+func TestAttachmentCallbackLength(t *testing.T) {
+	const channelID = -1009999999999
+	tests := []struct {
+		unique string
+		a      *Attachment
+	}{
+		{"set_toggle_edit_update_btn", &Attachment{UserId: channelID, SourceId: 1 << 20}},
+		{"set_preview_len_btn", &Attachment{UserId: channelID, SourceId: 1 << 20, Value: 4096}},
+		{"set_interval_btn", &Attachment{UserId: channelID, SourceId: 1 << 20, Value: 10080}},
+		{"set_feed_page_btn", &Attachment{UserId: channelID, SourceId: 1 << 20, Page: 10}},
+		{"content_pause_btn", &Attachment{UserId: channelID, SourceId: 1 << 20, ContentHash: 0xffffffff}},
+		{"list_page_btn", &Attachment{UserId: channelID, Page: 1000}},
+		{"undo_unsub_btn", &Attachment{UserId: channelID, RemovalId: 0xffffffff}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.unique, func(t *testing.T) {
+				assert.LessOrEqual(t, len("\f"+tt.unique+"|"+Marshal(tt.a)), 64)
+			},
+		)
+	}
 }
@@ -7,6 +7,7 @@ type BotContextStoreKey string
 
 const (
 	StoreKeyMentionChat BotContextStoreKey = "mention_chat"
+	StoreKeyLanguage    BotContextStoreKey = "language"
 )
 
 func (k BotContextStoreKey) String() string {
@@ -25,3 +26,9 @@ func GetMentionChatFromCtxStore(ctx tb.Context) (*tb.Chat, bool) {
 	}
 	return mentionChat, true
 }
+
+// GetLanguageFromCtxStore gets the reply language of the update, empty if not resolved
+func GetLanguageFromCtxStore(ctx tb.Context) string {
+	lang, _ := ctx.Get(StoreKeyLanguage.String()).(string)
+	return lang
+}
@@ -0,0 +1,225 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	tb "gopkg.in/telebot.v3"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const (
+	secretTokenHeader       = "X-Telegram-Bot-Api-Secret-Token"
+	webhookShutdownTimeout  = 5 * time.Second
+	webhookMaxRequestLength = 1 << 20
+)
+
+// webhookPoller receives the updates Telegram posts to the webhook
+type webhookPoller struct {
+	listen      string
+	publicURL   string
+	secretToken string
+	certificate string
+	tlsCert     string
+	tlsKey      string
+	client      *http.Client
+
+	listener net.Listener
+}
+
+// newPoller returns the webhook poller when a webhook url is configured, the long poller otherwise
+func newPoller(client *http.Client) (tb.Poller, error) {
+	if config.WebhookURL == "" {
+		return &tb.LongPoller{Timeout: 10 * time.Second}, nil
+	}
+
+	secretToken := config.WebhookSecretToken
+	if secretToken == "" {
+		var err error
+		if secretToken, err = randomSecretToken(); err != nil {
+			return nil, err
+		}
+	}
+	return &webhookPoller{
+		listen:      config.WebhookListen,
+		publicURL:   config.WebhookURL,
+		secretToken: secretToken,
+		certificate: config.WebhookCertificate,
+		tlsCert:     config.WebhookTLSCert,
+		tlsKey:      config.WebhookTLSKey,
+		client:      client,
+	}, nil
+}
+
+func randomSecretToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// setupUpdates prepares the bot for its poller: the webhook mode opens the listener and sets the webhook,
+// the polling mode removes a webhook left behind, Telegram refuses getUpdates while a webhook is set
+func setupUpdates(bot *tb.Bot) error {
+	p, ok := bot.Poller.(*webhookPoller)
+	if !ok {
+		return removeWebhook(bot)
+	}
+
+	if p.listener == nil {
+		listener, err := net.Listen("tcp", p.listen)
+		if err != nil {
+			return err
+		}
+		p.listener = listener
+	}
+
+	if err := p.setWebhook(bot); err != nil {
+		p.listener.Close()
+		p.listener = nil
+		return err
+	}
+	log.Infof("webhook set to %s, listening on %s", p.publicURL, p.listener.Addr())
+	return nil
+}
+
+func removeWebhook(bot *tb.Bot) error {
+	info, err := bot.Webhook()
+	if err != nil {
+		return err
+	}
+	// the url of the webhook info is decoded into Listen
+	if info.Listen == "" {
+		return nil
+	}
+
+	log.Infof("remove webhook %s for long polling", info.Listen)
+	return bot.RemoveWebhook()
+}
+
+// setWebhook uploads the parameters as multipart form itself,
+// telebot sends the certificate without a file name and it would not be recognised as a file
+func (p *webhookPoller) setWebhook(bot *tb.Bot) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("url", p.publicURL)
+	_ = writer.WriteField("secret_token", p.secretToken)
+	if p.certificate != "" {
+		data, err := os.ReadFile(p.certificate)
+		if err != nil {
+			return err
+		}
+		part, err := writer.CreateFormFile("certificate", filepath.Base(p.certificate))
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(bot.URL+"/bot"+bot.Token+"/setWebhook", writer.FormDataContentType(), body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("set webhook failed, %s", resp.Status)
+	}
+	if !result.Ok {
+		return fmt.Errorf("set webhook failed, %s", result.Description)
+	}
+	return nil
+}
+
+// Poll serves the webhook until the bot is stopped, the listener is opened by setupUpdates
+func (p *webhookPoller) Poll(b *tb.Bot, dest chan tb.Update, stop chan struct{}) {
+	if p.listener == nil {
+		log.Error("webhook listener is not open")
+		<-stop
+		return
+	}
+
+	server := &http.Server{Handler: p.handler(dest, stop)}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Errorf("shutdown webhook server failed, %v", err)
+		}
+	}()
+
+	var err error
+	if p.tlsCert != "" {
+		err = server.ServeTLS(p.listener, p.tlsCert, p.tlsKey)
+	} else {
+		err = server.Serve(p.listener)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Errorf("webhook server failed, %v", err)
+		<-stop
+	}
+	<-done
+	p.listener = nil
+}
+
+func (p *webhookPoller) handler(dest chan<- tb.Update, stop <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		token := r.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(p.secretToken)) != 1 {
+			log.Warnf("webhook request from %s with invalid secret token", r.RemoteAddr)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		var update tb.Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, webhookMaxRequestLength)).Decode(&update); err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case dest <- update:
+		case <-stop:
+			// Telegram retries the update after the restart
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		case <-r.Context().Done():
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tb "gopkg.in/telebot.v3"
+)
+
+// fakeBotAPI is a local Bot API server recording the webhook calls
+type fakeBotAPI struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	webhookURL  string
+	secretToken string
+	certificate string
+	calls       []string
+	// threads lists the message_thread_id of every sendMessage call,
+	// only the general topic exists in the fake chats
+	threads []string
+}
+
+func newFakeBotAPI(t *testing.T, webhookURL string) *fakeBotAPI {
+	api := &fakeBotAPI{webhookURL: webhookURL}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func (api *fakeBotAPI) serve(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.calls = append(api.calls, method)
+
+	var result string
+	switch method {
+	case "getMe":
+		result = `{"id":1,"is_bot":true,"first_name":"bot","username":"bot"}`
+	case "getWebhookInfo":
+		result = fmt.Sprintf(`{"url":%q,"has_custom_certificate":false,"pending_update_count":0}`, api.webhookURL)
+	case "setWebhook":
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: invalid form"}`)
+			return
+		}
+		if file, _, err := r.FormFile("certificate"); err == nil {
+			data, _ := io.ReadAll(file)
+			api.certificate = string(data)
+		}
+		api.webhookURL = r.FormValue("url")
+		api.secretToken = r.FormValue("secret_token")
+		result = "true"
+	case "deleteWebhook":
+		api.webhookURL = ""
+		result = "true"
+	case "sendMessage":
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: invalid body"}`)
+			return
+		}
+		api.threads = append(api.threads, params["message_thread_id"])
+		if thread := params["message_thread_id"]; thread != "" && thread != "0" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: message thread not found"}`)
+			return
+		}
+		result = fmt.Sprintf(`{"message_id":%d,"date":0,"chat":{"id":%s,"type":"supergroup"}}`, len(api.calls), params["chat_id"])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"ok":false,"error_code":404,"description":"Not Found"}`)
+		return
+	}
+	fmt.Fprintf(w, `{"ok":true,"result":%s}`, result)
+}
+
+func (api *fakeBotAPI) Calls() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]string(nil), api.calls...)
+}
+
+func newTestBot(t *testing.T, api *fakeBotAPI, poller tb.Poller) *tb.Bot {
+	bot, err := tb.NewBot(tb.Settings{URL: api.URL, Token: "token", Poller: poller})
+	require.NoError(t, err)
+	return bot
+}
+
+func TestSetupUpdates(t *testing.T) {
+	t.Run(
+		"polling removes the webhook", func(t *testing.T) {
+			api := newFakeBotAPI(t, "https://example.com/hook")
+			bot := newTestBot(t, api, &tb.LongPoller{})
+			assert.NoError(t, setupUpdates(bot))
+			assert.Equal(t, []string{"getMe", "getWebhookInfo", "deleteWebhook"}, api.Calls())
+			assert.Empty(t, api.webhookURL)
+		},
+	)
+
+	t.Run(
+		"polling without webhook", func(t *testing.T) {
+			api := newFakeBotAPI(t, "")
+			bot := newTestBot(t, api, &tb.LongPoller{})
+			assert.NoError(t, setupUpdates(bot))
+			assert.Equal(t, []string{"getMe", "getWebhookInfo"}, api.Calls())
+		},
+	)
+
+	t.Run(
+		"webhook uploads the certificate", func(t *testing.T) {
+			certificate := filepath.Join(t.TempDir(), "cert.pem")
+			require.NoError(t, os.WriteFile(certificate, []byte("certificate"), 0o600))
+
+			api := newFakeBotAPI(t, "")
+			poller := &webhookPoller{
+				listen:      "127.0.0.1:0",
+				publicURL:   "https://example.com/hook",
+				secretToken: "secret",
+				certificate: certificate,
+			}
+			bot := newTestBot(t, api, poller)
+			require.NoError(t, setupUpdates(bot))
+			defer poller.listener.Close()
+
+			assert.Equal(t, "https://example.com/hook", api.webhookURL)
+			assert.Equal(t, "secret", api.secretToken)
+			assert.Equal(t, "certificate", api.certificate)
+		},
+	)
+
+	t.Run(
+		"webhook listen failed", func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			api := newFakeBotAPI(t, "")
+			poller := &webhookPoller{listen: listener.Addr().String(), publicURL: "https://example.com/hook"}
+			bot := newTestBot(t, api, poller)
+			assert.Error(t, setupUpdates(bot))
+			assert.Equal(t, []string{"getMe"}, api.Calls())
+		},
+	)
+}
+
+func TestWebhookPoller(t *testing.T) {
+	api := newFakeBotAPI(t, "")
+	poller := &webhookPoller{listen: "127.0.0.1:0", publicURL: "https://example.com/hook", secretToken: "secret"}
+	bot := newTestBot(t, api, poller)
+
+	received := make(chan string, 1)
+	bot.Handle(tb.OnText, func(c tb.Context) error {
+		received <- c.Text()
+		return nil
+	})
+	require.NoError(t, setupUpdates(bot))
+	hookURL := "http://" + poller.listener.Addr().String()
+
+	stopped := make(chan struct{})
+	go func() {
+		bot.Start()
+		close(stopped)
+	}()
+
+	post := func(token, body string) int {
+		req, err := http.NewRequest(http.MethodPost, hookURL, strings.NewReader(body))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set(secretTokenHeader, token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	update := `{"update_id":1,"message":{"message_id":1,"chat":{"id":1,"type":"private"},"text":"hello"}}`
+
+	assert.Equal(t, http.StatusUnauthorized, post("", update))
+	assert.Equal(t, http.StatusUnauthorized, post("wrong", update))
+	assert.Equal(t, http.StatusBadRequest, post("secret", "not json"))
+
+	resp, err := http.Get(hookURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	assert.Equal(t, http.StatusOK, post("secret", update))
+	select {
+	case text := <-received:
+		assert.Equal(t, "hello", text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("update not received")
+	}
+
+	bot.Stop()
+	<-stopped
+	_, err = http.Post(hookURL, "application/json", strings.NewReader(update))
+	assert.Error(t, err)
+}
@@ -19,6 +19,9 @@ import (
 	tb "gopkg.in/telebot.v3"
 )
 
+// webhookSecretTokenRegexp the characters Telegram allows in a webhook secret token
+var webhookSecretTokenRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 func init() {
 	if isInTests() {
 		// 测试环境
@@ -97,6 +100,21 @@ func init() {
 			AllowUsers = append(AllowUsers, userID)
 		}
 	}
+	// configs listing allowed users before the switch existed keep their access restricted
+	AllowlistEnabled = len(AllowUsers) > 0
+	if viper.IsSet("allowlist") {
+		AllowlistEnabled = viper.GetBool("allowlist")
+	}
+
+	if viper.IsSet("admin_users") {
+		for _, userIDStr := range viper.GetStringSlice("admin_users") {
+			userID, err := strconv.ParseInt(userIDStr, 10, 64)
+			if err != nil {
+				panic(fmt.Errorf("Fatal error config file: %s", err))
+			}
+			AdminUsers = append(AdminUsers, userID)
+		}
+	}
 
 	if viper.IsSet("disable_web_page_preview") {
 		DisableWebPagePreview = viper.GetBool("disable_web_page_preview")
@@ -106,6 +124,23 @@ func init() {
 		TelegramEndpoint = viper.GetString("telegram.endpoint")
 	}
 
+	if viper.IsSet("webhook.url") {
+		WebhookURL = viper.GetString("webhook.url")
+		if viper.IsSet("webhook.listen") {
+			WebhookListen = viper.GetString("webhook.listen")
+		}
+		WebhookSecretToken = viper.GetString("webhook.secret_token")
+		if WebhookSecretToken != "" && !webhookSecretTokenRegexp.MatchString(WebhookSecretToken) {
+			panic(fmt.Errorf("Fatal error config file: webhook.secret_token must be 1-256 characters of A-Z, a-z, 0-9, _ and -"))
+		}
+		WebhookCertificate = viper.GetString("webhook.certificate")
+		WebhookTLSCert = viper.GetString("webhook.tls_cert")
+		WebhookTLSKey = viper.GetString("webhook.tls_key")
+		if (WebhookTLSCert == "") != (WebhookTLSKey == "") {
+			panic(fmt.Errorf("Fatal error config file: webhook.tls_cert and webhook.tls_key must be set together"))
+		}
+	}
+
 	if viper.IsSet("error_threshold") {
 		ErrorThreshold = uint(viper.GetInt("error_threshold"))
 	}
@@ -114,6 +149,14 @@ func init() {
 		UpdateInterval = viper.GetInt("update_interval")
 	}
 
+	if viper.IsSet("fetch_history_days") {
+		FetchHistoryDays = viper.GetInt("fetch_history_days")
+	}
+
+	if viper.IsSet("undo_period") {
+		UndoPeriod = viper.GetInt("undo_period")
+	}
+
 	if viper.IsSet("mysql.host") {
 		EnableMysql = true
 		mysqlConfig = mysql.NewConfig()
@@ -47,15 +47,43 @@ var (
 	// TelegramEndpoint Telegram bot server address, default is empty
 	TelegramEndpoint string = tb.DefaultApiURL
 
+	// WebhookURL Public URL Telegram posts the updates to, the bot uses long polling when it is empty
+	WebhookURL string
+
+	// WebhookListen Local address the webhook server listens on
+	WebhookListen string = ":8443"
+
+	// WebhookSecretToken Token Telegram sends in every webhook request, a random one is used when it is empty
+	WebhookSecretToken string
+
+	// WebhookCertificate Self-signed public certificate uploaded to Telegram when the webhook is set
+	WebhookCertificate string
+
+	// WebhookTLSCert WebhookTLSKey Certificate and key the webhook server serves TLS with, plain HTTP when empty
+	WebhookTLSCert string
+	WebhookTLSKey  string
+
 	// UserAgent User-Agent
 	UserAgent string
 
 	// RunMode Running mode Release / Debug
 	RunMode RunType = ReleaseMode
 
-	// AllowUsers Users allowed to use the bot
+	// AllowlistEnabled Only the users on the allowlist and the admins may use the bot, everyone may otherwise
+	AllowlistEnabled bool
+
+	// AllowUsers Users added to the allowlist on every start, the allowlist is kept in the database
 	AllowUsers []int64
 
+	// AdminUsers Bot admins, allowed to use the /admin commands
+	AdminUsers []int64
+
+	// FetchHistoryDays Days the fetch history of the sources is kept
+	FetchHistoryDays int = 30
+
+	// UndoPeriod Minutes removed subscriptions can be restored before they are purged
+	UndoPeriod int = 10
+
 	// DBLogMode Whether to print database logs
 	DBLogMode bool = false
 )
@@ -116,3 +144,13 @@ func GetString(key string) string {
 func GetMysqlDSN() string {
 	return mysqlConfig.FormatDSN()
 }
+
+// IsAdmin checks if a user is a bot admin
+func IsAdmin(userID int64) bool {
+	for _, id := range AdminUsers {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
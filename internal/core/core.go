@@ -2,17 +2,23 @@ package core
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"html"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/glebarez/sqlite"
+	strip "github.com/grokify/html-strip-tags-go"
 	"github.com/mmcdole/gofeed"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
+	"github.com/andatoshiki/toshiki-rssbot/internal/backup"
 	"github.com/andatoshiki/toshiki-rssbot/internal/config"
 	"github.com/andatoshiki/toshiki-rssbot/internal/feed"
 	"github.com/andatoshiki/toshiki-rssbot/internal/log"
@@ -27,14 +33,35 @@ var (
 	ErrSubscriptionNotExist = errors.New("subscription not exist")
 	ErrSourceNotExist       = errors.New("source not exist")
 	ErrContentNotExist      = errors.New("content not exist")
+	ErrBookmarkExist        = errors.New("already bookmarked")
+	ErrBookmarkNotExist     = errors.New("bookmark not exist")
+	ErrTagExist             = errors.New("tag already exists")
+	ErrTagNotExist          = errors.New("tag not exist")
+	ErrInviteNotExist       = errors.New("invite not exist")
 )
 
+const (
+	// maxBackupTitleLength longest subscription title restored from a backup
+	maxBackupTitleLength = 256
+	// maxBackupPreviewLength longest preview text restored from a backup, a message holds 4096 characters
+	maxBackupPreviewLength = 4096
+)
+
+// maxSourceErrorLength max length of the last fetch error kept on a source
+const maxSourceErrorLength = 200
+
 type Core struct {
 	// Storage
 	userStorage         storage.User
 	contentStorage      storage.Content
 	sourceStorage       storage.Source
 	subscriptionStorage storage.Subscription
+	messageStorage      storage.Message
+	preferenceStorage   storage.Preference
+	bookmarkStorage     storage.Bookmark
+	tagStorage          storage.Tag
+	fetchHistoryStorage storage.FetchHistory
+	chatStorage         storage.Chat
 
 	feedParser *feed.FeedParser
 	httpClient *client.HttpClient
@@ -53,6 +80,12 @@ func NewCore(
 	contentStorage storage.Content,
 	sourceStorage storage.Source,
 	subscriptionStorage storage.Subscription,
+	messageStorage storage.Message,
+	preferenceStorage storage.Preference,
+	bookmarkStorage storage.Bookmark,
+	tagStorage storage.Tag,
+	fetchHistoryStorage storage.FetchHistory,
+	chatStorage storage.Chat,
 	parser *feed.FeedParser,
 	httpClient *client.HttpClient,
 ) *Core {
@@ -61,6 +94,12 @@ func NewCore(
 		contentStorage:      contentStorage,
 		sourceStorage:       sourceStorage,
 		subscriptionStorage: subscriptionStorage,
+		messageStorage:      messageStorage,
+		preferenceStorage:   preferenceStorage,
+		bookmarkStorage:     bookmarkStorage,
+		tagStorage:          tagStorage,
+		fetchHistoryStorage: fetchHistoryStorage,
+		chatStorage:         chatStorage,
 		feedParser:          parser,
 		httpClient:          httpClient,
 	}
@@ -110,6 +149,12 @@ func NewCoreFormConfig() *Core {
 		storage.NewContentStorageImpl(db),
 		storage.NewSourceStorageImpl(db),
 		subscriptionStorage,
+		storage.NewMessageStorageImpl(db),
+		storage.NewPreferenceStorageImpl(db),
+		storage.NewBookmarkStorageImpl(db),
+		storage.NewTagStorageImpl(db),
+		storage.NewFetchHistoryStorageImpl(db),
+		storage.NewChatStorageImpl(db),
 		feedParser,
 		httpClient,
 	)
@@ -128,12 +173,50 @@ func (c *Core) Init() error {
 	if err := c.subscriptionStorage.Init(context.Background()); err != nil {
 		return err
 	}
+	if err := c.messageStorage.Init(context.Background()); err != nil {
+		return err
+	}
+	if err := c.preferenceStorage.Init(context.Background()); err != nil {
+		return err
+	}
+	if err := c.bookmarkStorage.Init(context.Background()); err != nil {
+		return err
+	}
+	// after the subscriptions, tags of existing subscriptions are migrated into the tags table
+	if err := c.tagStorage.Init(context.Background()); err != nil {
+		return err
+	}
+	if err := c.fetchHistoryStorage.Init(context.Background()); err != nil {
+		return err
+	}
+	if err := c.chatStorage.Init(context.Background()); err != nil {
+		return err
+	}
+	return c.seedAllowedUsers(context.Background())
+}
+
+// seedAllowedUsers adds the users allowed in the config to the allowlist on every start,
+// including the ones known before, a user denied by an admin has to be removed from the config as well
+func (c *Core) seedAllowedUsers(ctx context.Context) error {
+	for _, userID := range config.AllowUsers {
+		if err := c.userStorage.SetUserAllowed(ctx, userID, true); err != nil {
+			return err
+		}
+	}
+	if len(config.AllowUsers) > 0 {
+		log.Infof("seeded %d allowed users", len(config.AllowUsers))
+	}
 	return nil
 }
 
 // GetUserSubscribedSources gets the subscribed sources of a user
 func (c *Core) GetUserSubscribedSources(ctx context.Context, userID int64) ([]*model.Source, error) {
-	opt := &storage.GetSubscriptionsOptions{Count: -1}
+	return c.GetUserTagSubscribedSources(ctx, userID, "")
+}
+
+// GetUserTagSubscribedSources gets the sources a user subscribed with the tag, tag empty to not filter
+func (c *Core) GetUserTagSubscribedSources(ctx context.Context, userID int64, tag string) ([]*model.Source, error) {
+	opt := &storage.GetSubscriptionsOptions{Count: -1, Tag: tag}
 	result, err := c.subscriptionStorage.GetSubscriptionsByUserID(ctx, userID, opt)
 	if err != nil {
 		return nil, err
@@ -151,6 +234,34 @@ func (c *Core) GetUserSubscribedSources(ctx context.Context, userID int64) ([]*m
 	return sources, nil
 }
 
+// GetUserSubscriptions gets all subscriptions of a user
+func (c *Core) GetUserSubscriptions(ctx context.Context, userID int64) ([]*model.Subscribe, error) {
+	opt := &storage.GetSubscriptionsOptions{Count: -1}
+	result, err := c.subscriptionStorage.GetSubscriptionsByUserID(ctx, userID, opt)
+	if err != nil {
+		return nil, err
+	}
+	return result.Subscriptions, nil
+}
+
+// GetUserTagSubscriptions gets all subscriptions of a user with the tag
+func (c *Core) GetUserTagSubscriptions(ctx context.Context, userID int64, tag string) ([]*model.Subscribe, error) {
+	opt := &storage.GetSubscriptionsOptions{Count: -1, Tag: tag}
+	result, err := c.subscriptionStorage.GetSubscriptionsByUserID(ctx, userID, opt)
+	if err != nil {
+		return nil, err
+	}
+	return result.Subscriptions, nil
+}
+
+// GetUserSubscriptionPage gets a page of subscriptions of a user, tag empty to not filter
+func (c *Core) GetUserSubscriptionPage(
+	ctx context.Context, userID int64, tag string, offset int, count int,
+) (*storage.GetSubscriptionsResult, error) {
+	opt := &storage.GetSubscriptionsOptions{Count: count, Offset: offset, Tag: tag}
+	return c.subscriptionStorage.GetSubscriptionsByUserID(ctx, userID, opt)
+}
+
 // AddSubscription adds a subscription
 func (c *Core) AddSubscription(ctx context.Context, userID int64, sourceID uint) error {
 	exist, err := c.subscriptionStorage.SubscriptionExist(ctx, userID, sourceID)
@@ -173,40 +284,97 @@ func (c *Core) AddSubscription(ctx context.Context, userID int64, sourceID uint)
 	return c.subscriptionStorage.AddSubscription(ctx, subscription)
 }
 
-// Unsubscribe unsubscribes a user from a source
-func (c *Core) Unsubscribe(ctx context.Context, userID int64, sourceID uint) error {
-	exist, err := c.subscriptionStorage.SubscriptionExist(ctx, userID, sourceID)
+// Unsubscribe removes a subscription, it can be restored with UndoUnsubscribe until the undo period ends.
+// Returns the id of the removal the undo refers to
+func (c *Core) Unsubscribe(ctx context.Context, userID int64, sourceID uint) (uint32, error) {
+	removalID, err := newRemovalID()
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return removalID, c.unsubscribe(ctx, userID, sourceID, removalID)
+}
 
-	if !exist {
-		return ErrSubscriptionNotExist
+// newRemovalID generates the id of an unsubscription, the subscriptions it removes are restored together
+func newRemovalID() (uint32, error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return 0, err
 	}
+	// 0 is kept for the subscriptions removed before the removals had ids
+	return binary.BigEndian.Uint32(id) | 1, nil
+}
 
-	// Remove the user's subscription
-	_, err = c.subscriptionStorage.DeleteSubscription(ctx, userID, sourceID)
+func (c *Core) unsubscribe(ctx context.Context, userID int64, sourceID uint, removalID uint32) error {
+	removed, err := c.subscriptionStorage.RemoveSubscriptions(ctx, userID, sourceID, removalID, time.Now())
 	if err != nil {
 		return err
 	}
+	if removed == 0 {
+		return ErrSubscriptionNotExist
+	}
+	return nil
+}
 
-	// Get the subscription count of the source
-	count, err := c.subscriptionStorage.CountSourceSubscriptions(ctx, sourceID)
+// UndoUnsubscribe restores the subscriptions of a chat removed by an unsubscription within the undo period.
+// The sources subscribed again since are skipped, returns the number of subscriptions restored
+func (c *Core) UndoUnsubscribe(ctx context.Context, userID int64, removalID uint32) (int64, error) {
+	// the purge may not have run yet, or may be failing
+	return c.subscriptionStorage.RestoreSubscriptions(ctx, userID, removalID, time.Now().Add(-c.undoPeriod()))
+}
+
+// PurgeRemovedSubscriptions deletes the subscriptions removed before the undo period with their tags,
+// then the sources left without subscriptions with their contents
+func (c *Core) PurgeRemovedSubscriptions(ctx context.Context) error {
+	before := time.Now().Add(-c.undoPeriod())
+	subscriptions, err := c.subscriptionStorage.GetRemovedSubscriptions(ctx, before)
 	if err != nil {
 		return err
 	}
 
-	if count != 0 {
-		return nil
+	ids := make([]uint, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if err := c.tagStorage.DeleteSubscriptionTags(ctx, subscription); err != nil {
+			return err
+		}
+		// the messages can no longer be edited once the subscription is gone,
+		// unless the chat subscribed to the source again and they belong to the new subscription
+		exist, err := c.subscriptionStorage.SubscriptionExist(ctx, subscription.UserID, subscription.SourceID)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			if _, err := c.messageStorage.DeleteChatMessages(ctx, subscription.UserID, subscription.SourceID); err != nil {
+				return err
+			}
+		}
+		ids = append(ids, subscription.ID)
+	}
+	purged, err := c.subscriptionStorage.PurgeSubscriptions(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Infof("purged %d removed subscriptions", purged)
 	}
 
-	// If the source no longer has any subscribed users, remove the source
-	if err := c.removeSource(ctx, sourceID); err != nil {
+	// sources created in the meantime are left alone, they are about to be subscribed
+	sources, err := c.sourceStorage.GetOrphanSources(ctx, before)
+	if err != nil {
 		return err
 	}
+	for _, source := range sources {
+		// one source failing is retried by the next purge, the others are still removed
+		if err := c.removeSource(ctx, source.ID); err != nil {
+			log.Errorf("remove orphan source %d failed, %v", source.ID, err)
+		}
+	}
 	return nil
 }
 
+func (c *Core) undoPeriod() time.Duration {
+	return time.Duration(config.UndoPeriod) * time.Minute
+}
+
 // removeSource removes a source
 func (c *Core) removeSource(ctx context.Context, sourceID uint) error {
 	if err := c.sourceStorage.Delete(ctx, sourceID); err != nil {
@@ -217,7 +385,12 @@ func (c *Core) removeSource(ctx context.Context, sourceID uint) error {
 	if err != nil {
 		return err
 	}
-	log.Infof("remove source %d and %d contents", sourceID, count)
+
+	messageCount, err := c.messageStorage.DeleteSourceMessages(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	log.Infof("remove source %d, %d contents and %d messages", sourceID, count, messageCount)
 	return nil
 }
 
@@ -305,6 +478,8 @@ func (c *Core) AddSourceContents(
 			HashID:       model.GenHashID(source.Link, item.GUID),
 			RawLink:      item.Link,
 			TelegraphURL: previewURL,
+			Fingerprint:  model.GenFingerprint(item.Title, item.Content),
+			Text:         itemText(item),
 		}
 		contents = append(contents, content)
 		go func() {
@@ -318,26 +493,123 @@ func (c *Core) AddSourceContents(
 	return contents, nil
 }
 
-// UnsubscribeAllSource unsubscribes a user from all sources
-func (c *Core) UnsubscribeAllSource(ctx context.Context, userID int64) error {
-	sources, err := c.GetUserSubscribedSources(ctx, userID)
+// UpdateSourceContents saves the items of a source that changed upstream and returns the updated contents
+func (c *Core) UpdateSourceContents(
+	ctx context.Context, source *model.Source, items []*gofeed.Item,
+) ([]*model.Content, error) {
+	var contents []*model.Content
+	for _, item := range items {
+		content, err := c.contentStorage.GetContentByHashID(ctx, model.GenHashID(source.Link, item.GUID))
+		if err != nil {
+			if err == storage.ErrRecordNotFound {
+				continue
+			}
+			return nil, err
+		}
+
+		fingerprint := model.GenFingerprint(item.Title, item.Content)
+		if content.Fingerprint == fingerprint {
+			continue
+		}
+
+		// Contents saved before fingerprints existed are only backfilled
+		changed := content.Fingerprint != ""
+		content.Title = strings.Trim(item.Title, " ")
+		content.Description = item.Content
+		content.RawLink = item.Link
+		content.Fingerprint = fingerprint
+		content.Text = itemText(item)
+		if changed && config.EnableTelegraph && content.TelegraphURL != "" {
+			_ = tgraph.EditHtml(content.TelegraphURL, source.Title, item.Title, item.Link, item.Content)
+		}
+
+		if err := c.contentStorage.UpdateContent(ctx, content); err != nil {
+			log.Errorf("update content %#v failed, %v", content, err)
+			continue
+		}
+		if changed {
+			contents = append(contents, content)
+		}
+	}
+	return contents, nil
+}
+
+// itemText gets the plain text of an item for search
+func itemText(item *gofeed.Item) string {
+	desc := item.Content
+	if desc == "" {
+		desc = item.Description
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(strip.StripTags(desc))), " ")
+}
+
+// SearchContents searches the contents of the sources a user subscribed, sourceID 0 searches all of them
+func (c *Core) SearchContents(
+	ctx context.Context, userID int64, sourceID uint, opts *storage.SearchContentsOptions,
+) (*storage.SearchContentsResult, error) {
+	subs, err := c.GetUserSubscriptions(ctx, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var wg sync.WaitGroup
-	for i := range sources {
-		wg.Add(1)
-		i := i
-		go func() {
-			defer wg.Done()
-			if err := c.Unsubscribe(ctx, userID, sources[i].ID); err != nil {
-				log.Errorf("user %d unsubscribe %d failed, %v", userID, sources[i].ID, err)
-			}
-		}()
+	var sourceIDs []uint
+	for _, sub := range subs {
+		if sourceID == 0 || sub.SourceID == sourceID {
+			sourceIDs = append(sourceIDs, sub.SourceID)
+		}
 	}
-	wg.Wait()
-	return nil
+	if sourceID != 0 && len(sourceIDs) == 0 {
+		return nil, ErrSubscriptionNotExist
+	}
+	opts.SourceIDs = sourceIDs
+	return c.contentStorage.SearchContents(ctx, opts)
+}
+
+// AddContentMessage records the message a content was delivered as
+func (c *Core) AddContentMessage(ctx context.Context, content *model.Content, chatID int64, messageID int) error {
+	return c.messageStorage.AddMessage(
+		ctx, &model.Message{
+			SourceID:  content.SourceID,
+			HashID:    content.HashID,
+			ChatID:    chatID,
+			MessageID: messageID,
+		},
+	)
+}
+
+// GetContentMessages gets all messages a content was delivered as
+func (c *Core) GetContentMessages(ctx context.Context, hashID string) ([]*model.Message, error) {
+	return c.messageStorage.GetMessagesByHashID(ctx, hashID)
+}
+
+// UnsubscribeAllSource unsubscribes a user from all sources, returns the id of the removal the undo refers to
+func (c *Core) UnsubscribeAllSource(ctx context.Context, userID int64) (uint32, error) {
+	removalID, err := newRemovalID()
+	if err != nil {
+		return 0, err
+	}
+	_, err = c.subscriptionStorage.RemoveSubscriptions(ctx, userID, 0, removalID, time.Now())
+	return removalID, err
+}
+
+// UnsubscribeTag unsubscribes a user from all sources subscribed with the tag,
+// returns the number of unsubscribed sources and the id of the removal the undo refers to
+func (c *Core) UnsubscribeTag(ctx context.Context, userID int64, tag string) (int, uint32, error) {
+	subscriptions, err := c.GetUserTagSubscriptions(ctx, userID, tag)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	removalID, err := newRemovalID()
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, subscription := range subscriptions {
+		if err := c.unsubscribe(ctx, userID, subscription.SourceID, removalID); err != nil {
+			return i, removalID, err
+		}
+	}
+	return len(subscriptions), removalID, nil
 }
 
 // GetSubscription gets a subscription
@@ -352,15 +624,142 @@ func (c *Core) GetSubscription(ctx context.Context, userID int64, sourceID uint)
 	return subscription, nil
 }
 
-// SetSubscriptionTag sets the tags of a subscription
+// SetSubscriptionTag sets the tags of a subscription, no tags clears them
 func (c *Core) SetSubscriptionTag(ctx context.Context, userID int64, sourceID uint, tags []string) error {
 	subscription, err := c.GetSubscription(ctx, userID, sourceID)
 	if err != nil {
 		return err
 	}
 
-	subscription.Tag = "#" + strings.Join(tags, " #")
-	return c.subscriptionStorage.UpdateSubscription(ctx, userID, sourceID, subscription)
+	tags = model.NormalizeTags(tags)
+	if err := c.tagStorage.SetSubscriptionTags(ctx, subscription, tags); err != nil {
+		return err
+	}
+	subscription.Tag = model.FormatTags(tags)
+	return c.subscriptionStorage.UpsertSubscription(ctx, userID, sourceID, subscription)
+}
+
+// GetTagCounts gets the tags of a user with the number of subscriptions of each tag
+func (c *Core) GetTagCounts(ctx context.Context, userID int64) ([]*storage.TagCount, error) {
+	return c.tagStorage.GetTagCounts(ctx, userID)
+}
+
+// RenameTag renames a tag of a user, returns the number of subscriptions with the tag
+func (c *Core) RenameTag(ctx context.Context, userID int64, from string, to string) (int64, error) {
+	from, to = model.NormalizeTag(from), model.NormalizeTag(to)
+	if err := c.checkTagExist(ctx, userID, from); err != nil {
+		return 0, err
+	}
+	if err := c.checkTagExist(ctx, userID, to); err == nil {
+		return 0, ErrTagExist
+	} else if err != ErrTagNotExist {
+		return 0, err
+	}
+	return c.tagStorage.MoveTag(ctx, userID, from, to)
+}
+
+// MergeTag moves the subscriptions of a tag of a user into another existing tag and removes the tag,
+// returns the number of subscriptions moved
+func (c *Core) MergeTag(ctx context.Context, userID int64, from string, to string) (int64, error) {
+	from, to = model.NormalizeTag(from), model.NormalizeTag(to)
+	if err := c.checkTagExist(ctx, userID, from); err != nil {
+		return 0, err
+	}
+	if err := c.checkTagExist(ctx, userID, to); err != nil {
+		return 0, err
+	}
+	return c.tagStorage.MoveTag(ctx, userID, from, to)
+}
+
+// DeleteTag removes a tag from all subscriptions of a user, returns the number of subscriptions affected
+func (c *Core) DeleteTag(ctx context.Context, userID int64, name string) (int64, error) {
+	name = model.NormalizeTag(name)
+	if err := c.checkTagExist(ctx, userID, name); err != nil {
+		return 0, err
+	}
+	return c.tagStorage.DeleteTag(ctx, userID, name)
+}
+
+func (c *Core) checkTagExist(ctx context.Context, userID int64, name string) error {
+	if _, err := c.tagStorage.GetTag(ctx, userID, name); err != nil {
+		if err == storage.ErrRecordNotFound {
+			return ErrTagNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+// SetSubscriptionPreview sets the preview text length and the web page preview mode of a subscription
+func (c *Core) SetSubscriptionPreview(
+	ctx context.Context, userID int64, sourceID uint, previewLength int, webPagePreview int,
+) error {
+	subscription, err := c.GetSubscription(ctx, userID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	subscription.PreviewLength = previewLength
+	subscription.WebPagePreview = webPagePreview
+	return c.subscriptionStorage.UpsertSubscription(ctx, userID, sourceID, subscription)
+}
+
+// SetSubscriptionTitle sets the name of the source in the chat, empty uses the source title
+func (c *Core) SetSubscriptionTitle(ctx context.Context, userID int64, sourceID uint, title string) error {
+	subscription, err := c.GetSubscription(ctx, userID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	subscription.Title = title
+	return c.subscriptionStorage.UpsertSubscription(ctx, userID, sourceID, subscription)
+}
+
+// SetSubscriptionThread binds a subscription to a forum topic, 0 unbinds it
+func (c *Core) SetSubscriptionThread(ctx context.Context, userID int64, sourceID uint, threadID int) error {
+	subscription, err := c.GetSubscription(ctx, userID, sourceID)
+	if err != nil {
+		return err
+	}
+
+	subscription.ThreadID = threadID
+	return c.subscriptionStorage.UpsertSubscription(ctx, userID, sourceID, subscription)
+}
+
+// AutoCreateTopics creates a forum topic named after the source for every subscription of a chat
+// delivered to the general topic and binds the subscription to it, returns the number of topics created.
+// Creating a topic failing stops, the topics created so far stay bound
+func (c *Core) AutoCreateTopics(
+	ctx context.Context, chatID int64, createTopic func(name string) (int, error),
+) (int, error) {
+	subscriptions, err := c.GetUserSubscriptions(ctx, chatID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, sub := range subscriptions {
+		if sub.ThreadID != 0 {
+			continue
+		}
+
+		source, err := c.GetSource(ctx, sub.SourceID)
+		if err != nil {
+			log.Errorf("get source %d failed, %v", sub.SourceID, err)
+			continue
+		}
+
+		threadID, err := createTopic(source.Title)
+		if err != nil {
+			return created, err
+		}
+		if err := c.SetSubscriptionThread(ctx, chatID, sub.SourceID, threadID); err != nil {
+			log.Errorf("set subscription %d %d thread failed, %v", chatID, sub.SourceID, err)
+			continue
+		}
+		created++
+	}
+	return created, nil
 }
 
 // SetSubscriptionInterval sets the update interval of a subscription
@@ -390,6 +789,19 @@ func (c *Core) DisableSourceUpdate(ctx context.Context, sourceID uint) error {
 	return c.sourceStorage.UpsertSource(ctx, sourceID, source)
 }
 
+// DisableUnsharedSourceUpdate pauses a source unless other chats subscribe to it as well,
+// pausing a source pauses it for every chat. Returns false when the source was left running
+func (c *Core) DisableUnsharedSourceUpdate(ctx context.Context, sourceID uint) (bool, error) {
+	count, err := c.subscriptionStorage.CountSourceSubscriptions(ctx, sourceID)
+	if err != nil {
+		return false, err
+	}
+	if count > 1 {
+		return false, nil
+	}
+	return true, c.DisableSourceUpdate(ctx, sourceID)
+}
+
 // ClearSourceErrorCount clears the error count of a source
 func (c *Core) ClearSourceErrorCount(ctx context.Context, sourceID uint) error {
 	source, err := c.GetSource(ctx, sourceID)
@@ -412,6 +824,98 @@ func (c *Core) SourceErrorCountIncr(ctx context.Context, sourceID uint) error {
 	return c.sourceStorage.UpsertSource(ctx, sourceID, source)
 }
 
+// SourceFetchSucceeded records a successful fetch of a source and clears its error count
+func (c *Core) SourceFetchSucceeded(ctx context.Context, sourceID uint) error {
+	source, err := c.GetSource(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	source.ErrorCount = 0
+	source.LastFetchedAt = &now
+	source.LastError = ""
+	return c.sourceStorage.UpsertSource(ctx, sourceID, source)
+}
+
+// SourceFetchFailed records a failed fetch of a source and increments its error count
+func (c *Core) SourceFetchFailed(ctx context.Context, sourceID uint, fetchErr error) error {
+	source, err := c.GetSource(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	source.ErrorCount += 1
+	source.LastFetchedAt = &now
+	source.LastError = fetchErr.Error()
+	if len([]rune(source.LastError)) > maxSourceErrorLength {
+		source.LastError = string([]rune(source.LastError)[:maxSourceErrorLength])
+	}
+	return c.sourceStorage.UpsertSource(ctx, sourceID, source)
+}
+
+// RecordSourceFetch records a fetch of a source in the fetch history
+func (c *Core) RecordSourceFetch(ctx context.Context, fetch *model.SourceFetch) error {
+	if len([]rune(fetch.Error)) > maxSourceErrorLength {
+		fetch.Error = string([]rune(fetch.Error)[:maxSourceErrorLength])
+	}
+	if err := c.fetchHistoryStorage.AddSourceFetch(ctx, fetch); err != nil {
+		return err
+	}
+	if fetch.NewItems == 0 {
+		return nil
+	}
+
+	source, err := c.GetSource(ctx, fetch.SourceID)
+	if err != nil {
+		return err
+	}
+	source.LastNewItemAt = &fetch.FetchedAt
+	return c.sourceStorage.UpsertSource(ctx, source.ID, source)
+}
+
+// RecordFetchCycle records a scheduler pass over the sources and drops the history older than the retention
+func (c *Core) RecordFetchCycle(ctx context.Context, cycle *model.FetchCycle) error {
+	if err := c.fetchHistoryStorage.AddFetchCycle(ctx, cycle); err != nil {
+		return err
+	}
+
+	deleted, err := c.fetchHistoryStorage.DeleteFetchHistory(ctx, FetchHistorySince())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Debugf("deleted %d fetch history records", deleted)
+	}
+	return nil
+}
+
+// FetchHistorySince gets the start of the kept fetch history
+func FetchHistorySince() time.Time {
+	return time.Now().AddDate(0, 0, -config.FetchHistoryDays)
+}
+
+// GetSourceFetchStats aggregates the kept fetch history per source, nil sourceIDs for all sources
+func (c *Core) GetSourceFetchStats(ctx context.Context, sourceIDs []uint) ([]*storage.SourceFetchStats, error) {
+	return c.fetchHistoryStorage.GetSourceFetchStats(ctx, sourceIDs, FetchHistorySince())
+}
+
+// GetFetchCycles gets the latest scheduler passes, newest first
+func (c *Core) GetFetchCycles(ctx context.Context, count int) ([]*model.FetchCycle, error) {
+	return c.fetchHistoryStorage.GetFetchCycles(ctx, count)
+}
+
+// CountSourceSubscriptions gets the subscriber count of a source
+func (c *Core) CountSourceSubscriptions(ctx context.Context, sourceID uint) (int64, error) {
+	return c.subscriptionStorage.CountSourceSubscriptions(ctx, sourceID)
+}
+
+// GetChatSubscriptionCounts gets the chats with subscriptions and their subscription counts
+func (c *Core) GetChatSubscriptionCounts(ctx context.Context) ([]*storage.ChatSubscriptionCount, error) {
+	return c.subscriptionStorage.GetChatSubscriptionCounts(ctx)
+}
+
 func (c *Core) ToggleSubscriptionNotice(ctx context.Context, userID int64, sourceID uint) error {
 	subscription, err := c.GetSubscription(ctx, userID, sourceID)
 	if err != nil {
@@ -452,6 +956,116 @@ func (c *Core) ToggleSubscriptionTelegraph(ctx context.Context, userID int64, so
 	return c.subscriptionStorage.UpsertSubscription(ctx, userID, sourceID, subscription)
 }
 
+func (c *Core) ToggleSubscriptionEditUpdate(ctx context.Context, userID int64, sourceID uint) error {
+	subscription, err := c.GetSubscription(ctx, userID, sourceID)
+	if err != nil {
+		return err
+	}
+	if subscription.EnableEditUpdate == 1 {
+		subscription.EnableEditUpdate = 0
+	} else {
+		subscription.EnableEditUpdate = 1
+	}
+	return c.subscriptionStorage.UpsertSubscription(ctx, userID, sourceID, subscription)
+}
+
+// GetChatPreference gets the preferences of a chat, defaults are returned for chats without any
+func (c *Core) GetChatPreference(ctx context.Context, chatID int64) (*model.ChatPreference, error) {
+	preference, err := c.preferenceStorage.GetPreference(ctx, chatID)
+	if err != nil {
+		if err == storage.ErrRecordNotFound {
+			return &model.ChatPreference{ChatID: chatID}, nil
+		}
+		return nil, err
+	}
+	return preference, nil
+}
+
+func (c *Core) ToggleChatActionButton(ctx context.Context, chatID int64) (*model.ChatPreference, error) {
+	preference, err := c.GetChatPreference(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if preference.EnableActionButton == 1 {
+		preference.EnableActionButton = 0
+	} else {
+		preference.EnableActionButton = 1
+	}
+	if err := c.preferenceStorage.UpsertPreference(ctx, preference); err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+// SaveBookmark saves a content for a user to read later, the content is copied so it survives retention
+func (c *Core) SaveBookmark(ctx context.Context, userID int64, chatID int64, hashID string) (*model.Bookmark, error) {
+	exist, err := c.bookmarkStorage.BookmarkExist(ctx, userID, hashID)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		return nil, ErrBookmarkExist
+	}
+
+	content, err := c.contentStorage.GetContentByHashID(ctx, hashID)
+	if err != nil {
+		if err == storage.ErrRecordNotFound {
+			return nil, ErrContentNotExist
+		}
+		return nil, err
+	}
+
+	bookmark := &model.Bookmark{
+		UserID:       userID,
+		ChatID:       chatID,
+		SourceID:     content.SourceID,
+		HashID:       content.HashID,
+		Title:        content.Title,
+		RawLink:      content.RawLink,
+		TelegraphURL: content.TelegraphURL,
+	}
+	if source, err := c.sourceStorage.GetSource(ctx, content.SourceID); err == nil {
+		bookmark.SourceTitle = source.Title
+	}
+	if err := c.bookmarkStorage.AddBookmark(ctx, bookmark); err != nil {
+		return nil, err
+	}
+	return bookmark, nil
+}
+
+// GetUserBookmarks gets a page of bookmarks of a user, count -1 retrieves all
+func (c *Core) GetUserBookmarks(
+	ctx context.Context, userID int64, offset int, count int,
+) (*storage.GetBookmarksResult, error) {
+	opts := &storage.GetBookmarksOptions{Count: count, Offset: offset}
+	return c.bookmarkStorage.GetBookmarksByUserID(ctx, userID, opts)
+}
+
+func (c *Core) CountUserBookmarks(ctx context.Context, userID int64) (int64, error) {
+	return c.bookmarkStorage.CountUserBookmarks(ctx, userID)
+}
+
+func (c *Core) RemoveBookmark(ctx context.Context, userID int64, id uint) error {
+	count, err := c.bookmarkStorage.DeleteBookmark(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrBookmarkNotExist
+	}
+	return nil
+}
+
+// SetChatLanguage sets the reply language of a chat, empty follows the user language
+func (c *Core) SetChatLanguage(ctx context.Context, chatID int64, language string) error {
+	preference, err := c.GetChatPreference(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	preference.Language = language
+	return c.preferenceStorage.UpsertPreference(ctx, preference)
+}
+
 func (c *Core) GetSourceAllSubscriptions(
 	ctx context.Context, sourceID uint,
 ) ([]*model.Subscribe, error) {
@@ -474,3 +1088,323 @@ func (c *Core) ContentHashIDExist(
 	}
 	return result, nil
 }
+
+// IsUserAllowed checks if a user may use the bot, everyone may unless the allowlist is enabled
+func (c *Core) IsUserAllowed(ctx context.Context, userID int64) (bool, error) {
+	if !config.AllowlistEnabled || config.IsAdmin(userID) {
+		return true, nil
+	}
+
+	user, err := c.userStorage.GetUser(ctx, userID)
+	if err != nil {
+		if err == storage.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.Allowed, nil
+}
+
+// SetUserAllowed adds a user to the allowlist or removes it
+func (c *Core) SetUserAllowed(ctx context.Context, userID int64, allowed bool) error {
+	return c.userStorage.SetUserAllowed(ctx, userID, allowed)
+}
+
+// GetAllowedUsers gets the users on the allowlist
+func (c *Core) GetAllowedUsers(ctx context.Context) ([]*model.User, error) {
+	return c.userStorage.GetAllowedUsers(ctx)
+}
+
+// CreateInvite creates a one-time invite code
+func (c *Core) CreateInvite(ctx context.Context, createdBy int64) (*model.Invite, error) {
+	code := make([]byte, 8)
+	if _, err := rand.Read(code); err != nil {
+		return nil, err
+	}
+
+	invite := &model.Invite{Code: hex.EncodeToString(code), CreatedBy: createdBy}
+	if err := c.userStorage.AddInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// UseInvite allowlists a user with an invite code, the code can not be used again
+func (c *Core) UseInvite(ctx context.Context, code string, userID int64) error {
+	if err := c.userStorage.UseInvite(ctx, code, userID); err != nil {
+		if err == storage.ErrRecordNotFound {
+			return ErrInviteNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+// SaveUser creates a user or updates its profile
+func (c *Core) SaveUser(ctx context.Context, user *model.User) error {
+	return c.userStorage.UpsertUser(ctx, user)
+}
+
+// SaveChat creates a chat or updates its profile, a chat the bot had left is marked active again
+func (c *Core) SaveChat(ctx context.Context, chat *model.Chat) error {
+	chat.LeftAt = nil
+	return c.chatStorage.UpsertChat(ctx, chat)
+}
+
+// GetChat gets a chat
+func (c *Core) GetChat(ctx context.Context, chatID int64) (*model.Chat, error) {
+	return c.chatStorage.GetChat(ctx, chatID)
+}
+
+// GetUserChannels gets the channels a user last subscribed or added the bot to, the bot was not removed from
+func (c *Core) GetUserChannels(ctx context.Context, userID int64) ([]*model.Chat, error) {
+	return c.chatStorage.GetActiveChats(ctx, "channel", userID)
+}
+
+// ChatLeft records that the bot was blocked or removed from a chat and forgets the messages delivered to it
+func (c *Core) ChatLeft(ctx context.Context, chatID int64) error {
+	// the bot can no longer edit the messages it delivered to the chat
+	if _, err := c.messageStorage.DeleteChatMessages(ctx, chatID, 0); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err := c.chatStorage.SetChatLeftAt(ctx, chatID, &now)
+	if err == storage.ErrRecordNotFound {
+		// chats from before the chats table, nothing to record
+		return nil
+	}
+	return err
+}
+
+// MigrateChat moves the subscriptions, tags and preferences of a group to the supergroup it was upgraded to,
+// returns the number of subscriptions moved
+func (c *Core) MigrateChat(ctx context.Context, fromChatID int64, toChatID int64) (int64, error) {
+	moved, err := c.chatStorage.MoveChat(ctx, fromChatID, toChatID)
+	if err != nil {
+		return 0, err
+	}
+	return moved, c.ChatLeft(ctx, fromChatID)
+}
+
+// ChatRemoved cleans up after the bot was removed from a chat, returns the number of subscriptions removed
+func (c *Core) ChatRemoved(ctx context.Context, chatID int64) (int, error) {
+	removalID, err := newRemovalID()
+	if err != nil {
+		return 0, err
+	}
+	removed, err := c.subscriptionStorage.RemoveSubscriptions(ctx, chatID, 0, removalID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return int(removed), c.ChatLeft(ctx, chatID)
+}
+
+// RestoreSummary outcome of restoring a backup, or what a dry run would do
+type RestoreSummary struct {
+	Added   int      // subscriptions created
+	Updated int      // existing subscriptions overwritten with the backup settings
+	Removed int      // subscriptions not in the backup removed, only when replacing
+	Failed  []string // urls of the sources that could not be subscribed
+	// urls of the sources paused in the backup but left running, other chats subscribe to them as well
+	PauseSkipped []string
+}
+
+// Backup dumps the subscriptions and settings of a chat
+func (c *Core) Backup(ctx context.Context, chatID int64) (*backup.Backup, error) {
+	subscriptions, err := c.GetUserSubscriptions(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	b := backup.New(chatID)
+	for _, sub := range subscriptions {
+		source, err := c.GetSource(ctx, sub.SourceID)
+		if err != nil {
+			return nil, err
+		}
+		b.Subscriptions = append(
+			b.Subscriptions, backup.Subscription{
+				URL:                source.Link,
+				SourceTitle:        source.Title,
+				Title:              sub.Title,
+				Tags:               model.ParseTags(sub.Tag),
+				EnableNotification: sub.EnableNotification,
+				EnableTelegraph:    sub.EnableTelegraph,
+				EnableEditUpdate:   sub.EnableEditUpdate,
+				ThreadID:           sub.ThreadID,
+				Interval:           sub.Interval,
+				PreviewLength:      sub.PreviewLength,
+				WebPagePreview:     sub.WebPagePreview,
+				WaitTime:           sub.WaitTime,
+				Paused:             source.ErrorCount >= config.ErrorThreshold,
+			},
+		)
+	}
+
+	preference, err := c.GetChatPreference(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	b.Preference = &backup.Preference{
+		EnableActionButton: preference.EnableActionButton,
+		Language:           preference.Language,
+	}
+	return b, nil
+}
+
+// Restore applies a backup to a chat. Subscriptions in the backup are created or overwritten, replacing also removes
+// the subscriptions missing from the backup. A dry run only counts the changes
+func (c *Core) Restore(
+	ctx context.Context, chatID int64, b *backup.Backup, replace bool, dryRun bool,
+) (*RestoreSummary, error) {
+	subscriptions, err := c.GetUserSubscriptions(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*model.Subscribe)
+	for _, sub := range subscriptions {
+		source, err := c.GetSource(ctx, sub.SourceID)
+		if err != nil {
+			return nil, err
+		}
+		current[source.Link] = sub
+	}
+
+	summary := &RestoreSummary{}
+	inBackup := make(map[string]bool)
+	for _, entry := range b.Subscriptions {
+		inBackup[entry.URL] = true
+		_, exist := current[entry.URL]
+		if dryRun {
+			if exist {
+				summary.Updated++
+			} else {
+				summary.Added++
+			}
+			continue
+		}
+
+		pauseSkipped, err := c.restoreSubscription(ctx, chatID, entry)
+		if err != nil {
+			log.Errorf("restore subscription %d %s failed, %v", chatID, entry.URL, err)
+			summary.Failed = append(summary.Failed, entry.URL)
+			continue
+		}
+		if pauseSkipped {
+			summary.PauseSkipped = append(summary.PauseSkipped, entry.URL)
+		}
+		if exist {
+			summary.Updated++
+		} else {
+			summary.Added++
+		}
+	}
+
+	if replace {
+		for link, sub := range current {
+			if inBackup[link] {
+				continue
+			}
+			if !dryRun {
+				if _, err := c.Unsubscribe(ctx, chatID, sub.SourceID); err != nil {
+					return summary, err
+				}
+			}
+			summary.Removed++
+		}
+	}
+
+	if !dryRun && b.Preference != nil {
+		preference := &model.ChatPreference{
+			ChatID:             chatID,
+			EnableActionButton: b.Preference.EnableActionButton,
+			Language:           b.Preference.Language,
+		}
+		if err := c.preferenceStorage.UpsertPreference(ctx, preference); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// restoreSubscription subscribes a chat to the source of a backup entry if needed and applies its settings.
+// Pausing a source pauses it for every chat, it is only applied to the sources no other chat subscribes to,
+// returns true when the pause was skipped
+func (c *Core) restoreSubscription(ctx context.Context, chatID int64, entry backup.Subscription) (bool, error) {
+	source, err := c.CreateSource(ctx, entry.URL)
+	if err != nil {
+		return false, err
+	}
+	if err := c.AddSubscription(ctx, chatID, source.ID); err != nil && err != ErrSubscriptionExist {
+		return false, err
+	}
+
+	subscription, err := c.GetSubscription(ctx, chatID, source.ID)
+	if err != nil {
+		return false, err
+	}
+	applyBackupSettings(subscription, entry)
+	if err := c.subscriptionStorage.UpsertSubscription(ctx, chatID, source.ID, subscription); err != nil {
+		return false, err
+	}
+	if err := c.SetSubscriptionTag(ctx, chatID, source.ID, entry.Tags); err != nil {
+		return false, err
+	}
+
+	if !entry.Paused {
+		return false, nil
+	}
+	paused, err := c.DisableUnsharedSourceUpdate(ctx, source.ID)
+	if err != nil {
+		return false, err
+	}
+	return !paused, nil
+}
+
+// applyBackupSettings copies the settings of a backup entry to a subscription,
+// the backup is an uploaded file and the values out of range fall back to the defaults
+func applyBackupSettings(subscription *model.Subscribe, entry backup.Subscription) {
+	subscription.Title = strings.TrimSpace(entry.Title)
+	if len([]rune(subscription.Title)) > maxBackupTitleLength {
+		subscription.Title = string([]rune(subscription.Title)[:maxBackupTitleLength])
+	}
+	subscription.EnableNotification = backupSwitch(entry.EnableNotification)
+	subscription.EnableTelegraph = backupSwitch(entry.EnableTelegraph)
+	subscription.EnableEditUpdate = backupSwitch(entry.EnableEditUpdate)
+
+	subscription.ThreadID = entry.ThreadID
+	if subscription.ThreadID < 0 {
+		subscription.ThreadID = 0
+	}
+	subscription.Interval = entry.Interval
+	if subscription.Interval <= 0 {
+		subscription.Interval = config.UpdateInterval
+	}
+	subscription.WaitTime = entry.WaitTime
+	if subscription.WaitTime < 0 || subscription.WaitTime > subscription.Interval {
+		subscription.WaitTime = subscription.Interval
+	}
+
+	// -1 hides the preview text and 0 follows the config
+	subscription.PreviewLength = entry.PreviewLength
+	if subscription.PreviewLength < -1 {
+		subscription.PreviewLength = 0
+	}
+	if subscription.PreviewLength > maxBackupPreviewLength {
+		subscription.PreviewLength = maxBackupPreviewLength
+	}
+	// 0 follows the config, 1 shows and 2 hides the web page preview
+	subscription.WebPagePreview = entry.WebPagePreview
+	if subscription.WebPagePreview < 0 || subscription.WebPagePreview > 2 {
+		subscription.WebPagePreview = 0
+	}
+}
+
+// backupSwitch reads an on or off setting of a backup, anything else than 0 is on
+func backupSwitch(value int) int {
+	if value != 0 {
+		return 1
+	}
+	return 0
+}
@@ -3,11 +3,16 @@ package core
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/mmcdole/gofeed"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/andatoshiki/toshiki-rssbot/internal/backup"
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
 	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 	"github.com/andatoshiki/toshiki-rssbot/internal/storage"
 	"github.com/andatoshiki/toshiki-rssbot/internal/storage/mock"
@@ -18,6 +23,12 @@ type mockStorage struct {
 	Content      *mock.MockContent
 	Source       *mock.MockSource
 	Subscription *mock.MockSubscription
+	Message      *mock.MockMessage
+	Preference   *mock.MockPreference
+	Bookmark     *mock.MockBookmark
+	Tag          *mock.MockTag
+	FetchHistory *mock.MockFetchHistory
+	Chat         *mock.MockChat
 	Ctrl         *gomock.Controller
 }
 
@@ -29,9 +40,18 @@ func getTestCore(t *testing.T) (*Core, *mockStorage) {
 		User:         mock.NewMockUser(ctrl),
 		Content:      mock.NewMockContent(ctrl),
 		Source:       mock.NewMockSource(ctrl),
+		Message:      mock.NewMockMessage(ctrl),
+		Preference:   mock.NewMockPreference(ctrl),
+		Bookmark:     mock.NewMockBookmark(ctrl),
+		Tag:          mock.NewMockTag(ctrl),
+		FetchHistory: mock.NewMockFetchHistory(ctrl),
+		Chat:         mock.NewMockChat(ctrl),
 		Ctrl:         ctrl,
 	}
-	c := NewCore(s.User, s.Content, s.Source, s.Subscription, nil, nil)
+	c := NewCore(
+		s.User, s.Content, s.Source, s.Subscription, s.Message, s.Preference, s.Bookmark, s.Tag, s.FetchHistory,
+		s.Chat, nil, nil,
+	)
 	return c, s
 }
 
@@ -150,81 +170,283 @@ func TestCore_Unsubscribe(t *testing.T) {
 	sourceID1 := uint(101)
 
 	t.Run(
-		"SubscriptionExist err", func(t *testing.T) {
-			s.Subscription.EXPECT().SubscriptionExist(ctx, userID, sourceID1).Return(
-				false, errors.New("err"),
+		"remove failed", func(t *testing.T) {
+			s.Subscription.EXPECT().RemoveSubscriptions(ctx, userID, sourceID1, gomock.Any(), gomock.Any()).Return(
+				int64(0), errors.New("err"),
 			).Times(1)
-			err := c.Unsubscribe(ctx, userID, sourceID1)
+			_, err := c.Unsubscribe(ctx, userID, sourceID1)
 			assert.Error(t, err)
 		},
 	)
 
 	t.Run(
 		"subscription not exist", func(t *testing.T) {
-			s.Subscription.EXPECT().SubscriptionExist(ctx, userID, sourceID1).Return(
-				false, nil,
+			s.Subscription.EXPECT().RemoveSubscriptions(ctx, userID, sourceID1, gomock.Any(), gomock.Any()).Return(
+				int64(0), nil,
 			).Times(1)
-			err := c.Unsubscribe(ctx, userID, sourceID1)
+			_, err := c.Unsubscribe(ctx, userID, sourceID1)
 			assert.Equal(t, ErrSubscriptionNotExist, err)
 		},
 	)
 
-	s.Subscription.EXPECT().SubscriptionExist(ctx, gomock.Any(), gomock.Any()).Return(
-		true, nil,
-	).AnyTimes()
+	t.Run(
+		"unsubscribe and undo", func(t *testing.T) {
+			var removalID uint32
+			s.Subscription.EXPECT().RemoveSubscriptions(ctx, userID, sourceID1, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ int64, _ uint, id uint32, _ time.Time) (int64, error) {
+					removalID = id
+					return 1, nil
+				},
+			).Times(1)
+			got, err := c.Unsubscribe(ctx, userID, sourceID1)
+			assert.Nil(t, err)
+			assert.NotZero(t, got)
+			assert.Equal(t, removalID, got)
+
+			// the undo period is checked even if the purge did not run
+			s.Subscription.EXPECT().RestoreSubscriptions(ctx, userID, removalID, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ int64, _ uint32, removedAfter time.Time) (int64, error) {
+					undoStart := time.Now().Add(-time.Duration(config.UndoPeriod) * time.Minute)
+					assert.WithinDuration(t, undoStart, removedAfter, time.Second)
+					return 1, nil
+				},
+			).Times(1)
+			restored, err := c.UndoUnsubscribe(ctx, userID, removalID)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), restored)
+		},
+	)
 
 	t.Run(
-		"unsubscribe failed", func(t *testing.T) {
-			s.Subscription.EXPECT().DeleteSubscription(ctx, userID, sourceID1).Return(
-				int64(1), errors.New("err"),
+		"unsubscribe tag as one removal", func(t *testing.T) {
+			s.Subscription.EXPECT().GetSubscriptionsByUserID(ctx, userID, gomock.Any()).Return(
+				&storage.GetSubscriptionsResult{
+					Subscriptions: []*model.Subscribe{{SourceID: 1}, {SourceID: 2}},
+				}, nil,
 			).Times(1)
-			err := c.Unsubscribe(ctx, userID, sourceID1)
-			assert.Error(t, err)
+			removalIDs := make(map[uint32]bool)
+			s.Subscription.EXPECT().RemoveSubscriptions(ctx, userID, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ int64, _ uint, id uint32, _ time.Time) (int64, error) {
+					removalIDs[id] = true
+					return 1, nil
+				},
+			).Times(2)
+			count, removalID, err := c.UnsubscribeTag(ctx, userID, "#news")
+			assert.Nil(t, err)
+			assert.Equal(t, 2, count)
+			assert.Equal(t, map[uint32]bool{removalID: true}, removalIDs)
+		},
+	)
+}
+
+func TestCore_PurgeRemovedSubscriptions(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	sourceID1 := uint(101)
+	subscription := &model.Subscribe{ID: 1, UserID: 1, SourceID: sourceID1}
+
+	t.Run(
+		"get removed subscriptions failed", func(t *testing.T) {
+			s.Subscription.EXPECT().GetRemovedSubscriptions(ctx, gomock.Any()).Return(nil, errors.New("err")).Times(1)
+			assert.Error(t, c.PurgeRemovedSubscriptions(ctx))
 		},
 	)
 
-	s.Subscription.EXPECT().DeleteSubscription(ctx, gomock.Any(), gomock.Any()).Return(
-		int64(1), nil,
+	s.Subscription.EXPECT().GetRemovedSubscriptions(ctx, gomock.Any()).Return(
+		[]*model.Subscribe{subscription}, nil,
 	).AnyTimes()
 
 	t.Run(
-		"count subs", func(t *testing.T) {
-			s.Subscription.EXPECT().CountSourceSubscriptions(ctx, sourceID1).Return(
-				int64(1), errors.New("err"),
-			).Times(1)
-			err := c.Unsubscribe(ctx, userID, sourceID1)
-			assert.Error(t, err)
+		"delete tags failed", func(t *testing.T) {
+			s.Tag.EXPECT().DeleteSubscriptionTags(ctx, subscription).Return(errors.New("err")).Times(1)
+			assert.Error(t, c.PurgeRemovedSubscriptions(ctx))
+		},
+	)
+
+	s.Tag.EXPECT().DeleteSubscriptionTags(ctx, gomock.Any()).Return(nil).AnyTimes()
 
-			s.Subscription.EXPECT().CountSourceSubscriptions(ctx, sourceID1).Return(
-				int64(1), nil,
+	t.Run(
+		"subscription exist failed", func(t *testing.T) {
+			s.Subscription.EXPECT().SubscriptionExist(ctx, int64(1), sourceID1).Return(false, errors.New("err")).Times(1)
+			assert.Error(t, c.PurgeRemovedSubscriptions(ctx))
+		},
+	)
+
+	s.Subscription.EXPECT().SubscriptionExist(ctx, int64(1), sourceID1).Return(false, nil).AnyTimes()
+
+	t.Run(
+		"delete messages failed", func(t *testing.T) {
+			s.Message.EXPECT().DeleteChatMessages(ctx, int64(1), sourceID1).Return(int64(0), errors.New("err")).Times(1)
+			assert.Error(t, c.PurgeRemovedSubscriptions(ctx))
+		},
+	)
+
+	s.Message.EXPECT().DeleteChatMessages(ctx, int64(1), sourceID1).Return(int64(2), nil).AnyTimes()
+
+	t.Run(
+		"purge failed", func(t *testing.T) {
+			s.Subscription.EXPECT().PurgeSubscriptions(ctx, []uint{1}).Return(int64(0), errors.New("err")).Times(1)
+			assert.Error(t, c.PurgeRemovedSubscriptions(ctx))
+		},
+	)
+
+	s.Subscription.EXPECT().PurgeSubscriptions(ctx, gomock.Any()).Return(int64(1), nil).AnyTimes()
+
+	t.Run(
+		"orphan sources failed", func(t *testing.T) {
+			s.Source.EXPECT().GetOrphanSources(ctx, gomock.Any()).Return(nil, errors.New("err")).Times(1)
+			assert.Error(t, c.PurgeRemovedSubscriptions(ctx))
+		},
+	)
+
+	t.Run(
+		"remove orphan sources", func(t *testing.T) {
+			sourceID2 := uint(102)
+			s.Source.EXPECT().GetOrphanSources(ctx, gomock.Any()).Return(
+				[]*model.Source{{ID: sourceID1}, {ID: sourceID2}}, nil,
 			).Times(1)
-			err = c.Unsubscribe(ctx, userID, sourceID1)
-			assert.Nil(t, err)
+			// a failing source does not stop the others from being removed
+			s.Source.EXPECT().Delete(ctx, sourceID1).Return(errors.New("err")).Times(1)
+			s.Source.EXPECT().Delete(ctx, sourceID2).Return(nil).Times(1)
+			s.Content.EXPECT().DeleteSourceContents(ctx, sourceID2).Return(int64(1), nil).Times(1)
+			s.Message.EXPECT().DeleteSourceMessages(ctx, sourceID2).Return(int64(1), nil).Times(1)
+			assert.Nil(t, c.PurgeRemovedSubscriptions(ctx))
 		},
 	)
+}
+
+func TestCore_PurgeResubscribedSubscription(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	userID := int64(1)
+	sourceID := uint(101)
+	removed := &model.Subscribe{ID: 1, UserID: userID, SourceID: sourceID}
 
-	s.Subscription.EXPECT().CountSourceSubscriptions(ctx, gomock.Any()).Return(
-		int64(0), nil,
+	live := true
+	s.Subscription.EXPECT().SubscriptionExist(ctx, userID, sourceID).DoAndReturn(
+		func(context.Context, int64, uint) (bool, error) {
+			return live, nil
+		},
 	).AnyTimes()
+	s.Subscription.EXPECT().RemoveSubscriptions(ctx, userID, sourceID, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, int64, uint, uint32, time.Time) (int64, error) {
+			live = false
+			return 1, nil
+		},
+	).Times(1)
+	s.Subscription.EXPECT().AddSubscription(ctx, gomock.Any()).DoAndReturn(
+		func(context.Context, *model.Subscribe) error {
+			live = true
+			return nil
+		},
+	).Times(1)
+
+	_, err := c.Unsubscribe(ctx, userID, sourceID)
+	assert.Nil(t, err)
+	assert.Nil(t, c.AddSubscription(ctx, userID, sourceID))
+
+	// the messages delivered since subscribing again are kept for edit-on-update
+	s.Subscription.EXPECT().GetRemovedSubscriptions(ctx, gomock.Any()).Return([]*model.Subscribe{removed}, nil).Times(1)
+	s.Tag.EXPECT().DeleteSubscriptionTags(ctx, removed).Return(nil).Times(1)
+	s.Message.EXPECT().DeleteChatMessages(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	s.Subscription.EXPECT().PurgeSubscriptions(ctx, []uint{1}).Return(int64(1), nil).Times(1)
+	s.Source.EXPECT().GetOrphanSources(ctx, gomock.Any()).Return(nil, nil).Times(1)
+	assert.Nil(t, c.PurgeRemovedSubscriptions(ctx))
+}
+
+func TestCore_SetSubscriptionThread(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	chatID := int64(-100)
+	sourceID := uint(101)
 
 	t.Run(
-		"remove source", func(t *testing.T) {
-			s.Source.EXPECT().Delete(ctx, sourceID1).Return(
-				errors.New("err"),
-			).Times(1)
+		"subscription not exist", func(t *testing.T) {
+			s.Subscription.EXPECT().GetSubscription(ctx, chatID, sourceID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			err := c.SetSubscriptionThread(ctx, chatID, sourceID, 3)
+			assert.Equal(t, ErrSubscriptionNotExist, err)
+		},
+	)
 
-			err := c.Unsubscribe(ctx, userID, sourceID1)
-			assert.Error(t, err)
+	t.Run(
+		"set thread", func(t *testing.T) {
+			s.Subscription.EXPECT().GetSubscription(ctx, chatID, sourceID).Return(
+				&model.Subscribe{UserID: chatID, SourceID: sourceID}, nil,
+			).Times(1)
+			s.Subscription.EXPECT().UpsertSubscription(
+				ctx, chatID, sourceID, &model.Subscribe{UserID: chatID, SourceID: sourceID, ThreadID: 3},
+			).Return(nil).Times(1)
+			err := c.SetSubscriptionThread(ctx, chatID, sourceID, 3)
+			assert.Nil(t, err)
+		},
+	)
+}
 
-			s.Source.EXPECT().Delete(ctx, sourceID1).Return(nil).AnyTimes()
+func TestCore_AutoCreateTopics(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
 
-			s.Content.EXPECT().DeleteSourceContents(ctx, sourceID1).Return(int64(0), errors.New("err")).Times(1)
-			err = c.Unsubscribe(ctx, userID, sourceID1)
-			assert.Error(t, err)
+	chatID := int64(-100)
+	subscriptions := []*model.Subscribe{
+		{UserID: chatID, SourceID: 1},
+		{UserID: chatID, SourceID: 2, ThreadID: 7},
+		{UserID: chatID, SourceID: 3},
+	}
+	s.Subscription.EXPECT().GetSubscriptionsByUserID(ctx, chatID, gomock.Any()).Return(
+		&storage.GetSubscriptionsResult{Subscriptions: subscriptions}, nil,
+	).AnyTimes()
+	s.Source.EXPECT().GetSource(ctx, uint(1)).Return(&model.Source{ID: 1, Title: "one"}, nil).AnyTimes()
+	s.Source.EXPECT().GetSource(ctx, uint(3)).Return(&model.Source{ID: 3, Title: "three"}, nil).AnyTimes()
+	s.Subscription.EXPECT().GetSubscription(ctx, chatID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, userID int64, sourceID uint) (*model.Subscribe, error) {
+			return &model.Subscribe{UserID: userID, SourceID: sourceID}, nil
+		},
+	).AnyTimes()
 
-			s.Content.EXPECT().DeleteSourceContents(ctx, sourceID1).Return(int64(1), nil).Times(1)
-			err = c.Unsubscribe(ctx, userID, sourceID1)
+	t.Run(
+		"topics created for the general topic subscriptions", func(t *testing.T) {
+			s.Subscription.EXPECT().UpsertSubscription(
+				ctx, chatID, uint(1), &model.Subscribe{UserID: chatID, SourceID: 1, ThreadID: 11},
+			).Return(nil).Times(1)
+			s.Subscription.EXPECT().UpsertSubscription(
+				ctx, chatID, uint(3), &model.Subscribe{UserID: chatID, SourceID: 3, ThreadID: 13},
+			).Return(nil).Times(1)
+
+			var names []string
+			created, err := c.AutoCreateTopics(
+				ctx, chatID, func(name string) (int, error) {
+					names = append(names, name)
+					return 10 + len(names)*2 - 1, nil
+				},
+			)
 			assert.Nil(t, err)
+			assert.Equal(t, 2, created)
+			assert.Equal(t, []string{"one", "three"}, names)
+		},
+	)
+
+	t.Run(
+		"create topic failed", func(t *testing.T) {
+			s.Subscription.EXPECT().UpsertSubscription(ctx, chatID, uint(1), gomock.Any()).Return(nil).Times(1)
+			calls := 0
+			created, err := c.AutoCreateTopics(
+				ctx, chatID, func(name string) (int, error) {
+					calls++
+					if calls == 2 {
+						return 0, errors.New("err")
+					}
+					return 11, nil
+				},
+			)
+			assert.Error(t, err)
+			assert.Equal(t, 1, created)
 		},
 	)
 }
@@ -350,6 +572,137 @@ func TestCore_GetSubscription(t *testing.T) {
 	)
 }
 
+func TestCore_SetSubscriptionTag(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	userID := int64(101)
+	sourceID := uint(1)
+
+	t.Run(
+		"set tags failed", func(t *testing.T) {
+			subscription := &model.Subscribe{ID: 1, UserID: userID, SourceID: sourceID}
+			s.Subscription.EXPECT().GetSubscription(ctx, userID, sourceID).Return(subscription, nil).Times(1)
+			s.Tag.EXPECT().SetSubscriptionTags(ctx, subscription, []string{"go"}).Return(errors.New("err")).Times(1)
+			err := c.SetSubscriptionTag(ctx, userID, sourceID, []string{"go"})
+			assert.Error(t, err)
+		},
+	)
+
+	t.Run(
+		"normalized", func(t *testing.T) {
+			subscription := &model.Subscribe{ID: 1, UserID: userID, SourceID: sourceID}
+			s.Subscription.EXPECT().GetSubscription(ctx, userID, sourceID).Return(subscription, nil).Times(1)
+			s.Tag.EXPECT().SetSubscriptionTags(ctx, subscription, []string{"go", "news"}).Return(nil).Times(1)
+			s.Subscription.EXPECT().UpsertSubscription(ctx, userID, sourceID, subscription).Return(nil).Times(1)
+			err := c.SetSubscriptionTag(ctx, userID, sourceID, []string{"#Go", "go", "news", ""})
+			assert.Nil(t, err)
+			assert.Equal(t, "#go #news", subscription.Tag)
+		},
+	)
+}
+
+func TestCore_RenameTag(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	userID := int64(101)
+
+	t.Run(
+		"tag not exist", func(t *testing.T) {
+			s.Tag.EXPECT().GetTag(ctx, userID, "go").Return(nil, storage.ErrRecordNotFound).Times(1)
+			_, err := c.RenameTag(ctx, userID, "#go", "#golang")
+			assert.Equal(t, ErrTagNotExist, err)
+		},
+	)
+
+	t.Run(
+		"target exist", func(t *testing.T) {
+			s.Tag.EXPECT().GetTag(ctx, userID, "go").Return(&model.Tag{ID: 1}, nil).Times(1)
+			s.Tag.EXPECT().GetTag(ctx, userID, "golang").Return(&model.Tag{ID: 2}, nil).Times(1)
+			_, err := c.RenameTag(ctx, userID, "#go", "#golang")
+			assert.Equal(t, ErrTagExist, err)
+		},
+	)
+
+	t.Run(
+		"renamed", func(t *testing.T) {
+			s.Tag.EXPECT().GetTag(ctx, userID, "go").Return(&model.Tag{ID: 1}, nil).Times(1)
+			s.Tag.EXPECT().GetTag(ctx, userID, "golang").Return(nil, storage.ErrRecordNotFound).Times(1)
+			s.Tag.EXPECT().MoveTag(ctx, userID, "go", "golang").Return(int64(3), nil).Times(1)
+			count, err := c.RenameTag(ctx, userID, "#Go", "#golang")
+			assert.Nil(t, err)
+			assert.Equal(t, int64(3), count)
+		},
+	)
+}
+
+func TestCore_MergeTag(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	userID := int64(101)
+
+	t.Run(
+		"target not exist", func(t *testing.T) {
+			s.Tag.EXPECT().GetTag(ctx, userID, "go").Return(&model.Tag{ID: 1}, nil).Times(1)
+			s.Tag.EXPECT().GetTag(ctx, userID, "golang").Return(nil, storage.ErrRecordNotFound).Times(1)
+			_, err := c.MergeTag(ctx, userID, "#go", "#golang")
+			assert.Equal(t, ErrTagNotExist, err)
+		},
+	)
+
+	t.Run(
+		"merged", func(t *testing.T) {
+			s.Tag.EXPECT().GetTag(ctx, userID, "go").Return(&model.Tag{ID: 1}, nil).Times(1)
+			s.Tag.EXPECT().GetTag(ctx, userID, "golang").Return(&model.Tag{ID: 2}, nil).Times(1)
+			s.Tag.EXPECT().MoveTag(ctx, userID, "go", "golang").Return(int64(2), nil).Times(1)
+			count, err := c.MergeTag(ctx, userID, "#go", "#golang")
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), count)
+		},
+	)
+}
+
+func TestCore_RecordSourceFetch(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	sourceID := uint(1)
+
+	t.Run(
+		"no new items", func(t *testing.T) {
+			fetch := &model.SourceFetch{SourceID: sourceID, FetchedAt: time.Now(), StatusCode: 304}
+			s.FetchHistory.EXPECT().AddSourceFetch(ctx, fetch).Return(nil).Times(1)
+			err := c.RecordSourceFetch(ctx, fetch)
+			assert.Nil(t, err)
+		},
+	)
+
+	t.Run(
+		"new items", func(t *testing.T) {
+			fetch := &model.SourceFetch{SourceID: sourceID, FetchedAt: time.Now(), StatusCode: 200, NewItems: 2}
+			source := &model.Source{ID: sourceID}
+			s.FetchHistory.EXPECT().AddSourceFetch(ctx, fetch).Return(nil).Times(1)
+			s.Source.EXPECT().GetSource(ctx, sourceID).Return(source, nil).Times(1)
+			s.Source.EXPECT().UpsertSource(ctx, sourceID, source).Return(nil).Times(1)
+			err := c.RecordSourceFetch(ctx, fetch)
+			assert.Nil(t, err)
+			assert.Equal(t, fetch.FetchedAt, *source.LastNewItemAt)
+		},
+	)
+
+	t.Run(
+		"long error truncated", func(t *testing.T) {
+			fetch := &model.SourceFetch{SourceID: sourceID, FetchedAt: time.Now(), Error: strings.Repeat("错", 300)}
+			s.FetchHistory.EXPECT().AddSourceFetch(ctx, fetch).Return(nil).Times(1)
+			err := c.RecordSourceFetch(ctx, fetch)
+			assert.Nil(t, err)
+			assert.Equal(t, strings.Repeat("错", maxSourceErrorLength), fetch.Error)
+		},
+	)
+}
+
 func TestCore_DisableSourceUpdate(t *testing.T) {
 	c, s := getTestCore(t)
 	defer s.Ctrl.Finish()
@@ -440,6 +793,114 @@ func TestCore_ClearSourceErrorCount(t *testing.T) {
 	)
 }
 
+func TestCore_SourceFetchSucceeded(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	sourceID := uint(1)
+
+	t.Run(
+		"get source err", func(t *testing.T) {
+			s.Source.EXPECT().GetSource(ctx, sourceID).Return(nil, errors.New("err")).Times(1)
+			err := c.SourceFetchSucceeded(ctx, sourceID)
+			assert.Error(t, err)
+		},
+	)
+
+	t.Run(
+		"error cleared", func(t *testing.T) {
+			source := &model.Source{ID: sourceID, ErrorCount: 3, LastError: "timeout"}
+			s.Source.EXPECT().GetSource(ctx, sourceID).Return(source, nil).Times(1)
+			s.Source.EXPECT().UpsertSource(ctx, sourceID, source).Return(nil).Times(1)
+			err := c.SourceFetchSucceeded(ctx, sourceID)
+			assert.Nil(t, err)
+			assert.Equal(t, uint(0), source.ErrorCount)
+			assert.Equal(t, "", source.LastError)
+			assert.NotNil(t, source.LastFetchedAt)
+		},
+	)
+}
+
+func TestCore_SourceFetchFailed(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	sourceID := uint(1)
+
+	t.Run(
+		"get source err", func(t *testing.T) {
+			s.Source.EXPECT().GetSource(ctx, sourceID).Return(nil, errors.New("err")).Times(1)
+			err := c.SourceFetchFailed(ctx, sourceID, errors.New("timeout"))
+			assert.Error(t, err)
+		},
+	)
+
+	t.Run(
+		"error recorded", func(t *testing.T) {
+			source := &model.Source{ID: sourceID, ErrorCount: 1}
+			s.Source.EXPECT().GetSource(ctx, sourceID).Return(source, nil).Times(1)
+			s.Source.EXPECT().UpsertSource(ctx, sourceID, source).Return(nil).Times(1)
+			err := c.SourceFetchFailed(ctx, sourceID, errors.New("timeout"))
+			assert.Nil(t, err)
+			assert.Equal(t, uint(2), source.ErrorCount)
+			assert.Equal(t, "timeout", source.LastError)
+			assert.NotNil(t, source.LastFetchedAt)
+		},
+	)
+
+	t.Run(
+		"long error truncated", func(t *testing.T) {
+			source := &model.Source{ID: sourceID}
+			s.Source.EXPECT().GetSource(ctx, sourceID).Return(source, nil).Times(1)
+			s.Source.EXPECT().UpsertSource(ctx, sourceID, source).Return(nil).Times(1)
+			err := c.SourceFetchFailed(ctx, sourceID, errors.New(strings.Repeat("错", 300)))
+			assert.Nil(t, err)
+			assert.Equal(t, strings.Repeat("错", maxSourceErrorLength), source.LastError)
+		},
+	)
+}
+
+func TestCore_SetSubscriptionPreview(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	userID := int64(-100)
+	sourceID := uint(1)
+
+	t.Run(
+		"subscription not exist", func(t *testing.T) {
+			s.Subscription.EXPECT().GetSubscription(ctx, userID, sourceID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			err := c.SetSubscriptionPreview(ctx, userID, sourceID, 100, 2)
+			assert.Equal(t, ErrSubscriptionNotExist, err)
+		},
+	)
+
+	t.Run(
+		"set preview", func(t *testing.T) {
+			s.Subscription.EXPECT().GetSubscription(ctx, userID, sourceID).Return(
+				&model.Subscribe{UserID: userID, SourceID: sourceID, PreviewLength: 500}, nil,
+			).Times(1)
+			s.Subscription.EXPECT().UpsertSubscription(
+				ctx, userID, sourceID,
+				&model.Subscribe{UserID: userID, SourceID: sourceID, PreviewLength: -1, WebPagePreview: 2},
+			).Return(nil).Times(1)
+			err := c.SetSubscriptionPreview(ctx, userID, sourceID, -1, 2)
+			assert.Nil(t, err)
+		},
+	)
+}
+
+func TestCore_CountSourceSubscriptions(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	s.Subscription.EXPECT().CountSourceSubscriptions(ctx, uint(1)).Return(int64(3), nil).Times(1)
+	count, err := c.CountSourceSubscriptions(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
 func TestCore_ToggleSubscriptionNotice(t *testing.T) {
 	c, s := getTestCore(t)
 	defer s.Ctrl.Finish()
@@ -579,4 +1040,469 @@ func TestCore_ToggleSubscriptionTelegraph(t *testing.T) {
 			assert.Nil(t, err)
 		},
 	)
-}
\ No newline at end of file
+}
+
+func TestCore_UpdateSourceContents(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	source := &model.Source{ID: 1, Link: "http://google.com"}
+	item := &gofeed.Item{GUID: "guid", Title: "title", Content: "content"}
+	hashID := model.GenHashID(source.Link, item.GUID)
+
+	t.Run(
+		"content not exist", func(t *testing.T) {
+			s.Content.EXPECT().GetContentByHashID(ctx, hashID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			got, err := c.UpdateSourceContents(ctx, source, []*gofeed.Item{item})
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(got))
+		},
+	)
+
+	t.Run(
+		"content unchanged", func(t *testing.T) {
+			s.Content.EXPECT().GetContentByHashID(ctx, hashID).Return(
+				&model.Content{HashID: hashID, Fingerprint: model.GenFingerprint(item.Title, item.Content)}, nil,
+			).Times(1)
+			got, err := c.UpdateSourceContents(ctx, source, []*gofeed.Item{item})
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(got))
+		},
+	)
+
+	t.Run(
+		"fingerprint backfill", func(t *testing.T) {
+			s.Content.EXPECT().GetContentByHashID(ctx, hashID).Return(&model.Content{HashID: hashID}, nil).Times(1)
+			s.Content.EXPECT().UpdateContent(ctx, gomock.Any()).Return(nil).Times(1)
+			got, err := c.UpdateSourceContents(ctx, source, []*gofeed.Item{item})
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(got))
+		},
+	)
+
+	t.Run(
+		"content changed", func(t *testing.T) {
+			s.Content.EXPECT().GetContentByHashID(ctx, hashID).Return(
+				&model.Content{HashID: hashID, Title: "titel", Fingerprint: "old"}, nil,
+			).Times(1)
+			s.Content.EXPECT().UpdateContent(ctx, gomock.Any()).Return(nil).Times(1)
+			got, err := c.UpdateSourceContents(ctx, source, []*gofeed.Item{item})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(got))
+			assert.Equal(t, item.Title, got[0].Title)
+		},
+	)
+}
+
+func TestCore_GetChatPreference(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	chatID := int64(-100123)
+
+	t.Run(
+		"preference err", func(t *testing.T) {
+			s.Preference.EXPECT().GetPreference(ctx, chatID).Return(nil, errors.New("err")).Times(1)
+			got, err := c.GetChatPreference(ctx, chatID)
+			assert.Error(t, err)
+			assert.Nil(t, got)
+		},
+	)
+
+	t.Run(
+		"default preference", func(t *testing.T) {
+			s.Preference.EXPECT().GetPreference(ctx, chatID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			got, err := c.GetChatPreference(ctx, chatID)
+			assert.Nil(t, err)
+			assert.Equal(t, chatID, got.ChatID)
+			assert.Equal(t, 0, got.EnableActionButton)
+		},
+	)
+
+	t.Run(
+		"toggle action button", func(t *testing.T) {
+			s.Preference.EXPECT().GetPreference(ctx, chatID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			s.Preference.EXPECT().UpsertPreference(ctx, gomock.Any()).Return(nil).Times(1)
+			got, err := c.ToggleChatActionButton(ctx, chatID)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, got.EnableActionButton)
+		},
+	)
+}
+
+func TestCore_SaveBookmark(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	userID := int64(1)
+	chatID := int64(-100123)
+	content := &model.Content{SourceID: 101, HashID: "hash", Title: "title", RawLink: "https://example.com/1"}
+
+	t.Run(
+		"bookmark exist", func(t *testing.T) {
+			s.Bookmark.EXPECT().BookmarkExist(ctx, userID, content.HashID).Return(true, nil).Times(1)
+			got, err := c.SaveBookmark(ctx, userID, chatID, content.HashID)
+			assert.Equal(t, ErrBookmarkExist, err)
+			assert.Nil(t, got)
+		},
+	)
+
+	t.Run(
+		"content not exist", func(t *testing.T) {
+			s.Bookmark.EXPECT().BookmarkExist(ctx, userID, content.HashID).Return(false, nil).Times(1)
+			s.Content.EXPECT().GetContentByHashID(ctx, content.HashID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			got, err := c.SaveBookmark(ctx, userID, chatID, content.HashID)
+			assert.Equal(t, ErrContentNotExist, err)
+			assert.Nil(t, got)
+		},
+	)
+
+	t.Run(
+		"ok", func(t *testing.T) {
+			s.Bookmark.EXPECT().BookmarkExist(ctx, userID, content.HashID).Return(false, nil).Times(1)
+			s.Content.EXPECT().GetContentByHashID(ctx, content.HashID).Return(content, nil).Times(1)
+			s.Source.EXPECT().GetSource(ctx, content.SourceID).Return(&model.Source{Title: "source"}, nil).Times(1)
+			s.Bookmark.EXPECT().AddBookmark(ctx, gomock.Any()).Return(nil).Times(1)
+			got, err := c.SaveBookmark(ctx, userID, chatID, content.HashID)
+			assert.Nil(t, err)
+			assert.Equal(t, "source", got.SourceTitle)
+			assert.Equal(t, content.Title, got.Title)
+			assert.Equal(t, content.RawLink, got.RawLink)
+		},
+	)
+
+	t.Run(
+		"remove not exist", func(t *testing.T) {
+			s.Bookmark.EXPECT().DeleteBookmark(ctx, userID, uint(1)).Return(int64(0), nil).Times(1)
+			err := c.RemoveBookmark(ctx, userID, 1)
+			assert.Equal(t, ErrBookmarkNotExist, err)
+		},
+	)
+}
+
+func TestCore_SearchContents(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	userID := int64(1)
+	subs := &storage.GetSubscriptionsResult{
+		Subscriptions: []*model.Subscribe{{UserID: userID, SourceID: 101}, {UserID: userID, SourceID: 102}},
+	}
+
+	t.Run(
+		"all subscriptions", func(t *testing.T) {
+			s.Subscription.EXPECT().GetSubscriptionsByUserID(ctx, userID, gomock.Any()).Return(subs, nil).Times(1)
+			s.Content.EXPECT().SearchContents(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, opts *storage.SearchContentsOptions) (*storage.SearchContentsResult, error) {
+					assert.Equal(t, []uint{101, 102}, opts.SourceIDs)
+					return &storage.SearchContentsResult{}, nil
+				},
+			).Times(1)
+			_, err := c.SearchContents(ctx, userID, 0, &storage.SearchContentsOptions{Query: "q"})
+			assert.Nil(t, err)
+		},
+	)
+
+	t.Run(
+		"not subscribed source", func(t *testing.T) {
+			s.Subscription.EXPECT().GetSubscriptionsByUserID(ctx, userID, gomock.Any()).Return(subs, nil).Times(1)
+			_, err := c.SearchContents(ctx, userID, 103, &storage.SearchContentsOptions{Query: "q"})
+			assert.Equal(t, ErrSubscriptionNotExist, err)
+		},
+	)
+}
+
+func TestCore_IsUserAllowed(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+	userID := int64(101)
+
+	t.Run(
+		"allowlist disabled", func(t *testing.T) {
+			config.AllowlistEnabled = false
+			allowed, err := c.IsUserAllowed(ctx, userID)
+			assert.Nil(t, err)
+			assert.True(t, allowed)
+		},
+	)
+
+	config.AllowlistEnabled = true
+	defer func() { config.AllowlistEnabled = false }()
+
+	t.Run(
+		"allowed", func(t *testing.T) {
+			s.User.EXPECT().GetUser(ctx, userID).Return(&model.User{ID: userID, Allowed: true}, nil).Times(1)
+			allowed, err := c.IsUserAllowed(ctx, userID)
+			assert.Nil(t, err)
+			assert.True(t, allowed)
+		},
+	)
+
+	t.Run(
+		"not allowed", func(t *testing.T) {
+			s.User.EXPECT().GetUser(ctx, userID).Return(nil, storage.ErrRecordNotFound).Times(1)
+			allowed, err := c.IsUserAllowed(ctx, userID)
+			assert.Nil(t, err)
+			assert.False(t, allowed)
+
+			s.User.EXPECT().GetUser(ctx, userID).Return(&model.User{ID: userID}, nil).Times(1)
+			allowed, err = c.IsUserAllowed(ctx, userID)
+			assert.Nil(t, err)
+			assert.False(t, allowed)
+		},
+	)
+
+	t.Run(
+		"get user failed", func(t *testing.T) {
+			s.User.EXPECT().GetUser(ctx, userID).Return(nil, errors.New("err")).Times(1)
+			allowed, err := c.IsUserAllowed(ctx, userID)
+			assert.Error(t, err)
+			assert.False(t, allowed)
+		},
+	)
+}
+
+func TestCore_seedAllowedUsers(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	config.AllowUsers = []int64{101, 102}
+	defer func() { config.AllowUsers = nil }()
+
+	t.Run(
+		"known users are allowed again", func(t *testing.T) {
+			s.User.EXPECT().SetUserAllowed(ctx, int64(101), true).Return(nil).Times(1)
+			s.User.EXPECT().SetUserAllowed(ctx, int64(102), true).Return(nil).Times(1)
+			assert.Nil(t, c.seedAllowedUsers(ctx))
+		},
+	)
+
+	t.Run(
+		"set allowed failed", func(t *testing.T) {
+			s.User.EXPECT().SetUserAllowed(ctx, int64(101), true).Return(errors.New("err")).Times(1)
+			assert.Error(t, c.seedAllowedUsers(ctx))
+		},
+	)
+}
+
+func TestCore_UseInvite(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	s.User.EXPECT().UseInvite(ctx, "used", int64(101)).Return(storage.ErrRecordNotFound).Times(1)
+	err := c.UseInvite(ctx, "used", 101)
+	assert.Equal(t, ErrInviteNotExist, err)
+
+	s.User.EXPECT().UseInvite(ctx, "code", int64(101)).Return(nil).Times(1)
+	err = c.UseInvite(ctx, "code", 101)
+	assert.Nil(t, err)
+}
+
+func TestCore_ChatLeft(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	chatID := int64(-100)
+	s.Message.EXPECT().DeleteChatMessages(ctx, chatID, uint(0)).Return(int64(3), nil).Times(1)
+	s.Chat.EXPECT().SetChatLeftAt(ctx, chatID, gomock.Any()).Return(nil).Times(1)
+	assert.Nil(t, c.ChatLeft(ctx, chatID))
+
+	s.Message.EXPECT().DeleteChatMessages(ctx, chatID, uint(0)).Return(int64(0), errors.New("err")).Times(1)
+	assert.Error(t, c.ChatLeft(ctx, chatID))
+}
+
+func TestCore_MigrateChat(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	s.Chat.EXPECT().MoveChat(ctx, int64(-1), int64(-1001)).Return(int64(2), nil).Times(1)
+	s.Message.EXPECT().DeleteChatMessages(ctx, int64(-1), uint(0)).Return(int64(0), nil).Times(1)
+	s.Chat.EXPECT().SetChatLeftAt(ctx, int64(-1), gomock.Any()).Return(storage.ErrRecordNotFound).Times(1)
+	moved, err := c.MigrateChat(ctx, -1, -1001)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), moved)
+
+	s.Chat.EXPECT().MoveChat(ctx, int64(-2), int64(-1002)).Return(int64(0), errors.New("err")).Times(1)
+	_, err = c.MigrateChat(ctx, -2, -1002)
+	assert.Error(t, err)
+}
+
+func TestCore_Backup(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	chatID := int64(-100)
+	s.Subscription.EXPECT().GetSubscriptionsByUserID(ctx, chatID, gomock.Any()).Return(
+		&storage.GetSubscriptionsResult{
+			Subscriptions: []*model.Subscribe{
+				{SourceID: 1, Tag: "#go #news", Interval: 30, EnableTelegraph: 1, ThreadID: 7},
+			},
+		}, nil,
+	).Times(1)
+	s.Source.EXPECT().GetSource(ctx, uint(1)).Return(
+		&model.Source{ID: 1, Link: "https://example.com/feed.xml", ErrorCount: config.ErrorThreshold + 1}, nil,
+	).Times(1)
+	s.Preference.EXPECT().GetPreference(ctx, chatID).Return(nil, storage.ErrRecordNotFound).Times(1)
+
+	b, err := c.Backup(ctx, chatID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(b.Subscriptions))
+	got := b.Subscriptions[0]
+	assert.Equal(t, "https://example.com/feed.xml", got.URL)
+	assert.Equal(t, []string{"go", "news"}, got.Tags)
+	assert.Equal(t, 30, got.Interval)
+	assert.Equal(t, 7, got.ThreadID)
+	assert.True(t, got.Paused)
+}
+
+func TestCore_Restore(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	chatID := int64(-100)
+	b := backup.New(chatID)
+	b.Subscriptions = []backup.Subscription{
+		{URL: "https://example.com/kept.xml"},
+		{URL: "https://example.com/new.xml"},
+	}
+	s.Subscription.EXPECT().GetSubscriptionsByUserID(ctx, chatID, gomock.Any()).Return(
+		&storage.GetSubscriptionsResult{
+			Subscriptions: []*model.Subscribe{{SourceID: 1}, {SourceID: 2}},
+		}, nil,
+	).Times(1)
+	s.Source.EXPECT().GetSource(ctx, uint(1)).Return(&model.Source{ID: 1, Link: "https://example.com/kept.xml"}, nil)
+	s.Source.EXPECT().GetSource(ctx, uint(2)).Return(&model.Source{ID: 2, Link: "https://example.com/gone.xml"}, nil)
+
+	summary, err := c.Restore(ctx, chatID, b, true, true)
+	assert.Nil(t, err)
+	assert.Equal(t, &RestoreSummary{Added: 1, Updated: 1, Removed: 1}, summary)
+}
+
+func TestCore_DisableUnsharedSourceUpdate(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	sourceID := uint(101)
+
+	t.Run(
+		"shared source left running", func(t *testing.T) {
+			s.Subscription.EXPECT().CountSourceSubscriptions(ctx, sourceID).Return(int64(2), nil).Times(1)
+			paused, err := c.DisableUnsharedSourceUpdate(ctx, sourceID)
+			assert.Nil(t, err)
+			assert.False(t, paused)
+		},
+	)
+
+	t.Run(
+		"unshared source paused", func(t *testing.T) {
+			s.Subscription.EXPECT().CountSourceSubscriptions(ctx, sourceID).Return(int64(1), nil).Times(1)
+			s.Source.EXPECT().GetSource(ctx, sourceID).Return(&model.Source{ID: sourceID}, nil).Times(1)
+			s.Source.EXPECT().UpsertSource(
+				ctx, sourceID, &model.Source{ID: sourceID, ErrorCount: config.ErrorThreshold + 1},
+			).Return(nil).Times(1)
+			paused, err := c.DisableUnsharedSourceUpdate(ctx, sourceID)
+			assert.Nil(t, err)
+			assert.True(t, paused)
+		},
+	)
+
+	t.Run(
+		"count failed", func(t *testing.T) {
+			s.Subscription.EXPECT().CountSourceSubscriptions(ctx, sourceID).Return(int64(0), errors.New("err")).Times(1)
+			_, err := c.DisableUnsharedSourceUpdate(ctx, sourceID)
+			assert.Error(t, err)
+		},
+	)
+}
+
+func TestCore_restoreSubscription(t *testing.T) {
+	c, s := getTestCore(t)
+	defer s.Ctrl.Finish()
+	ctx := context.Background()
+
+	chatID := int64(-100)
+	source := &model.Source{ID: 1, Link: "https://example.com/feed.xml"}
+	s.Source.EXPECT().GetSourceByURL(ctx, source.Link).Return(source, nil).AnyTimes()
+	s.Source.EXPECT().GetSource(ctx, source.ID).Return(source, nil).AnyTimes()
+	s.Subscription.EXPECT().SubscriptionExist(ctx, chatID, source.ID).Return(true, nil).AnyTimes()
+	s.Subscription.EXPECT().GetSubscription(ctx, chatID, source.ID).Return(
+		&model.Subscribe{UserID: chatID, SourceID: source.ID}, nil,
+	).AnyTimes()
+	s.Subscription.EXPECT().UpsertSubscription(ctx, chatID, source.ID, gomock.Any()).Return(nil).AnyTimes()
+	s.Tag.EXPECT().SetSubscriptionTags(ctx, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	entry := backup.Subscription{URL: source.Link, Paused: true}
+
+	t.Run(
+		"shared source is not paused", func(t *testing.T) {
+			s.Subscription.EXPECT().CountSourceSubscriptions(ctx, source.ID).Return(int64(2), nil).Times(1)
+			pauseSkipped, err := c.restoreSubscription(ctx, chatID, entry)
+			assert.Nil(t, err)
+			assert.True(t, pauseSkipped)
+		},
+	)
+
+	t.Run(
+		"source of the chat only is paused", func(t *testing.T) {
+			s.Subscription.EXPECT().CountSourceSubscriptions(ctx, source.ID).Return(int64(1), nil).Times(1)
+			s.Source.EXPECT().UpsertSource(ctx, source.ID, gomock.Any()).Return(nil).Times(1)
+			pauseSkipped, err := c.restoreSubscription(ctx, chatID, entry)
+			assert.Nil(t, err)
+			assert.False(t, pauseSkipped)
+		},
+	)
+}
+
+func Test_applyBackupSettings(t *testing.T) {
+	interval := config.UpdateInterval
+	config.UpdateInterval = 10
+	defer func() { config.UpdateInterval = interval }()
+	tests := []struct {
+		name  string
+		entry backup.Subscription
+		want  model.Subscribe
+	}{
+		{
+			name: "valid settings",
+			entry: backup.Subscription{
+				Title: "news", EnableNotification: 1, EnableEditUpdate: 1, ThreadID: 3, Interval: 30, WaitTime: 5,
+				PreviewLength: -1, WebPagePreview: 2,
+			},
+			want: model.Subscribe{
+				Title: "news", EnableNotification: 1, EnableEditUpdate: 1, ThreadID: 3, Interval: 30, WaitTime: 5,
+				PreviewLength: -1, WebPagePreview: 2,
+			},
+		},
+		{
+			name: "out of range settings",
+			entry: backup.Subscription{
+				EnableNotification: 5, EnableTelegraph: -1, ThreadID: -3, Interval: -1, WaitTime: 100,
+				PreviewLength: -7, WebPagePreview: 9,
+			},
+			want: model.Subscribe{
+				EnableNotification: 1, EnableTelegraph: 1, Interval: 10, WaitTime: 10,
+			},
+		},
+		{
+			name:  "long preview",
+			entry: backup.Subscription{Interval: 10, PreviewLength: 1 << 20},
+			want:  model.Subscribe{Interval: 10, PreviewLength: maxBackupPreviewLength},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got := model.Subscribe{}
+				applyBackupSettings(&got, tt.entry)
+				assert.Equal(t, tt.want, got)
+			},
+		)
+	}
+}
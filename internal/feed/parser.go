@@ -1,15 +1,33 @@
 package feed
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/andatoshiki/toshiki-rssbot/pkg/client"
 
 	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html"
 )
 
+// maxDiscoverBodySize size of the page or feed a link sent to the bot is read up to
+const maxDiscoverBodySize = 5 << 20
+
+var errDiscoverBodyTooLarge = errors.New("response body too large")
+
+// feedLinkTypes content types of the feeds a web page can link to
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	"application/json":      true,
+}
+
 type FeedParser struct {
 	client *client.HttpClient
 	parser *gofeed.Parser
@@ -23,9 +41,15 @@ func NewFeedParser(httpClient *client.HttpClient) *FeedParser {
 }
 
 func (p *FeedParser) ParseFromURL(ctx context.Context, URL string) (*gofeed.Feed, error) {
+	feed, _, err := p.FetchFromURL(ctx, URL)
+	return feed, err
+}
+
+// FetchFromURL fetches and parses a feed, returning the HTTP status code as well, 0 if no response was received
+func (p *FeedParser) FetchFromURL(ctx context.Context, URL string) (*gofeed.Feed, int, error) {
 	resp, err := p.client.GetWithContext(ctx, URL)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if resp != nil {
@@ -38,7 +62,93 @@ func (p *FeedParser) ParseFromURL(ctx context.Context, URL string) (*gofeed.Feed
 	}
 
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return nil, errors.New(resp.Status)
+		return nil, resp.StatusCode, errors.New(resp.Status)
+	}
+	feed, err := p.parser.Parse(resp.Body)
+	return feed, resp.StatusCode, err
+}
+
+// DiscoverFromURL parses a feed, or the first feed a web page links to, returning the url of the feed as well
+func (p *FeedParser) DiscoverFromURL(ctx context.Context, URL string) (*gofeed.Feed, string, error) {
+	resp, err := p.client.GetWithContext(ctx, URL)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, "", errors.New(resp.Status)
+	}
+	// the link may point to anything, a page or feed is read up to the limit
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoverBodySize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(body) > maxDiscoverBodySize {
+		return nil, "", errDiscoverBodyTooLarge
+	}
+
+	feed, err := p.parser.Parse(bytes.NewReader(body))
+	if err == nil {
+		return feed, URL, nil
+	}
+	if !errors.Is(err, gofeed.ErrFeedTypeNotDetected) {
+		return nil, "", err
+	}
+
+	base := resp.Request.URL
+	if base == nil {
+		if base, err = url.Parse(URL); err != nil {
+			return nil, "", err
+		}
+	}
+	links := FeedLinks(bytes.NewReader(body), base)
+	if len(links) == 0 {
+		return nil, "", gofeed.ErrFeedTypeNotDetected
+	}
+	feed, err = p.ParseFromURL(ctx, links[0])
+	if err != nil {
+		return nil, "", err
+	}
+	return feed, links[0], nil
+}
+
+// FeedLinks finds the feeds an HTML page links to with <link rel="alternate">, resolved against the page url
+func FeedLinks(r io.Reader, base *url.URL) []string {
+	var links []string
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data == "body" {
+				return links
+			}
+			if token.Data != "link" {
+				continue
+			}
+
+			var rel, linkType, href string
+			for _, attr := range token.Attr {
+				switch attr.Key {
+				case "rel":
+					rel = strings.ToLower(attr.Val)
+				case "type":
+					linkType = strings.ToLower(strings.TrimSpace(attr.Val))
+				case "href":
+					href = strings.TrimSpace(attr.Val)
+				}
+			}
+			if !strings.Contains(rel, "alternate") || !feedLinkTypes[linkType] || href == "" {
+				continue
+			}
+			link, err := base.Parse(href)
+			if err != nil {
+				continue
+			}
+			links = append(links, link.String())
+		}
 	}
-	return p.parser.Parse(resp.Body)
 }
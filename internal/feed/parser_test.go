@@ -0,0 +1,58 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/pkg/client"
+)
+
+func TestFeedLinks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post")
+	page := `<html><head>
+<link rel="stylesheet" href="/style.css">
+<link rel="alternate" type="application/rss+xml" title="RSS" href="/feed.xml">
+<link rel="alternate" type="application/atom+xml" href="https://example.com/atom.xml" />
+<link rel="alternate" hreflang="ja" href="/ja/">
+</head><body><link rel="alternate" type="application/rss+xml" href="/ignored.xml"></body></html>`
+
+	links := FeedLinks(strings.NewReader(page), base)
+	assert.Equal(t, []string{"https://example.com/feed.xml", "https://example.com/atom.xml"}, links)
+
+	assert.Empty(t, FeedLinks(strings.NewReader("<html><body>no feed</body></html>"), base))
+}
+
+func TestDiscoverFromURL(t *testing.T) {
+	feed := `<?xml version="1.0"?><rss version="2.0"><channel><title>news</title></channel></rss>`
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/feed.xml":
+					w.Write([]byte(feed))
+				case "/large":
+					w.Write([]byte("<html>" + strings.Repeat(" ", maxDiscoverBodySize) + "</html>"))
+				default:
+					w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head></html>`))
+				}
+			},
+		),
+	)
+	defer server.Close()
+
+	p := NewFeedParser(client.NewHttpClient(client.WithTimeout(5 * time.Second)))
+	got, feedURL, err := p.DiscoverFromURL(context.Background(), server.URL+"/page")
+	assert.Nil(t, err)
+	assert.Equal(t, "news", got.Title)
+	assert.Equal(t, server.URL+"/feed.xml", feedURL)
+
+	_, _, err = p.DiscoverFromURL(context.Background(), server.URL+"/large")
+	assert.Equal(t, errDiscoverBodyTooLarge, err)
+}
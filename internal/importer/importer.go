@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/opml"
+)
+
+// ErrUnsupported no importer reads the file
+var ErrUnsupported = errors.New("unsupported file")
+
+// sniffLength number of bytes of a file the importers match against
+const sniffLength = 512
+
+// Importer reads the feeds of a subscription export of another reader
+type Importer interface {
+	// Name of the format
+	Name() string
+	// Match reports whether the importer reads a file, by its name and first bytes
+	Match(fileName string, head []byte) bool
+	// Read parses the feeds of a file, with the folders they were filed under as tags
+	Read(data []byte) ([]opml.FeedOutline, error)
+}
+
+var (
+	mu        sync.RWMutex
+	importers []Importer
+)
+
+func init() {
+	Register(&OPMLImporter{})
+	Register(&MinifluxImporter{})
+	Register(&URLListImporter{})
+}
+
+// Register adds an importer, the importers are tried in the order they were registered
+func Register(importer Importer) {
+	mu.Lock()
+	defer mu.Unlock()
+	importers = append(importers, importer)
+}
+
+// Find gets the first importer that reads a file
+func Find(fileName string, data []byte) (Importer, error) {
+	head := data
+	if len(head) > sniffLength {
+		head = head[:sniffLength]
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, importer := range importers {
+		if importer.Match(fileName, head) {
+			return importer, nil
+		}
+	}
+	return nil, ErrUnsupported
+}
+
+// Read parses the feeds of a file with the first importer that reads it
+func Read(fileName string, data []byte) ([]opml.FeedOutline, error) {
+	importer, err := Find(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	return importer.Read(data)
+}
@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	t.Run(
+		"opml variants", func(t *testing.T) {
+			data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+	<body>
+		<outline title="Tech" text="Tech">
+			<outline text="inoreader" type="rss" xmlUrl="https://example.com/a.xml" rssfr-useCustomTitle="true"/>
+			<outline text="lowercase" type="rss" xmlurl="https://example.com/b.xml"/>
+			<outline text="opml 1.0" type="rss" url="https://example.com/c.xml"/>
+		</outline>
+	</body>
+</opml>`)
+			importer, err := Find("feedly.xml", data)
+			assert.Nil(t, err)
+			assert.Equal(t, "OPML", importer.Name())
+
+			outlines, err := importer.Read(data)
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(outlines))
+			for i, url := range []string{"https://example.com/a.xml", "https://example.com/b.xml", "https://example.com/c.xml"} {
+				assert.Equal(t, url, outlines[i].XMLURL)
+				assert.Equal(t, []string{"Tech"}, outlines[i].Tags)
+			}
+		},
+	)
+
+	t.Run(
+		"miniflux", func(t *testing.T) {
+			data := []byte(`[
+	{"id": 1, "feed_url": "https://example.com/a.xml", "title": "A", "category": {"id": 2, "title": "Dev News"}},
+	{"id": 2, "feed_url": "https://example.com/b.xml", "title": "B"}
+]`)
+			outlines, err := Read("feeds.json", data)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(outlines))
+			assert.Equal(t, "A", outlines[0].Name())
+			assert.Equal(t, []string{"Dev_News"}, outlines[0].Tags)
+			assert.Empty(t, outlines[1].Tags)
+		},
+	)
+
+	t.Run(
+		"url list", func(t *testing.T) {
+			data := []byte("# my feeds\n\nhttps://example.com/a.xml\nhttps://example.com/b.xml B feed\nnot a url\n")
+			outlines, err := Read("feeds", data)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(outlines))
+			assert.Equal(t, "https://example.com/a.xml", outlines[0].XMLURL)
+			assert.Equal(t, "B feed", outlines[1].Name())
+		},
+	)
+
+	t.Run(
+		"unsupported", func(t *testing.T) {
+			_, err := Read("backup.json", []byte(`{"version": 1, "subscriptions": []}`))
+			assert.Equal(t, ErrUnsupported, err)
+		},
+	)
+}
@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/opml"
+)
+
+// MinifluxImporter reads the feed list of the Miniflux API, a JSON array of feeds with their categories
+type MinifluxImporter struct{}
+
+type minifluxFeed struct {
+	FeedURL  string `json:"feed_url"`
+	Title    string `json:"title"`
+	Category *struct {
+		Title string `json:"title"`
+	} `json:"category"`
+}
+
+func (i *MinifluxImporter) Name() string {
+	return "Miniflux JSON"
+}
+
+func (i *MinifluxImporter) Match(fileName string, head []byte) bool {
+	head = bytes.TrimSpace(head)
+	return bytes.HasPrefix(head, []byte("[")) && bytes.Contains(head, []byte(`"feed_url"`))
+}
+
+func (i *MinifluxImporter) Read(data []byte) ([]opml.FeedOutline, error) {
+	var feeds []minifluxFeed
+	if err := json.Unmarshal(data, &feeds); err != nil {
+		return nil, err
+	}
+
+	var outlines []opml.FeedOutline
+	for _, feed := range feeds {
+		if feed.FeedURL == "" {
+			continue
+		}
+		outline := opml.FeedOutline{Outline: opml.Outline{Text: feed.Title, XMLURL: feed.FeedURL}}
+		if feed.Category != nil {
+			if tag := opml.TagName(feed.Category.Title); tag != "" {
+				outline.Tags = []string{tag}
+			}
+		}
+		outlines = append(outlines, outline)
+	}
+	return outlines, nil
+}
@@ -0,0 +1,31 @@
+package importer
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/opml"
+)
+
+// OPMLImporter reads OPML files, including the variants exported by Inoreader, Feedly, FreshRSS and Miniflux
+type OPMLImporter struct{}
+
+func (i *OPMLImporter) Name() string {
+	return "OPML"
+}
+
+func (i *OPMLImporter) Match(fileName string, head []byte) bool {
+	if strings.EqualFold(path.Ext(fileName), ".opml") {
+		return true
+	}
+	return bytes.Contains(bytes.ToLower(head), []byte("<opml"))
+}
+
+func (i *OPMLImporter) Read(data []byte) ([]opml.FeedOutline, error) {
+	o, err := opml.NewOPML(data)
+	if err != nil {
+		return nil, err
+	}
+	return o.GetFlattenOutlines()
+}
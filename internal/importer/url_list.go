@@ -0,0 +1,54 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/opml"
+)
+
+// URLListImporter reads plain text lists with one feed url per line, optionally followed by its name.
+// Empty lines and lines starting with # are skipped
+type URLListImporter struct{}
+
+func (i *URLListImporter) Name() string {
+	return "URL list"
+}
+
+func (i *URLListImporter) Match(fileName string, head []byte) bool {
+	if strings.EqualFold(path.Ext(fileName), ".txt") {
+		return true
+	}
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		return isURL(string(line))
+	}
+	return false
+}
+
+func (i *URLListImporter) Read(data []byte) ([]opml.FeedOutline, error) {
+	var outlines []opml.FeedOutline
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if !isURL(fields[0]) {
+			continue
+		}
+		name := strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+		outlines = append(outlines, opml.FeedOutline{Outline: opml.Outline{Text: name, XMLURL: fields[0]}})
+	}
+	return outlines, scanner.Err()
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,15 @@
+package model
+
+// Bookmark content saved by a user to read later, kept after the content itself is pruned
+type Bookmark struct {
+	ID           uint  `gorm:"primary_key;AUTO_INCREMENT"`
+	UserID       int64 `gorm:"index"` // user who saved the content
+	ChatID       int64 // chat the content was saved from
+	SourceID     uint
+	HashID       string
+	SourceTitle  string
+	Title        string
+	RawLink      string
+	TelegraphURL string
+	EditTime
+}
@@ -8,6 +8,8 @@ type Content struct {
 	RawLink      string
 	Title        string
 	Description  string `gorm:"-"` //ignore to db
+	Text         string `gorm:"type:text"` // description stripped of markup, indexed for search
 	TelegraphURL string
+	Fingerprint  string // title and description digest, used to detect upstream edits
 	EditTime
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// SourceFetch a fetch of a source by the scheduler
+type SourceFetch struct {
+	ID         uint      `gorm:"primary_key;AUTO_INCREMENT"`
+	SourceID   uint      `gorm:"index"`
+	FetchedAt  time.Time `gorm:"index"`
+	DurationMs int64
+	StatusCode int // HTTP status, 0 if no response was received
+	NewItems   int
+	Error      string // empty if the fetch succeeded
+}
+
+// FetchCycle a scheduler pass over all sources
+type FetchCycle struct {
+	ID          uint      `gorm:"primary_key;AUTO_INCREMENT"`
+	StartedAt   time.Time `gorm:"index"`
+	DurationMs  int64
+	SourceCount int
+	FailedCount int
+}
@@ -13,3 +13,10 @@ func GenHashID(sLink string, id string) string {
 	encoded := hex.EncodeToString(f.Sum(nil))
 	return encoded
 }
+
+// GenFingerprint digests the visible part of an item so that upstream edits can be detected
+func GenFingerprint(title string, description string) string {
+	f := fnv.New64a()
+	f.Write([]byte(title + "||" + description))
+	return hex.EncodeToString(f.Sum(nil))
+}
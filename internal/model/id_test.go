@@ -1,6 +1,10 @@
 package model
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func Test_genHashID(t *testing.T) {
 	type args struct {
@@ -28,3 +32,10 @@ func Test_genHashID(t *testing.T) {
 		)
 	}
 }
+
+func Test_genFingerprint(t *testing.T) {
+	a := GenFingerprint("title", "description")
+	assert.Equal(t, a, GenFingerprint("title", "description"))
+	assert.NotEqual(t, a, GenFingerprint("title fixed", "description"))
+	assert.NotEqual(t, a, GenFingerprint("title", "description updated"))
+}
@@ -0,0 +1,11 @@
+package model
+
+// Message a content message delivered to a subscriber
+type Message struct {
+	ID        uint `gorm:"primary_key;AUTO_INCREMENT"`
+	SourceID  uint
+	HashID    string `gorm:"index"`
+	ChatID    int64  `gorm:"index"`
+	MessageID int
+	EditTime
+}
@@ -1,6 +1,6 @@
 package model
 
-// Option bot 设置
+// Option bot settings
 type Option struct {
 	ID    int `gorm:"primary_key;AUTO_INCREMENT"`
 	Name  string
@@ -0,0 +1,9 @@
+package model
+
+// ChatPreference per-chat bot preferences
+type ChatPreference struct {
+	ChatID             int64 `gorm:"primary_key;autoIncrement:false"`
+	EnableActionButton int
+	Language           string // language of the bot replies, empty follows the user language
+	EditTime
+}
@@ -1,10 +1,15 @@
 package model
 
+import "time"
+
 type Source struct {
-	ID         uint `gorm:"primary_key;AUTO_INCREMENT"`
-	Link       string
-	Title      string
-	ErrorCount uint
-	Content    []Content
+	ID            uint `gorm:"primary_key;AUTO_INCREMENT"`
+	Link          string
+	Title         string
+	ErrorCount    uint
+	LastFetchedAt *time.Time // nil if the source was never fetched
+	LastError     string     // error of the last fetch, empty if it succeeded
+	LastNewItemAt *time.Time // nil if no new item was fetched yet
+	Content       []Content
 	EditTime
 }
@@ -1,13 +1,30 @@
 package model
 
+import "gorm.io/gorm"
+
 type Subscribe struct {
 	ID                 uint `gorm:"primary_key;AUTO_INCREMENT"`
 	UserID             int64
 	SourceID           uint
+	Title              string // name of the source in the chat, empty uses the source title
 	EnableNotification int
 	EnableTelegraph    int
-	Tag                string
+	EnableEditUpdate   int
+	Tag                string // rendered "#tag1 #tag2" for the templates, the tags table is the source of truth
+	ThreadID           int    // forum topic the subscription is delivered to, 0 for the general topic
 	Interval           int
+	PreviewLength      int // preview text length, 0 follows the config and -1 hides the preview text
+	WebPagePreview     int // 0 follows the config, 1 shows and 2 hides the web page preview
 	WaitTime           int
+	DeletedAt          gorm.DeletedAt `gorm:"index"` // set while the removal can be undone, purged afterwards
+	RemovalID          uint32         // the unsubscription the removal belongs to, restored together by its Undo
 	EditTime
 }
+
+// DisplayTitle gets the name of the source shown in the chat
+func (s *Subscribe) DisplayTitle(source *Source) string {
+	if s.Title != "" {
+		return s.Title
+	}
+	return source.Title
+}
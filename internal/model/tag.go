@@ -0,0 +1,50 @@
+package model
+
+import "strings"
+
+// Tag a tag of the subscriptions of a chat, the name is stored normalized without the leading #
+type Tag struct {
+	ID     uint   `gorm:"primary_key;AUTO_INCREMENT"`
+	UserID int64  `gorm:"uniqueIndex:idx_tags_user_name"`
+	Name   string `gorm:"size:64;uniqueIndex:idx_tags_user_name"`
+	EditTime
+}
+
+// SubscribeTag links a subscription to one of its tags
+type SubscribeTag struct {
+	SubscribeID uint `gorm:"primaryKey;autoIncrement:false"`
+	TagID       uint `gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+// NormalizeTag normalizes a tag name, #News and news are the same tag
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimLeft(strings.TrimSpace(name), "#"))
+}
+
+// NormalizeTags normalizes tag names, dropping empty and duplicated names
+func NormalizeTags(names []string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		tag := NormalizeTag(name)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// ParseTags parses tags rendered like "#tag1 #tag2"
+func ParseTags(rendered string) []string {
+	return NormalizeTags(strings.Fields(rendered))
+}
+
+// FormatTags renders tags as hashtags like "#tag1 #tag2"
+func FormatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(tags, " #")
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		rendered string
+		want     []string
+	}{
+		{"empty", "", nil},
+		{"rendered", "#go #news", []string{"go", "news"}},
+		{"case and duplicates", "#Go go ##news #", []string{"go", "news"}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, ParseTags(tt.rendered))
+			},
+		)
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	assert.Equal(t, "", FormatTags(nil))
+	assert.Equal(t, "#go #news", FormatTags([]string{"go", "news"}))
+}
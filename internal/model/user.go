@@ -1,7 +1,35 @@
 package model
 
+import "time"
+
 // User subscriber
 type User struct {
-	ID int64 `gorm:"primary_key"`
+	ID           int64 `gorm:"primary_key"`
+	Username     string
+	Name         string
+	LanguageCode string // language reported by Telegram
+	Allowed      bool   `gorm:"index"` // on the allowlist of the bot
+	EditTime
+}
+
+// Chat a chat the bot posts to, private chats share the id of their user
+type Chat struct {
+	ID           int64  `gorm:"primary_key;autoIncrement:false"`
+	Type         string // private, group, supergroup or channel
+	Title        string // title of groups and channels, name of the user of private chats
+	Username     string
+	LanguageCode string     // language reported by Telegram for the user of private chats
+	TimeZone     string     // IANA time zone name, empty for the time zone of the server
+	LeftAt       *time.Time // when the bot was blocked or removed, nil while it can post
+	RegisteredBy int64      `gorm:"index"` // user who last subscribed the channel or added the bot to it, 0 for other chats
+	EditTime
+}
+
+// Invite a one-time code that allowlists the user starting the bot with it
+type Invite struct {
+	Code      string `gorm:"primary_key;size:32"`
+	CreatedBy int64
+	UsedBy    int64      // 0 if the invite was not used yet
+	UsedAt    *time.Time // nil if the invite was not used yet
 	EditTime
 }
@@ -4,9 +4,8 @@ import (
 	"encoding/xml"
 	"errors"
 	"io"
+	"strings"
 	"time"
-
-	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 )
 
 // OPML opml struct
@@ -55,6 +54,8 @@ type Outline struct {
 	Title        string    `xml:"title,attr,omitempty"`
 	Version      string    `xml:"version,attr,omitempty"`
 	Description  string    `xml:"description,attr,omitempty"`
+	// Attrs attributes other readers add, like Inoreader and Feedly
+	Attrs []xml.Attr `xml:",any,attr"`
 }
 
 // NewOPML gen OPML form []byte
@@ -81,24 +82,82 @@ func ReadOPML(r io.Reader) (*OPML, error) {
 	return o, nil
 }
 
-// GetFlattenOutlines make all outline at the same xml level
-func (o OPML) GetFlattenOutlines() ([]Outline, error) {
-	var flattenOutlines []Outline
-	for _, line := range o.Body.Outlines {
-		if line.Outlines != nil {
-			for _, subLine := range line.Outlines {
-				// 查找子outline
-				if subLine.XMLURL != "" {
-					flattenOutlines = append(flattenOutlines, subLine)
-				}
-			}
+// FeedOutline a feed outline with the tags it is filed under
+type FeedOutline struct {
+	Outline
+	Tags []string // names of the folders the feed is nested in and its categories
+}
+
+// Name gets the display name of an outline, the title falling back to the text
+func (o Outline) Name() string {
+	if o.Title != "" {
+		return o.Title
+	}
+	return o.Text
+}
+
+// FeedURL gets the feed url of an outline, also spelled xmlurl by some readers or kept in url by OPML 1.0 files
+func (o Outline) FeedURL() string {
+	if o.XMLURL != "" {
+		return o.XMLURL
+	}
+	for _, attr := range o.Attrs {
+		if strings.EqualFold(attr.Name.Local, "xmlUrl") && attr.Value != "" {
+			return attr.Value
 		}
+	}
+	switch strings.ToLower(o.Type) {
+	case "rss", "atom":
+		return o.URL
+	}
+	return ""
+}
+
+// GetFlattenOutlines make all feed outlines at the same level, however deep they are nested in folders
+func (o OPML) GetFlattenOutlines() ([]FeedOutline, error) {
+	return flattenOutlines(o.Body.Outlines, nil), nil
+}
 
+func flattenOutlines(outlines []Outline, folders []string) []FeedOutline {
+	var feeds []FeedOutline
+	for _, line := range outlines {
+		line.XMLURL = line.FeedURL()
 		if line.XMLURL != "" {
-			flattenOutlines = append(flattenOutlines, line)
+			tags := append(append([]string{}, folders...), categoryTags(line.Category)...)
+			feeds = append(feeds, FeedOutline{Outline: line, Tags: tags})
+		}
+		if len(line.Outlines) == 0 {
+			continue
+		}
+
+		// an outline without a feed is a folder, the feeds in it are tagged with its name
+		children := folders
+		if line.XMLURL == "" {
+			if tag := TagName(line.Name()); tag != "" {
+				children = append(append([]string{}, folders...), tag)
+			}
+		}
+		feeds = append(feeds, flattenOutlines(line.Outlines, children)...)
+	}
+	return feeds
+}
+
+// categoryTags gets the tags of a category attribute, comma separated slash delimited categories like "/tech/go,news"
+func categoryTags(category string) []string {
+	var tags []string
+	for _, path := range strings.Split(category, ",") {
+		for _, name := range strings.Split(path, "/") {
+			if tag := TagName(name); tag != "" {
+				tags = append(tags, tag)
+			}
 		}
 	}
-	return flattenOutlines, nil
+	return tags
+}
+
+// TagName turns a folder name into a tag, tags can not contain spaces
+func TagName(name string) string {
+	return strings.Join(strings.Fields(name), "_")
 }
 
 // XML dump OPML to xml file
@@ -107,20 +166,47 @@ func (o OPML) XML() (string, error) {
 	return xml.Header + string(b), err
 }
 
-// ToOPML dump sources to opml file
-func ToOPML(sources []*model.Source) (string, error) {
+// Feed a subscription to export
+type Feed struct {
+	Title  string
+	XMLURL string
+	Tags   []string
+}
+
+// ToOPML dump feeds to opml file, feeds are grouped into folders by their first tag and keep all tags as categories
+func ToOPML(feeds []Feed) (string, error) {
 	O := OPML{}
 	O.XMLName.Local = "opml"
 	O.Version = "2.0"
 	O.XMLName.Space = ""
 	O.Head.Title = "subscriptions in toshiki-rssbot"
 	O.Head.DateCreated = time.Now().Format(time.RFC1123)
-	for _, s := range sources {
+
+	folders := make(map[string]int)
+	for _, feed := range feeds {
 		outline := Outline{}
-		outline.Text = s.Title
+		outline.Text = feed.Title
+		outline.Title = feed.Title
 		outline.Type = "rss"
-		outline.XMLURL = s.Link
-		O.Body.Outlines = append(O.Body.Outlines, outline)
+		outline.XMLURL = feed.XMLURL
+		if len(feed.Tags) == 0 {
+			O.Body.Outlines = append(O.Body.Outlines, outline)
+			continue
+		}
+
+		var categories []string
+		for _, tag := range feed.Tags {
+			categories = append(categories, "/"+tag)
+		}
+		outline.Category = strings.Join(categories, ",")
+
+		i, ok := folders[feed.Tags[0]]
+		if !ok {
+			i = len(O.Body.Outlines)
+			folders[feed.Tags[0]] = i
+			O.Body.Outlines = append(O.Body.Outlines, Outline{Text: feed.Tags[0], Title: feed.Tags[0]})
+		}
+		O.Body.Outlines[i].Outlines = append(O.Body.Outlines[i].Outlines, outline)
 	}
 	return O.XML()
 }
@@ -0,0 +1,65 @@
+package opml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOPML_GetFlattenOutlines(t *testing.T) {
+	o, err := ReadOPML(
+		strings.NewReader(
+			`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+	<head><title>feeds</title></head>
+	<body>
+		<outline text="top" xmlUrl="https://example.com/top.xml" category="/news,/tech/go"/>
+		<outline text="Tech News">
+			<outline text="deep">
+				<outline text="go blog" title="The Go Blog" xmlUrl="https://go.dev/blog/feed.atom"/>
+			</outline>
+			<outline text="hn" xmlUrl="https://news.ycombinator.com/rss"/>
+		</outline>
+	</body>
+</opml>`,
+		),
+	)
+	assert.Nil(t, err)
+
+	outlines, err := o.GetFlattenOutlines()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(outlines))
+
+	assert.Equal(t, "https://example.com/top.xml", outlines[0].XMLURL)
+	assert.Equal(t, []string{"news", "tech", "go"}, outlines[0].Tags)
+
+	assert.Equal(t, "The Go Blog", outlines[1].Name())
+	assert.Equal(t, []string{"Tech_News", "deep"}, outlines[1].Tags)
+
+	assert.Equal(t, "hn", outlines[2].Name())
+	assert.Equal(t, []string{"Tech_News"}, outlines[2].Tags)
+}
+
+func TestToOPML(t *testing.T) {
+	feeds := []Feed{
+		{Title: "a", XMLURL: "https://example.com/a.xml", Tags: []string{"news", "go"}},
+		{Title: "b", XMLURL: "https://example.com/b.xml"},
+		{Title: "c", XMLURL: "https://example.com/c.xml", Tags: []string{"news"}},
+	}
+	xml, err := ToOPML(feeds)
+	assert.Nil(t, err)
+
+	o, err := ReadOPML(strings.NewReader(xml))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(o.Body.Outlines))
+	assert.Equal(t, "news", o.Body.Outlines[0].Text)
+	assert.Equal(t, 2, len(o.Body.Outlines[0].Outlines))
+
+	// importing the export restores the tags
+	outlines, err := o.GetFlattenOutlines()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(outlines))
+	assert.Equal(t, []string{"news", "news", "go"}, outlines[0].Tags)
+	assert.Equal(t, "https://example.com/b.xml", outlines[2].XMLURL)
+}
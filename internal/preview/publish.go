@@ -1,10 +1,14 @@
 package tgraph
 
 import (
+	"errors"
 	"html"
 	"math/rand"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/andatoshiki/telegraph-go"
 	"go.uber.org/zap"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/log"
@@ -24,3 +28,30 @@ func PublishHtml(sourceTitle string, title string, rawLink string, htmlContent s
 		return "", nil
 	}
 }
+
+// EditHtml replaces the content of a published telegraph page
+func EditHtml(pageURL string, sourceTitle string, title string, rawLink string, htmlContent string) error {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return err
+	}
+	path := strings.TrimPrefix(u.Path, "/")
+	if path == "" {
+		return errors.New("invalid telegraph page url")
+	}
+
+	nodes, err := telegraph.NewNodesWithHTML(html.UnescapeString(htmlContent))
+	if err != nil {
+		return err
+	}
+
+	// pages can only be edited by the account that created them, which is unknown after a random pick
+	for _, client := range clientPool {
+		if _, err = client.EditPage(path, title+" - "+sourceTitle, nodes, sourceTitle, rawLink, false); err == nil {
+			zap.S().Infof("Edited telegraph page url: %s", pageURL)
+			return nil
+		}
+	}
+	log.Warnf("Edit telegraph page %s failed, error: %v", pageURL, err)
+	return err
+}
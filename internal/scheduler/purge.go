@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/config"
+	"github.com/andatoshiki/toshiki-rssbot/internal/core"
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+)
+
+const purgeInterval = time.Minute
+
+// NewPurgeTask new PurgeTask
+func NewPurgeTask(appCore *core.Core) *PurgeTask {
+	return &PurgeTask{core: appCore}
+}
+
+// PurgeTask purges the removed subscriptions once their undo period ends, and the sources left without subscriptions
+type PurgeTask struct {
+	isStop atomic.Bool
+	core   *core.Core
+}
+
+// Stop scheduler
+func (t *PurgeTask) Stop() {
+	t.isStop.Store(true)
+}
+
+// Start run scheduler
+func (t *PurgeTask) Start() {
+	if config.RunMode == config.TestMode {
+		return
+	}
+
+	t.isStop.Store(false)
+	go func() {
+		for {
+			if t.isStop.Load() {
+				log.Info("PurgeTask stopped")
+				return
+			}
+
+			if err := t.core.PurgeRemovedSubscriptions(context.Background()); err != nil {
+				log.Errorf("purge removed subscriptions failed, %v", err)
+			}
+			time.Sleep(purgeInterval)
+		}
+	}()
+}
@@ -19,6 +19,7 @@ import (
 // RssUpdateObserver Rss Update observer
 type RssUpdateObserver interface {
 	SourceUpdate(*model.Source, []*model.Content, []*model.Subscribe)
+	SourceContentUpdate(*model.Source, []*model.Content, []*model.Subscribe)
 	SourceUpdateError(*model.Source)
 }
 
@@ -41,7 +42,7 @@ type RssUpdateTask struct {
 	httpClient   *client.HttpClient
 }
 
-// Register 注册rss更新订阅者
+// Register registers the subscriber of rss updates
 func (t *RssUpdateTask) Register(observer RssUpdateObserver) {
 	t.observerList = append(t.observerList, observer)
 }
@@ -71,20 +72,23 @@ func (t *RssUpdateTask) Start() {
 				time.Sleep(time.Duration(config.UpdateInterval) * time.Minute)
 				continue
 			}
+			cycle := &model.FetchCycle{StartedAt: time.Now()}
 			for _, source := range sources {
 				if source.ErrorCount >= config.ErrorThreshold {
 					continue
 				}
 
-				newContents, err := t.getSourceNewContents(source)
+				cycle.SourceCount++
+				newContents, updatedContents, err := t.getSourceNewContents(source)
 				if err != nil {
+					cycle.FailedCount++
 					if source.ErrorCount >= config.ErrorThreshold {
 						t.notifyAllObserverErrorUpdate(source)
 					}
 					continue
 				}
 
-				if len(newContents) > 0 {
+				if len(newContents) > 0 || len(updatedContents) > 0 {
 					subs, err := t.core.GetSourceAllSubscriptions(
 						context.Background(), source.ID,
 					)
@@ -92,39 +96,67 @@ func (t *RssUpdateTask) Start() {
 						log.Errorf("get subscriptions failed, %v", err)
 						continue
 					}
-					t.notifyAllObserverUpdate(source, newContents, subs)
+					if len(newContents) > 0 {
+						t.notifyAllObserverUpdate(source, newContents, subs)
+					}
+					if len(updatedContents) > 0 {
+						t.notifyAllObserverContentUpdate(source, updatedContents, subs)
+					}
 				}
 			}
 
+			cycle.DurationMs = time.Since(cycle.StartedAt).Milliseconds()
+			if err := t.core.RecordFetchCycle(context.Background(), cycle); err != nil {
+				log.Errorf("record fetch cycle failed, %v", err)
+			}
 			time.Sleep(time.Duration(config.UpdateInterval) * time.Minute)
 		}
 	}()
 }
 
-// getSourceNewContents 获取rss新内容
-func (t *RssUpdateTask) getSourceNewContents(source *model.Source) ([]*model.Content, error) {
+// getSourceNewContents gets the new contents of a rss source
+// the known contents that changed upstream are returned as well
+func (t *RssUpdateTask) getSourceNewContents(source *model.Source) ([]*model.Content, []*model.Content, error) {
 	log.Debugf("fetch source [%d]%s update", source.ID, source.Link)
 
-	rssFeed, err := t.feedParser.ParseFromURL(context.Background(), source.Link)
+	fetch := &model.SourceFetch{SourceID: source.ID, FetchedAt: time.Now()}
+	defer func() {
+		if err := t.core.RecordSourceFetch(context.Background(), fetch); err != nil {
+			log.Errorf("record source %d fetch failed, %v", source.ID, err)
+		}
+	}()
+
+	rssFeed, statusCode, err := t.feedParser.FetchFromURL(context.Background(), source.Link)
+	fetch.DurationMs = time.Since(fetch.FetchedAt).Milliseconds()
+	fetch.StatusCode = statusCode
 	if err != nil {
 		log.Errorf("unable to fetch feed, source %#v, err %v", source, err)
-		t.core.SourceErrorCountIncr(context.Background(), source.ID)
-		return nil, err
+		fetch.Error = err.Error()
+		t.core.SourceFetchFailed(context.Background(), source.ID, err)
+		return nil, nil, err
 	}
-	t.core.ClearSourceErrorCount(context.Background(), source.ID)
+	t.core.SourceFetchSucceeded(context.Background(), source.ID)
 
-	newContents, err := t.saveNewContents(source, rssFeed.Items)
+	newContents, existItems, err := t.saveNewContents(source, rssFeed.Items)
 	if err != nil {
-		return nil, err
+		fetch.Error = err.Error()
+		return nil, nil, err
 	}
-	return newContents, nil
+	fetch.NewItems = len(newContents)
+
+	updatedContents, err := t.core.UpdateSourceContents(context.Background(), source, existItems)
+	if err != nil {
+		log.Errorf("update source %d contents failed, %v", source.ID, err)
+	}
+	return newContents, updatedContents, nil
 }
 
-// saveNewContents generate content by fetcher item
+// saveNewContents generate content by fetcher item, the items already saved are returned untouched
 func (t *RssUpdateTask) saveNewContents(
 	s *model.Source, items []*gofeed.Item,
-) ([]*model.Content, error) {
+) ([]*model.Content, []*gofeed.Item, error) {
 	var newItems []*gofeed.Item
+	var existItems []*gofeed.Item
 	for _, item := range items {
 		hashID := model.GenHashID(s.Link, item.GUID)
 		exist, err := t.core.ContentHashIDExist(context.Background(), hashID)
@@ -133,12 +165,17 @@ func (t *RssUpdateTask) saveNewContents(
 		}
 
 		if exist {
-			// if exist, then end the process and skip
+			// if exist, skip it here and check it for upstream edits later
+			existItems = append(existItems, item)
 			continue
 		}
 		newItems = append(newItems, item)
 	}
-	return t.core.AddSourceContents(context.Background(), s, newItems)
+	newContents, err := t.core.AddSourceContents(context.Background(), s, newItems)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newContents, existItems, nil
 }
 
 // notifyAllObserverUpdate notify all rss SourceUpdate observer
@@ -156,6 +193,21 @@ func (t *RssUpdateTask) notifyAllObserverUpdate(
 	wg.Wait()
 }
 
+// notifyAllObserverContentUpdate notify all rss SourceContentUpdate observer
+func (t *RssUpdateTask) notifyAllObserverContentUpdate(
+	source *model.Source, updatedContents []*model.Content, subscribes []*model.Subscribe,
+) {
+	wg := sync.WaitGroup{}
+	for _, observer := range t.observerList {
+		wg.Add(1)
+		go func(o RssUpdateObserver) {
+			defer wg.Done()
+			o.SourceContentUpdate(source, updatedContents, subscribes)
+		}(observer)
+	}
+	wg.Wait()
+}
+
 // notifyAllObserverErrorUpdate notify all rss error SourceUpdate observer
 func (t *RssUpdateTask) notifyAllObserverErrorUpdate(source *model.Source) {
 	wg := sync.WaitGroup{}
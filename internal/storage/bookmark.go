@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+type BookmarkStorageImpl struct {
+	db *gorm.DB
+}
+
+func NewBookmarkStorageImpl(db *gorm.DB) *BookmarkStorageImpl {
+	return &BookmarkStorageImpl{db: db.Model(&model.Bookmark{})}
+}
+
+func (s *BookmarkStorageImpl) Init(ctx context.Context) error {
+	return s.db.Migrator().AutoMigrate(&model.Bookmark{})
+}
+
+func (s *BookmarkStorageImpl) AddBookmark(ctx context.Context, bookmark *model.Bookmark) error {
+	result := s.db.WithContext(ctx).Create(bookmark)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (s *BookmarkStorageImpl) BookmarkExist(ctx context.Context, userID int64, hashID string) (bool, error) {
+	var count int64
+	result := s.db.WithContext(ctx).Where("user_id = ? and hash_id = ?", userID, hashID).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return (count > 0), nil
+}
+
+func (s *BookmarkStorageImpl) GetBookmarksByUserID(
+	ctx context.Context, userID int64, opts *GetBookmarksOptions,
+) (*GetBookmarksResult, error) {
+	var bookmarks []*model.Bookmark
+
+	count := opts.Count
+	if count != -1 {
+		count += 1
+	}
+	dbResult := s.db.WithContext(ctx).Where("user_id = ?", userID).
+		Limit(count).Order("created_at desc").Offset(opts.Offset).Find(&bookmarks)
+	if dbResult.Error != nil {
+		return nil, dbResult.Error
+	}
+
+	result := &GetBookmarksResult{}
+	if opts.Count != -1 && len(bookmarks) > opts.Count {
+		result.HasMore = true
+		bookmarks = bookmarks[:opts.Count]
+	}
+
+	result.Bookmarks = bookmarks
+	return result, nil
+}
+
+func (s *BookmarkStorageImpl) CountUserBookmarks(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	result := s.db.WithContext(ctx).Where("user_id = ?", userID).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (s *BookmarkStorageImpl) DeleteBookmark(ctx context.Context, userID int64, id uint) (int64, error) {
+	result := s.db.WithContext(ctx).Where("user_id = ? and id = ?", userID, id).Delete(&model.Bookmark{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+func TestBookmarkStorageImpl(t *testing.T) {
+	db := GetTestDB(t)
+	s := NewBookmarkStorageImpl(db)
+	ctx := context.Background()
+	s.Init(ctx)
+
+	bookmarks := []*model.Bookmark{
+		{UserID: 100, ChatID: -100, HashID: "hash1", Title: "title1", RawLink: "https://example.com/1"},
+		{UserID: 100, ChatID: 100, HashID: "hash2", Title: "title2", RawLink: "https://example.com/2"},
+		{UserID: 101, ChatID: 101, HashID: "hash1", Title: "title1", RawLink: "https://example.com/1"},
+	}
+
+	t.Run(
+		"add bookmark", func(t *testing.T) {
+			for _, bookmark := range bookmarks {
+				err := s.AddBookmark(ctx, bookmark)
+				assert.Nil(t, err)
+			}
+
+			exist, err := s.BookmarkExist(ctx, 100, "hash1")
+			assert.Nil(t, err)
+			assert.True(t, exist)
+
+			exist, err = s.BookmarkExist(ctx, 101, "hash2")
+			assert.Nil(t, err)
+			assert.False(t, exist)
+
+			count, err := s.CountUserBookmarks(ctx, 100)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), count)
+		},
+	)
+
+	t.Run(
+		"get bookmarks", func(t *testing.T) {
+			result, err := s.GetBookmarksByUserID(ctx, 100, &GetBookmarksOptions{Count: 1})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(result.Bookmarks))
+			assert.True(t, result.HasMore)
+
+			result, err = s.GetBookmarksByUserID(ctx, 100, &GetBookmarksOptions{Count: 1, Offset: 1})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(result.Bookmarks))
+			assert.False(t, result.HasMore)
+
+			result, err = s.GetBookmarksByUserID(ctx, 100, &GetBookmarksOptions{Count: -1})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(result.Bookmarks))
+			assert.False(t, result.HasMore)
+		},
+	)
+
+	t.Run(
+		"del bookmark", func(t *testing.T) {
+			got, err := s.DeleteBookmark(ctx, 101, bookmarks[0].ID)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(0), got)
+
+			got, err = s.DeleteBookmark(ctx, 100, bookmarks[0].ID)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), got)
+
+			exist, err := s.BookmarkExist(ctx, 100, "hash1")
+			assert.Nil(t, err)
+			assert.False(t, exist)
+		},
+	)
+}
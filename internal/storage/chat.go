@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+type ChatStorageImpl struct {
+	db *gorm.DB
+}
+
+func NewChatStorageImpl(db *gorm.DB) *ChatStorageImpl {
+	return &ChatStorageImpl{db: db}
+}
+
+func (s *ChatStorageImpl) Init(ctx context.Context) error {
+	return s.db.Migrator().AutoMigrate(&model.Chat{})
+}
+
+func (s *ChatStorageImpl) UpsertChat(ctx context.Context, chat *model.Chat) error {
+	columns := []string{"type", "title", "username", "language_code", "left_at", "updated_at"}
+	if chat.RegisteredBy != 0 {
+		columns = append(columns, "registered_by")
+	}
+	return s.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns(columns),
+		},
+	).Create(chat).Error
+}
+
+func (s *ChatStorageImpl) GetChat(ctx context.Context, id int64) (*model.Chat, error) {
+	chat := &model.Chat{}
+	result := s.db.WithContext(ctx).Where("id = ?", id).First(chat)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return chat, nil
+}
+
+func (s *ChatStorageImpl) GetActiveChats(ctx context.Context, chatType string, registeredBy int64) ([]*model.Chat, error) {
+	var chats []*model.Chat
+	db := s.db.WithContext(ctx).Where("type = ? and left_at is null", chatType)
+	if registeredBy != 0 {
+		db = db.Where("registered_by = ?", registeredBy)
+	}
+	result := db.Order("id").Find(&chats)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return chats, nil
+}
+
+func (s *ChatStorageImpl) SetChatLeftAt(ctx context.Context, id int64, leftAt *time.Time) error {
+	result := s.db.WithContext(ctx).Model(&model.Chat{}).Where("id = ?", id).Update("left_at", leftAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *ChatStorageImpl) MoveChat(ctx context.Context, fromChatID int64, toChatID int64) (int64, error) {
+	var moved int64
+	err := s.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			var err error
+			if moved, err = moveChatSubscriptions(tx, fromChatID, toChatID); err != nil {
+				return err
+			}
+			if err := moveChatTags(tx, fromChatID, toChatID); err != nil {
+				return err
+			}
+			return movePreference(tx, fromChatID, toChatID)
+		},
+	)
+	return moved, err
+}
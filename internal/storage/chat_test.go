@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+func TestChatStorageImpl(t *testing.T) {
+	db := GetTestDB(t)
+	s := NewChatStorageImpl(db)
+	ctx := context.Background()
+	s.Init(ctx)
+
+	chat := &model.Chat{ID: -5000, Type: "group", Title: "group", TimeZone: "Asia/Tokyo"}
+
+	t.Run(
+		"upsert chat", func(t *testing.T) {
+			err := s.UpsertChat(ctx, chat)
+			assert.Nil(t, err)
+
+			err = s.UpsertChat(ctx, &model.Chat{ID: -5000, Type: "supergroup", Title: "renamed"})
+			assert.Nil(t, err)
+
+			got, err := s.GetChat(ctx, chat.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, "supergroup", got.Type)
+			assert.Equal(t, "renamed", got.Title)
+			assert.Equal(t, "Asia/Tokyo", got.TimeZone)
+		},
+	)
+
+	t.Run(
+		"set chat left at", func(t *testing.T) {
+			leftAt := time.Now()
+			err := s.SetChatLeftAt(ctx, chat.ID, &leftAt)
+			assert.Nil(t, err)
+			got, err := s.GetChat(ctx, chat.ID)
+			assert.Nil(t, err)
+			assert.NotNil(t, got.LeftAt)
+
+			// the bot is back once the chat is saved again
+			err = s.UpsertChat(ctx, &model.Chat{ID: -5000, Type: "supergroup", Title: "renamed"})
+			assert.Nil(t, err)
+			got, err = s.GetChat(ctx, chat.ID)
+			assert.Nil(t, err)
+			assert.Nil(t, got.LeftAt)
+
+			err = s.SetChatLeftAt(ctx, -5001, &leftAt)
+			assert.ErrorIs(t, err, ErrRecordNotFound)
+		},
+	)
+
+	t.Run(
+		"get active chats", func(t *testing.T) {
+			err := s.UpsertChat(ctx, &model.Chat{ID: -5100, Type: "channel", Title: "news", RegisteredBy: 5100})
+			assert.Nil(t, err)
+			err = s.UpsertChat(ctx, &model.Chat{ID: -5101, Type: "channel", Title: "old"})
+			assert.Nil(t, err)
+			err = s.UpsertChat(ctx, &model.Chat{ID: -5102, Type: "channel", Title: "other", RegisteredBy: 5101})
+			assert.Nil(t, err)
+			leftAt := time.Now()
+			err = s.SetChatLeftAt(ctx, -5101, &leftAt)
+			assert.Nil(t, err)
+
+			chats, err := s.GetActiveChats(ctx, "channel", 0)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(chats))
+
+			// an update without the user keeps the one who registered the channel
+			err = s.UpsertChat(ctx, &model.Chat{ID: -5100, Type: "channel", Title: "news"})
+			assert.Nil(t, err)
+			chats, err = s.GetActiveChats(ctx, "channel", 5100)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(chats))
+			assert.Equal(t, int64(-5100), chats[0].ID)
+		},
+	)
+
+	t.Run(
+		"move chat into a chat with data", func(t *testing.T) {
+			subscriptionStorage := NewSubscriptionStorageImpl(db)
+			tagStorage := NewTagStorageImpl(db)
+			preferenceStorage := NewPreferenceStorageImpl(db)
+			assert.Nil(t, subscriptionStorage.Init(ctx))
+			assert.Nil(t, tagStorage.Init(ctx))
+			assert.Nil(t, preferenceStorage.Init(ctx))
+
+			from, to := int64(-5200), int64(-1005200)
+			// the subscription tests count every subscription in the shared database
+			t.Cleanup(
+				func() {
+					result, err := subscriptionStorage.GetSubscriptionsByUserID(ctx, to, &GetSubscriptionsOptions{Count: -1})
+					assert.Nil(t, err)
+					for _, sub := range result.Subscriptions {
+						assert.Nil(t, tagStorage.DeleteSubscriptionTags(ctx, sub))
+					}
+					assert.Nil(t, db.Unscoped().Where("user_id in ?", []int64{from, to}).Delete(&model.Subscribe{}).Error)
+				},
+			)
+			subscribe := func(userID int64, sourceID uint, tags ...string) {
+				sub := &model.Subscribe{UserID: userID, SourceID: sourceID}
+				assert.Nil(t, subscriptionStorage.AddSubscription(ctx, sub))
+				assert.Nil(t, tagStorage.SetSubscriptionTags(ctx, sub, tags))
+			}
+			subscribe(from, 5201, "go")
+			subscribe(from, 5202, "go", "news")
+			subscribe(to, 5202, "go")
+			assert.Nil(t, preferenceStorage.UpsertPreference(ctx, &model.ChatPreference{ChatID: from, Language: "zh"}))
+			assert.Nil(t, preferenceStorage.UpsertPreference(ctx, &model.ChatPreference{ChatID: to, Language: "ja"}))
+
+			moved, err := s.MoveChat(ctx, from, to)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), moved)
+
+			// the source both chats subscribe to keeps the settings and tags of the new chat
+			result, err := subscriptionStorage.GetSubscriptionsByUserID(ctx, to, &GetSubscriptionsOptions{Count: -1})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(result.Subscriptions))
+			result, err = subscriptionStorage.GetSubscriptionsByUserID(ctx, to, &GetSubscriptionsOptions{Count: -1, Tag: "go"})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(result.Subscriptions))
+			counts, err := tagStorage.GetTagCounts(ctx, to)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(counts))
+			counts, err = tagStorage.GetTagCounts(ctx, from)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(counts))
+			preference, err := preferenceStorage.GetPreference(ctx, to)
+			assert.Nil(t, err)
+			assert.Equal(t, "ja", preference.Language)
+
+			// handling the migration twice is harmless
+			moved, err = s.MoveChat(ctx, from, to)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(0), moved)
+		},
+	)
+}
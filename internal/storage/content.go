@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -17,7 +19,10 @@ func NewContentStorageImpl(db *gorm.DB) *ContentStorageImpl {
 }
 
 func (s *ContentStorageImpl) Init(ctx context.Context) error {
-	return s.db.Migrator().AutoMigrate(&model.Content{})
+	if err := s.db.Migrator().AutoMigrate(&model.Content{}); err != nil {
+		return err
+	}
+	return s.initSearchIndex(ctx)
 }
 
 func (s *ContentStorageImpl) DeleteSourceContents(ctx context.Context, sourceID uint) (int64, error) {
@@ -44,3 +49,151 @@ func (s *ContentStorageImpl) HashIDExist(ctx context.Context, hashID string) (bo
 	}
 	return (count > 0), nil
 }
+
+func (s *ContentStorageImpl) GetContentByHashID(ctx context.Context, hashID string) (*model.Content, error) {
+	var content = &model.Content{}
+	result := s.db.WithContext(ctx).Where("hash_id = ?", hashID).First(content)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return content, nil
+}
+
+func (s *ContentStorageImpl) UpdateContent(ctx context.Context, content *model.Content) error {
+	result := s.db.WithContext(ctx).Model(content).Updates(content)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+const (
+	contentFTSTable      = "content_fts"
+	contentFullTextIndex = "idx_contents_search"
+	dialectSQLite        = "sqlite"
+	dialectMysql         = "mysql"
+)
+
+// initSearchIndex creates the full text index of contents, FTS5 on SQLite and FULLTEXT on MySQL
+func (s *ContentStorageImpl) initSearchIndex(ctx context.Context) error {
+	db := s.db.Session(&gorm.Session{NewDB: true}).WithContext(ctx)
+	switch db.Dialector.Name() {
+	case dialectSQLite:
+		var stmts []string
+		if db.Migrator().HasTable(contentFTSTable) {
+			var schema string
+			if err := db.Raw("SELECT sql FROM sqlite_master WHERE name = ?", contentFTSTable).Scan(&schema).Error; err != nil {
+				return err
+			}
+			if strings.Contains(schema, "content_rowid") {
+				return nil
+			}
+			// the first index kept its own copy keyed by hash_id, every delete scanned the whole index
+			stmts = append(
+				stmts,
+				"DROP TRIGGER IF EXISTS content_fts_insert",
+				"DROP TRIGGER IF EXISTS content_fts_delete",
+				"DROP TRIGGER IF EXISTS content_fts_update",
+				"DROP TABLE content_fts",
+			)
+		}
+		// an external content index reading title and text from contents by rowid,
+		// contents has no integer key so its rowid is used, VACUUM may renumber it and needs a rebuild after
+		stmts = append(
+			stmts,
+			"CREATE VIRTUAL TABLE content_fts USING fts5(title, text, content='contents', content_rowid='rowid')",
+			`CREATE TRIGGER content_fts_insert AFTER INSERT ON contents BEGIN
+				INSERT INTO content_fts(rowid, title, text) VALUES (new.rowid, new.title, new.text);
+			END`,
+			`CREATE TRIGGER content_fts_delete AFTER DELETE ON contents BEGIN
+				INSERT INTO content_fts(content_fts, rowid, title, text) VALUES ('delete', old.rowid, old.title, old.text);
+			END`,
+			`CREATE TRIGGER content_fts_update AFTER UPDATE OF title, text ON contents BEGIN
+				INSERT INTO content_fts(content_fts, rowid, title, text) VALUES ('delete', old.rowid, old.title, old.text);
+				INSERT INTO content_fts(rowid, title, text) VALUES (new.rowid, new.title, new.text);
+			END`,
+			// index the contents saved before search existed
+			"INSERT INTO content_fts(content_fts) VALUES ('rebuild')",
+		)
+		return db.Transaction(
+			func(tx *gorm.DB) error {
+				for _, stmt := range stmts {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		)
+	case dialectMysql:
+		if db.Migrator().HasIndex(&model.Content{}, contentFullTextIndex) {
+			return nil
+		}
+		return db.Exec("CREATE FULLTEXT INDEX " + contentFullTextIndex + " ON contents (title, text)").Error
+	}
+	return nil
+}
+
+func (s *ContentStorageImpl) SearchContents(
+	ctx context.Context, opts *SearchContentsOptions,
+) (*SearchContentsResult, error) {
+	if len(opts.SourceIDs) == 0 {
+		return &SearchContentsResult{}, nil
+	}
+
+	db := s.db.WithContext(ctx).Select("contents.*")
+	if terms := strings.Fields(opts.Query); len(terms) > 0 {
+		db = searchTermsCondition(db, terms)
+	}
+	db = db.Where("contents.source_id IN ?", opts.SourceIDs)
+	if !opts.Since.IsZero() {
+		db = db.Where("contents.created_at >= ?", opts.Since)
+	}
+	if !opts.Until.IsZero() {
+		db = db.Where("contents.created_at < ?", opts.Until)
+	}
+
+	count := opts.Count
+	if count != -1 {
+		count += 1
+	}
+	var contents []*model.Content
+	dbResult := db.Order("contents.created_at desc").Limit(count).Offset(opts.Offset).Find(&contents)
+	if dbResult.Error != nil {
+		return nil, dbResult.Error
+	}
+
+	result := &SearchContentsResult{}
+	if opts.Count != -1 && len(contents) > opts.Count {
+		result.HasMore = true
+		contents = contents[:opts.Count]
+	}
+	result.Contents = contents
+	return result, nil
+}
+
+// searchTermsCondition matches contents containing all terms
+func searchTermsCondition(db *gorm.DB, terms []string) *gorm.DB {
+	switch db.Dialector.Name() {
+	case dialectSQLite:
+		// quote every term so user input is never parsed as FTS5 syntax, terms match as prefixes
+		for i := range terms {
+			terms[i] = `"` + strings.ReplaceAll(terms[i], `"`, `""`) + `"*`
+		}
+		return db.Joins("JOIN content_fts ON content_fts.rowid = contents.rowid").
+			Where("content_fts MATCH ?", strings.Join(terms, " "))
+	case dialectMysql:
+		for i := range terms {
+			terms[i] = `+"` + strings.ReplaceAll(terms[i], `"`, "") + `"`
+		}
+		return db.Where("MATCH (contents.title, contents.text) AGAINST (? IN BOOLEAN MODE)", strings.Join(terms, " "))
+	}
+	for _, term := range terms {
+		like := "%" + term + "%"
+		db = db.Where("(contents.title LIKE ? OR contents.text LIKE ?)", like, like)
+	}
+	return db
+}
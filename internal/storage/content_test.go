@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -41,6 +42,79 @@ func TestContentStorageImpl(t *testing.T) {
 		},
 	)
 
+	t.Run(
+		"update content", func(t *testing.T) {
+			content.Title = "title"
+			content.Fingerprint = "fingerprint"
+			err := s.UpdateContent(ctx, content)
+			assert.Nil(t, err)
+
+			got, err := s.GetContentByHashID(ctx, content.HashID)
+			assert.Nil(t, err)
+			assert.Equal(t, content.Title, got.Title)
+			assert.Equal(t, content.Fingerprint, got.Fingerprint)
+
+			got, err = s.GetContentByHashID(ctx, "not exist")
+			assert.Equal(t, ErrRecordNotFound, err)
+			assert.Nil(t, got)
+		},
+	)
+
+	t.Run(
+		"search content", func(t *testing.T) {
+			content3 := &model.Content{
+				SourceID: 2,
+				HashID:   "id3",
+				Title:    "Release notes",
+				Text:     "the sqlite backend gained full-text search",
+			}
+			err := s.AddContent(ctx, content3)
+			assert.Nil(t, err)
+
+			opts := &SearchContentsOptions{Query: "full-text sql", SourceIDs: []uint{1, 2}, Count: 10}
+			got, err := s.SearchContents(ctx, opts)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(got.Contents))
+			assert.Equal(t, content3.HashID, got.Contents[0].HashID)
+
+			// the updated title is indexed
+			opts.Query = "title"
+			got, err = s.SearchContents(ctx, opts)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(got.Contents))
+			assert.Equal(t, content.HashID, got.Contents[0].HashID)
+
+			opts.SourceIDs = []uint{2}
+			got, err = s.SearchContents(ctx, opts)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(got.Contents))
+
+			opts.Query = `release "notes`
+			opts.Since = time.Now().Add(time.Hour)
+			got, err = s.SearchContents(ctx, opts)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(got.Contents))
+
+			opts.Since = time.Time{}
+			got, err = s.SearchContents(ctx, opts)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(got.Contents))
+
+			got, err = s.SearchContents(ctx, &SearchContentsOptions{SourceIDs: []uint{1, 2}, Count: -1})
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(got.Contents))
+
+			_, err = s.DeleteSourceContents(ctx, content3.SourceID)
+			assert.Nil(t, err)
+			got, err = s.SearchContents(ctx, opts)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(got.Contents))
+
+			// the index stays in step with contents after the updates and deletes
+			assert.Nil(t, db.Exec("INSERT INTO content_fts(content_fts, rank) VALUES ('integrity-check', 1)").Error)
+		},
+	)
+
 	t.Run(
 		"del content", func(t *testing.T) {
 			got, err := s.DeleteSourceContents(ctx, content.SourceID)
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+type FetchHistoryStorageImpl struct {
+	db *gorm.DB
+}
+
+func NewFetchHistoryStorageImpl(db *gorm.DB) *FetchHistoryStorageImpl {
+	return &FetchHistoryStorageImpl{db: db}
+}
+
+func (s *FetchHistoryStorageImpl) Init(ctx context.Context) error {
+	return s.db.Migrator().AutoMigrate(&model.SourceFetch{}, &model.FetchCycle{})
+}
+
+func (s *FetchHistoryStorageImpl) AddSourceFetch(ctx context.Context, fetch *model.SourceFetch) error {
+	return s.db.WithContext(ctx).Create(fetch).Error
+}
+
+func (s *FetchHistoryStorageImpl) AddFetchCycle(ctx context.Context, cycle *model.FetchCycle) error {
+	return s.db.WithContext(ctx).Create(cycle).Error
+}
+
+func (s *FetchHistoryStorageImpl) GetSourceFetchStats(
+	ctx context.Context, sourceIDs []uint, since time.Time,
+) ([]*SourceFetchStats, error) {
+	db := s.db.WithContext(ctx).Model(&model.SourceFetch{}).
+		Select(
+			"source_id, count(*) as fetches, "+
+				"sum(case when error <> '' then 1 else 0 end) as failures, "+
+				"sum(new_items) as new_items, avg(duration_ms) as avg_duration_ms",
+		).
+		Where("fetched_at >= ?", since)
+	if sourceIDs != nil {
+		db = db.Where("source_id in ?", sourceIDs)
+	}
+
+	var stats []*SourceFetchStats
+	if err := db.Group("source_id").Order("source_id").Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *FetchHistoryStorageImpl) GetFetchCycles(ctx context.Context, count int) ([]*model.FetchCycle, error) {
+	var cycles []*model.FetchCycle
+	if err := s.db.WithContext(ctx).Order("started_at desc").Limit(count).Find(&cycles).Error; err != nil {
+		return nil, err
+	}
+	return cycles, nil
+}
+
+func (s *FetchHistoryStorageImpl) DeleteFetchHistory(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := s.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			result := tx.Where("fetched_at < ?", before).Delete(&model.SourceFetch{})
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted += result.RowsAffected
+
+			result = tx.Where("started_at < ?", before).Delete(&model.FetchCycle{})
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted += result.RowsAffected
+			return nil
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
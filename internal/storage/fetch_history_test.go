@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+func TestFetchHistoryStorageImpl(t *testing.T) {
+	db := GetTestDB(t)
+	s := NewFetchHistoryStorageImpl(db)
+	ctx := context.Background()
+	s.Init(ctx)
+
+	now := time.Now()
+	fetches := []*model.SourceFetch{
+		{SourceID: 4001, FetchedAt: now.Add(-48 * time.Hour), DurationMs: 100, StatusCode: 200, NewItems: 3},
+		{SourceID: 4001, FetchedAt: now.Add(-time.Hour), DurationMs: 300, StatusCode: 200, NewItems: 1},
+		{SourceID: 4001, FetchedAt: now, DurationMs: 200, StatusCode: 500, Error: "500 Internal Server Error"},
+		{SourceID: 4002, FetchedAt: now, DurationMs: 50, StatusCode: 200},
+	}
+
+	t.Run(
+		"add source fetch", func(t *testing.T) {
+			for _, fetch := range fetches {
+				err := s.AddSourceFetch(ctx, fetch)
+				assert.Nil(t, err)
+			}
+		},
+	)
+
+	t.Run(
+		"get source fetch stats", func(t *testing.T) {
+			stats, err := s.GetSourceFetchStats(ctx, []uint{4001}, now.Add(-24*time.Hour))
+			assert.Nil(t, err)
+			assert.Equal(
+				t, []*SourceFetchStats{
+					{SourceID: 4001, Fetches: 2, Failures: 1, NewItems: 1, AvgDurationMs: 250},
+				}, stats,
+			)
+
+			stats, err = s.GetSourceFetchStats(ctx, nil, now.Add(-72*time.Hour))
+			assert.Nil(t, err)
+			assert.Len(t, stats, 2)
+			assert.Equal(t, int64(4), stats[0].NewItems)
+		},
+	)
+
+	t.Run(
+		"fetch cycles", func(t *testing.T) {
+			for i := 3; i > 0; i-- {
+				err := s.AddFetchCycle(
+					ctx, &model.FetchCycle{StartedAt: now.Add(-time.Duration(i) * 24 * time.Hour), DurationMs: int64(i)},
+				)
+				assert.Nil(t, err)
+			}
+
+			cycles, err := s.GetFetchCycles(ctx, 2)
+			assert.Nil(t, err)
+			assert.Len(t, cycles, 2)
+			assert.Equal(t, int64(1), cycles[0].DurationMs)
+		},
+	)
+
+	t.Run(
+		"delete fetch history", func(t *testing.T) {
+			deleted, err := s.DeleteFetchHistory(ctx, now.Add(-36*time.Hour))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(3), deleted)
+
+			stats, err := s.GetSourceFetchStats(ctx, []uint{4001}, time.Time{})
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), stats[0].Fetches)
+		},
+	)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+type MessageStorageImpl struct {
+	db *gorm.DB
+}
+
+func NewMessageStorageImpl(db *gorm.DB) *MessageStorageImpl {
+	return &MessageStorageImpl{db: db.Model(&model.Message{})}
+}
+
+func (s *MessageStorageImpl) Init(ctx context.Context) error {
+	return s.db.Migrator().AutoMigrate(&model.Message{})
+}
+
+func (s *MessageStorageImpl) AddMessage(ctx context.Context, message *model.Message) error {
+	result := s.db.WithContext(ctx).Create(message)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (s *MessageStorageImpl) GetMessagesByHashID(ctx context.Context, hashID string) ([]*model.Message, error) {
+	var messages []*model.Message
+	result := s.db.WithContext(ctx).Where("hash_id = ?", hashID).Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return messages, nil
+}
+
+func (s *MessageStorageImpl) DeleteSourceMessages(ctx context.Context, sourceID uint) (int64, error) {
+	result := s.db.WithContext(ctx).Where("source_id = ?", sourceID).Delete(&model.Message{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (s *MessageStorageImpl) DeleteChatMessages(ctx context.Context, chatID int64, sourceID uint) (int64, error) {
+	db := s.db.WithContext(ctx).Where("chat_id = ?", chatID)
+	if sourceID != 0 {
+		db = db.Where("source_id = ?", sourceID)
+	}
+	result := db.Delete(&model.Message{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
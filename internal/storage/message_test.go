@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+func TestMessageStorageImpl(t *testing.T) {
+	db := GetTestDB(t)
+	s := NewMessageStorageImpl(db)
+	ctx := context.Background()
+	s.Init(ctx)
+
+	messages := []*model.Message{
+		{SourceID: 1, HashID: "hash1", ChatID: 100, MessageID: 1},
+		{SourceID: 1, HashID: "hash1", ChatID: 101, MessageID: 7},
+		{SourceID: 1, HashID: "hash2", ChatID: 100, MessageID: 2},
+	}
+
+	t.Run(
+		"add message", func(t *testing.T) {
+			for _, message := range messages {
+				err := s.AddMessage(ctx, message)
+				assert.Nil(t, err)
+			}
+
+			got, err := s.GetMessagesByHashID(ctx, "hash1")
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(got))
+
+			got, err = s.GetMessagesByHashID(ctx, "hash3")
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(got))
+		},
+	)
+
+	t.Run(
+		"del chat message", func(t *testing.T) {
+			other := &model.Message{SourceID: 2, HashID: "hash3", ChatID: 100, MessageID: 3}
+			assert.Nil(t, s.AddMessage(ctx, other))
+
+			got, err := s.DeleteChatMessages(ctx, 100, 2)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), got)
+
+			got, err = s.DeleteChatMessages(ctx, 101, 0)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), got)
+
+			messages, err := s.GetMessagesByHashID(ctx, "hash1")
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(messages))
+			assert.Equal(t, int64(100), messages[0].ChatID)
+		},
+	)
+
+	t.Run(
+		"del message", func(t *testing.T) {
+			got, err := s.DeleteSourceMessages(ctx, 1)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), got)
+
+			messages, err := s.GetMessagesByHashID(ctx, "hash1")
+			assert.Nil(t, err)
+			assert.Equal(t, 0, len(messages))
+		},
+	)
+}
@@ -7,6 +7,7 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/andatoshiki/toshiki-rssbot/internal/model"
 	storage "github.com/andatoshiki/toshiki-rssbot/internal/storage"
@@ -73,18 +74,47 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 	return m.recorder
 }
 
-// CrateUser mocks base method.
-func (m *MockUser) CrateUser(ctx context.Context, user *model.User) error {
+// AddInvite mocks base method.
+func (m *MockUser) AddInvite(ctx context.Context, invite *model.Invite) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CrateUser", ctx, user)
+	ret := m.ctrl.Call(m, "AddInvite", ctx, invite)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CrateUser indicates an expected call of CrateUser.
-func (mr *MockUserMockRecorder) CrateUser(ctx, user interface{}) *gomock.Call {
+// AddInvite indicates an expected call of AddInvite.
+func (mr *MockUserMockRecorder) AddInvite(ctx, invite interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrateUser", reflect.TypeOf((*MockUser)(nil).CrateUser), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddInvite", reflect.TypeOf((*MockUser)(nil).AddInvite), ctx, invite)
+}
+
+// CreateUser mocks base method.
+func (m *MockUser) CreateUser(ctx context.Context, user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserMockRecorder) CreateUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUser)(nil).CreateUser), ctx, user)
+}
+
+// GetAllowedUsers mocks base method.
+func (m *MockUser) GetAllowedUsers(ctx context.Context) ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllowedUsers", ctx)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllowedUsers indicates an expected call of GetAllowedUsers.
+func (mr *MockUserMockRecorder) GetAllowedUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllowedUsers", reflect.TypeOf((*MockUser)(nil).GetAllowedUsers), ctx)
 }
 
 // GetUser mocks base method.
@@ -116,6 +146,158 @@ func (mr *MockUserMockRecorder) Init(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockUser)(nil).Init), ctx)
 }
 
+// SetUserAllowed mocks base method.
+func (m *MockUser) SetUserAllowed(ctx context.Context, id int64, allowed bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserAllowed", ctx, id, allowed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserAllowed indicates an expected call of SetUserAllowed.
+func (mr *MockUserMockRecorder) SetUserAllowed(ctx, id, allowed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserAllowed", reflect.TypeOf((*MockUser)(nil).SetUserAllowed), ctx, id, allowed)
+}
+
+// UpsertUser mocks base method.
+func (m *MockUser) UpsertUser(ctx context.Context, user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUser indicates an expected call of UpsertUser.
+func (mr *MockUserMockRecorder) UpsertUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUser", reflect.TypeOf((*MockUser)(nil).UpsertUser), ctx, user)
+}
+
+// UseInvite mocks base method.
+func (m *MockUser) UseInvite(ctx context.Context, code string, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseInvite", ctx, code, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseInvite indicates an expected call of UseInvite.
+func (mr *MockUserMockRecorder) UseInvite(ctx, code, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseInvite", reflect.TypeOf((*MockUser)(nil).UseInvite), ctx, code, userID)
+}
+
+// MockChat is a mock of Chat interface.
+type MockChat struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatMockRecorder
+}
+
+// MockChatMockRecorder is the mock recorder for MockChat.
+type MockChatMockRecorder struct {
+	mock *MockChat
+}
+
+// NewMockChat creates a new mock instance.
+func NewMockChat(ctrl *gomock.Controller) *MockChat {
+	mock := &MockChat{ctrl: ctrl}
+	mock.recorder = &MockChatMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChat) EXPECT() *MockChatMockRecorder {
+	return m.recorder
+}
+
+// GetActiveChats mocks base method.
+func (m *MockChat) GetActiveChats(ctx context.Context, chatType string, registeredBy int64) ([]*model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveChats", ctx, chatType, registeredBy)
+	ret0, _ := ret[0].([]*model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveChats indicates an expected call of GetActiveChats.
+func (mr *MockChatMockRecorder) GetActiveChats(ctx, chatType, registeredBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveChats", reflect.TypeOf((*MockChat)(nil).GetActiveChats), ctx, chatType, registeredBy)
+}
+
+// GetChat mocks base method.
+func (m *MockChat) GetChat(ctx context.Context, id int64) (*model.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChat", ctx, id)
+	ret0, _ := ret[0].(*model.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChat indicates an expected call of GetChat.
+func (mr *MockChatMockRecorder) GetChat(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChat", reflect.TypeOf((*MockChat)(nil).GetChat), ctx, id)
+}
+
+// Init mocks base method.
+func (m *MockChat) Init(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockChatMockRecorder) Init(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockChat)(nil).Init), ctx)
+}
+
+// MoveChat mocks base method.
+func (m *MockChat) MoveChat(ctx context.Context, fromChatID, toChatID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveChat", ctx, fromChatID, toChatID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveChat indicates an expected call of MoveChat.
+func (mr *MockChatMockRecorder) MoveChat(ctx, fromChatID, toChatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveChat", reflect.TypeOf((*MockChat)(nil).MoveChat), ctx, fromChatID, toChatID)
+}
+
+// SetChatLeftAt mocks base method.
+func (m *MockChat) SetChatLeftAt(ctx context.Context, id int64, leftAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChatLeftAt", ctx, id, leftAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetChatLeftAt indicates an expected call of SetChatLeftAt.
+func (mr *MockChatMockRecorder) SetChatLeftAt(ctx, id, leftAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChatLeftAt", reflect.TypeOf((*MockChat)(nil).SetChatLeftAt), ctx, id, leftAt)
+}
+
+// UpsertChat mocks base method.
+func (m *MockChat) UpsertChat(ctx context.Context, chat *model.Chat) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertChat", ctx, chat)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertChat indicates an expected call of UpsertChat.
+func (mr *MockChatMockRecorder) UpsertChat(ctx, chat interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertChat", reflect.TypeOf((*MockChat)(nil).UpsertChat), ctx, chat)
+}
+
 // MockSource is a mock of Source interface.
 type MockSource struct {
 	ctrl     *gomock.Controller
@@ -167,6 +349,21 @@ func (mr *MockSourceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSource)(nil).Delete), ctx, id)
 }
 
+// GetOrphanSources mocks base method.
+func (m *MockSource) GetOrphanSources(ctx context.Context, createdBefore time.Time) ([]*model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrphanSources", ctx, createdBefore)
+	ret0, _ := ret[0].([]*model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrphanSources indicates an expected call of GetOrphanSources.
+func (mr *MockSourceMockRecorder) GetOrphanSources(ctx, createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrphanSources", reflect.TypeOf((*MockSource)(nil).GetOrphanSources), ctx, createdBefore)
+}
+
 // GetSource mocks base method.
 func (m *MockSource) GetSource(ctx context.Context, id uint) (*model.Source, error) {
 	m.ctrl.T.Helper()
@@ -322,6 +519,36 @@ func (mr *MockSubscriptionMockRecorder) DeleteSubscription(ctx, userID, sourceID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockSubscription)(nil).DeleteSubscription), ctx, userID, sourceID)
 }
 
+// GetChatSubscriptionCounts mocks base method.
+func (m *MockSubscription) GetChatSubscriptionCounts(ctx context.Context) ([]*storage.ChatSubscriptionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChatSubscriptionCounts", ctx)
+	ret0, _ := ret[0].([]*storage.ChatSubscriptionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatSubscriptionCounts indicates an expected call of GetChatSubscriptionCounts.
+func (mr *MockSubscriptionMockRecorder) GetChatSubscriptionCounts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatSubscriptionCounts", reflect.TypeOf((*MockSubscription)(nil).GetChatSubscriptionCounts), ctx)
+}
+
+// GetRemovedSubscriptions mocks base method.
+func (m *MockSubscription) GetRemovedSubscriptions(ctx context.Context, before time.Time) ([]*model.Subscribe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemovedSubscriptions", ctx, before)
+	ret0, _ := ret[0].([]*model.Subscribe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRemovedSubscriptions indicates an expected call of GetRemovedSubscriptions.
+func (mr *MockSubscriptionMockRecorder) GetRemovedSubscriptions(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemovedSubscriptions", reflect.TypeOf((*MockSubscription)(nil).GetRemovedSubscriptions), ctx, before)
+}
+
 // GetSubscription mocks base method.
 func (m *MockSubscription) GetSubscription(ctx context.Context, userID int64, sourceID uint) (*model.Subscribe, error) {
 	m.ctrl.T.Helper()
@@ -381,6 +608,51 @@ func (mr *MockSubscriptionMockRecorder) Init(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockSubscription)(nil).Init), ctx)
 }
 
+// PurgeSubscriptions mocks base method.
+func (m *MockSubscription) PurgeSubscriptions(ctx context.Context, ids []uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeSubscriptions", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeSubscriptions indicates an expected call of PurgeSubscriptions.
+func (mr *MockSubscriptionMockRecorder) PurgeSubscriptions(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeSubscriptions", reflect.TypeOf((*MockSubscription)(nil).PurgeSubscriptions), ctx, ids)
+}
+
+// RemoveSubscriptions mocks base method.
+func (m *MockSubscription) RemoveSubscriptions(ctx context.Context, userID int64, sourceID uint, removalID uint32, removedAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSubscriptions", ctx, userID, sourceID, removalID, removedAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveSubscriptions indicates an expected call of RemoveSubscriptions.
+func (mr *MockSubscriptionMockRecorder) RemoveSubscriptions(ctx, userID, sourceID, removalID, removedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSubscriptions", reflect.TypeOf((*MockSubscription)(nil).RemoveSubscriptions), ctx, userID, sourceID, removalID, removedAt)
+}
+
+// RestoreSubscriptions mocks base method.
+func (m *MockSubscription) RestoreSubscriptions(ctx context.Context, userID int64, removalID uint32, removedAfter time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSubscriptions", ctx, userID, removalID, removedAfter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSubscriptions indicates an expected call of RestoreSubscriptions.
+func (mr *MockSubscriptionMockRecorder) RestoreSubscriptions(ctx, userID, removalID, removedAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSubscriptions", reflect.TypeOf((*MockSubscription)(nil).RestoreSubscriptions), ctx, userID, removalID, removedAfter)
+}
+
 // SubscriptionExist mocks base method.
 func (m *MockSubscription) SubscriptionExist(ctx context.Context, userID int64, sourceID uint) (bool, error) {
 	m.ctrl.T.Helper()
@@ -424,6 +696,131 @@ func (mr *MockSubscriptionMockRecorder) UpsertSubscription(ctx, userID, sourceID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSubscription", reflect.TypeOf((*MockSubscription)(nil).UpsertSubscription), ctx, userID, sourceID, newSubscription)
 }
 
+// MockTag is a mock of Tag interface.
+type MockTag struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagMockRecorder
+}
+
+// MockTagMockRecorder is the mock recorder for MockTag.
+type MockTagMockRecorder struct {
+	mock *MockTag
+}
+
+// NewMockTag creates a new mock instance.
+func NewMockTag(ctrl *gomock.Controller) *MockTag {
+	mock := &MockTag{ctrl: ctrl}
+	mock.recorder = &MockTagMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTag) EXPECT() *MockTagMockRecorder {
+	return m.recorder
+}
+
+// DeleteSubscriptionTags mocks base method.
+func (m *MockTag) DeleteSubscriptionTags(ctx context.Context, subscription *model.Subscribe) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscriptionTags", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscriptionTags indicates an expected call of DeleteSubscriptionTags.
+func (mr *MockTagMockRecorder) DeleteSubscriptionTags(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionTags", reflect.TypeOf((*MockTag)(nil).DeleteSubscriptionTags), ctx, subscription)
+}
+
+// DeleteTag mocks base method.
+func (m *MockTag) DeleteTag(ctx context.Context, userID int64, name string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, userID, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockTagMockRecorder) DeleteTag(ctx, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTag)(nil).DeleteTag), ctx, userID, name)
+}
+
+// GetTag mocks base method.
+func (m *MockTag) GetTag(ctx context.Context, userID int64, name string) (*model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, userID, name)
+	ret0, _ := ret[0].(*model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockTagMockRecorder) GetTag(ctx, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockTag)(nil).GetTag), ctx, userID, name)
+}
+
+// GetTagCounts mocks base method.
+func (m *MockTag) GetTagCounts(ctx context.Context, userID int64) ([]*storage.TagCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagCounts", ctx, userID)
+	ret0, _ := ret[0].([]*storage.TagCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagCounts indicates an expected call of GetTagCounts.
+func (mr *MockTagMockRecorder) GetTagCounts(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagCounts", reflect.TypeOf((*MockTag)(nil).GetTagCounts), ctx, userID)
+}
+
+// Init mocks base method.
+func (m *MockTag) Init(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockTagMockRecorder) Init(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockTag)(nil).Init), ctx)
+}
+
+// MoveTag mocks base method.
+func (m *MockTag) MoveTag(ctx context.Context, userID int64, from, to string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTag", ctx, userID, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTag indicates an expected call of MoveTag.
+func (mr *MockTagMockRecorder) MoveTag(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTag", reflect.TypeOf((*MockTag)(nil).MoveTag), ctx, userID, from, to)
+}
+
+// SetSubscriptionTags mocks base method.
+func (m *MockTag) SetSubscriptionTags(ctx context.Context, subscription *model.Subscribe, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSubscriptionTags", ctx, subscription, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSubscriptionTags indicates an expected call of SetSubscriptionTags.
+func (mr *MockTagMockRecorder) SetSubscriptionTags(ctx, subscription, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubscriptionTags", reflect.TypeOf((*MockTag)(nil).SetSubscriptionTags), ctx, subscription, names)
+}
+
 // MockContent is a mock of Content interface.
 type MockContent struct {
 	ctrl     *gomock.Controller
@@ -476,6 +873,21 @@ func (mr *MockContentMockRecorder) DeleteSourceContents(ctx, sourceID interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSourceContents", reflect.TypeOf((*MockContent)(nil).DeleteSourceContents), ctx, sourceID)
 }
 
+// GetContentByHashID mocks base method.
+func (m *MockContent) GetContentByHashID(ctx context.Context, hashID string) (*model.Content, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContentByHashID", ctx, hashID)
+	ret0, _ := ret[0].(*model.Content)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContentByHashID indicates an expected call of GetContentByHashID.
+func (mr *MockContentMockRecorder) GetContentByHashID(ctx, hashID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContentByHashID", reflect.TypeOf((*MockContent)(nil).GetContentByHashID), ctx, hashID)
+}
+
 // HashIDExist mocks base method.
 func (m *MockContent) HashIDExist(ctx context.Context, hashID string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -504,3 +916,415 @@ func (mr *MockContentMockRecorder) Init(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockContent)(nil).Init), ctx)
 }
+
+// SearchContents mocks base method.
+func (m *MockContent) SearchContents(ctx context.Context, opts *storage.SearchContentsOptions) (*storage.SearchContentsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchContents", ctx, opts)
+	ret0, _ := ret[0].(*storage.SearchContentsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchContents indicates an expected call of SearchContents.
+func (mr *MockContentMockRecorder) SearchContents(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchContents", reflect.TypeOf((*MockContent)(nil).SearchContents), ctx, opts)
+}
+
+// UpdateContent mocks base method.
+func (m *MockContent) UpdateContent(ctx context.Context, content *model.Content) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContent", ctx, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContent indicates an expected call of UpdateContent.
+func (mr *MockContentMockRecorder) UpdateContent(ctx, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContent", reflect.TypeOf((*MockContent)(nil).UpdateContent), ctx, content)
+}
+
+// MockPreference is a mock of Preference interface.
+type MockPreference struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreferenceMockRecorder
+}
+
+// MockPreferenceMockRecorder is the mock recorder for MockPreference.
+type MockPreferenceMockRecorder struct {
+	mock *MockPreference
+}
+
+// NewMockPreference creates a new mock instance.
+func NewMockPreference(ctrl *gomock.Controller) *MockPreference {
+	mock := &MockPreference{ctrl: ctrl}
+	mock.recorder = &MockPreferenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreference) EXPECT() *MockPreferenceMockRecorder {
+	return m.recorder
+}
+
+// GetPreference mocks base method.
+func (m *MockPreference) GetPreference(ctx context.Context, chatID int64) (*model.ChatPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreference", ctx, chatID)
+	ret0, _ := ret[0].(*model.ChatPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreference indicates an expected call of GetPreference.
+func (mr *MockPreferenceMockRecorder) GetPreference(ctx, chatID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreference", reflect.TypeOf((*MockPreference)(nil).GetPreference), ctx, chatID)
+}
+
+// Init mocks base method.
+func (m *MockPreference) Init(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockPreferenceMockRecorder) Init(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockPreference)(nil).Init), ctx)
+}
+
+// UpsertPreference mocks base method.
+func (m *MockPreference) UpsertPreference(ctx context.Context, preference *model.ChatPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPreference", ctx, preference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertPreference indicates an expected call of UpsertPreference.
+func (mr *MockPreferenceMockRecorder) UpsertPreference(ctx, preference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPreference", reflect.TypeOf((*MockPreference)(nil).UpsertPreference), ctx, preference)
+}
+
+// MockBookmark is a mock of Bookmark interface.
+type MockBookmark struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookmarkMockRecorder
+}
+
+// MockBookmarkMockRecorder is the mock recorder for MockBookmark.
+type MockBookmarkMockRecorder struct {
+	mock *MockBookmark
+}
+
+// NewMockBookmark creates a new mock instance.
+func NewMockBookmark(ctrl *gomock.Controller) *MockBookmark {
+	mock := &MockBookmark{ctrl: ctrl}
+	mock.recorder = &MockBookmarkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookmark) EXPECT() *MockBookmarkMockRecorder {
+	return m.recorder
+}
+
+// AddBookmark mocks base method.
+func (m *MockBookmark) AddBookmark(ctx context.Context, bookmark *model.Bookmark) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBookmark", ctx, bookmark)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBookmark indicates an expected call of AddBookmark.
+func (mr *MockBookmarkMockRecorder) AddBookmark(ctx, bookmark interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBookmark", reflect.TypeOf((*MockBookmark)(nil).AddBookmark), ctx, bookmark)
+}
+
+// BookmarkExist mocks base method.
+func (m *MockBookmark) BookmarkExist(ctx context.Context, userID int64, hashID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookmarkExist", ctx, userID, hashID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookmarkExist indicates an expected call of BookmarkExist.
+func (mr *MockBookmarkMockRecorder) BookmarkExist(ctx, userID, hashID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookmarkExist", reflect.TypeOf((*MockBookmark)(nil).BookmarkExist), ctx, userID, hashID)
+}
+
+// CountUserBookmarks mocks base method.
+func (m *MockBookmark) CountUserBookmarks(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserBookmarks", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserBookmarks indicates an expected call of CountUserBookmarks.
+func (mr *MockBookmarkMockRecorder) CountUserBookmarks(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserBookmarks", reflect.TypeOf((*MockBookmark)(nil).CountUserBookmarks), ctx, userID)
+}
+
+// DeleteBookmark mocks base method.
+func (m *MockBookmark) DeleteBookmark(ctx context.Context, userID int64, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookmark", ctx, userID, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBookmark indicates an expected call of DeleteBookmark.
+func (mr *MockBookmarkMockRecorder) DeleteBookmark(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookmark", reflect.TypeOf((*MockBookmark)(nil).DeleteBookmark), ctx, userID, id)
+}
+
+// GetBookmarksByUserID mocks base method.
+func (m *MockBookmark) GetBookmarksByUserID(ctx context.Context, userID int64, opts *storage.GetBookmarksOptions) (*storage.GetBookmarksResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarksByUserID", ctx, userID, opts)
+	ret0, _ := ret[0].(*storage.GetBookmarksResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookmarksByUserID indicates an expected call of GetBookmarksByUserID.
+func (mr *MockBookmarkMockRecorder) GetBookmarksByUserID(ctx, userID, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarksByUserID", reflect.TypeOf((*MockBookmark)(nil).GetBookmarksByUserID), ctx, userID, opts)
+}
+
+// Init mocks base method.
+func (m *MockBookmark) Init(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockBookmarkMockRecorder) Init(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockBookmark)(nil).Init), ctx)
+}
+
+// MockMessage is a mock of Message interface.
+type MockMessage struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageMockRecorder
+}
+
+// MockMessageMockRecorder is the mock recorder for MockMessage.
+type MockMessageMockRecorder struct {
+	mock *MockMessage
+}
+
+// NewMockMessage creates a new mock instance.
+func NewMockMessage(ctrl *gomock.Controller) *MockMessage {
+	mock := &MockMessage{ctrl: ctrl}
+	mock.recorder = &MockMessageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessage) EXPECT() *MockMessageMockRecorder {
+	return m.recorder
+}
+
+// AddMessage mocks base method.
+func (m *MockMessage) AddMessage(ctx context.Context, message *model.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMessage", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMessage indicates an expected call of AddMessage.
+func (mr *MockMessageMockRecorder) AddMessage(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMessage", reflect.TypeOf((*MockMessage)(nil).AddMessage), ctx, message)
+}
+
+// DeleteChatMessages mocks base method.
+func (m *MockMessage) DeleteChatMessages(ctx context.Context, chatID int64, sourceID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChatMessages", ctx, chatID, sourceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChatMessages indicates an expected call of DeleteChatMessages.
+func (mr *MockMessageMockRecorder) DeleteChatMessages(ctx, chatID, sourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChatMessages", reflect.TypeOf((*MockMessage)(nil).DeleteChatMessages), ctx, chatID, sourceID)
+}
+
+// DeleteSourceMessages mocks base method.
+func (m *MockMessage) DeleteSourceMessages(ctx context.Context, sourceID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSourceMessages", ctx, sourceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSourceMessages indicates an expected call of DeleteSourceMessages.
+func (mr *MockMessageMockRecorder) DeleteSourceMessages(ctx, sourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSourceMessages", reflect.TypeOf((*MockMessage)(nil).DeleteSourceMessages), ctx, sourceID)
+}
+
+// GetMessagesByHashID mocks base method.
+func (m *MockMessage) GetMessagesByHashID(ctx context.Context, hashID string) ([]*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesByHashID", ctx, hashID)
+	ret0, _ := ret[0].([]*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesByHashID indicates an expected call of GetMessagesByHashID.
+func (mr *MockMessageMockRecorder) GetMessagesByHashID(ctx, hashID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByHashID", reflect.TypeOf((*MockMessage)(nil).GetMessagesByHashID), ctx, hashID)
+}
+
+// Init mocks base method.
+func (m *MockMessage) Init(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockMessageMockRecorder) Init(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockMessage)(nil).Init), ctx)
+}
+
+// MockFetchHistory is a mock of FetchHistory interface.
+type MockFetchHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockFetchHistoryMockRecorder
+}
+
+// MockFetchHistoryMockRecorder is the mock recorder for MockFetchHistory.
+type MockFetchHistoryMockRecorder struct {
+	mock *MockFetchHistory
+}
+
+// NewMockFetchHistory creates a new mock instance.
+func NewMockFetchHistory(ctrl *gomock.Controller) *MockFetchHistory {
+	mock := &MockFetchHistory{ctrl: ctrl}
+	mock.recorder = &MockFetchHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFetchHistory) EXPECT() *MockFetchHistoryMockRecorder {
+	return m.recorder
+}
+
+// AddFetchCycle mocks base method.
+func (m *MockFetchHistory) AddFetchCycle(ctx context.Context, cycle *model.FetchCycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFetchCycle", ctx, cycle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFetchCycle indicates an expected call of AddFetchCycle.
+func (mr *MockFetchHistoryMockRecorder) AddFetchCycle(ctx, cycle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFetchCycle", reflect.TypeOf((*MockFetchHistory)(nil).AddFetchCycle), ctx, cycle)
+}
+
+// AddSourceFetch mocks base method.
+func (m *MockFetchHistory) AddSourceFetch(ctx context.Context, fetch *model.SourceFetch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSourceFetch", ctx, fetch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSourceFetch indicates an expected call of AddSourceFetch.
+func (mr *MockFetchHistoryMockRecorder) AddSourceFetch(ctx, fetch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSourceFetch", reflect.TypeOf((*MockFetchHistory)(nil).AddSourceFetch), ctx, fetch)
+}
+
+// DeleteFetchHistory mocks base method.
+func (m *MockFetchHistory) DeleteFetchHistory(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFetchHistory", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFetchHistory indicates an expected call of DeleteFetchHistory.
+func (mr *MockFetchHistoryMockRecorder) DeleteFetchHistory(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFetchHistory", reflect.TypeOf((*MockFetchHistory)(nil).DeleteFetchHistory), ctx, before)
+}
+
+// GetFetchCycles mocks base method.
+func (m *MockFetchHistory) GetFetchCycles(ctx context.Context, count int) ([]*model.FetchCycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFetchCycles", ctx, count)
+	ret0, _ := ret[0].([]*model.FetchCycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFetchCycles indicates an expected call of GetFetchCycles.
+func (mr *MockFetchHistoryMockRecorder) GetFetchCycles(ctx, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFetchCycles", reflect.TypeOf((*MockFetchHistory)(nil).GetFetchCycles), ctx, count)
+}
+
+// GetSourceFetchStats mocks base method.
+func (m *MockFetchHistory) GetSourceFetchStats(ctx context.Context, sourceIDs []uint, since time.Time) ([]*storage.SourceFetchStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSourceFetchStats", ctx, sourceIDs, since)
+	ret0, _ := ret[0].([]*storage.SourceFetchStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSourceFetchStats indicates an expected call of GetSourceFetchStats.
+func (mr *MockFetchHistoryMockRecorder) GetSourceFetchStats(ctx, sourceIDs, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceFetchStats", reflect.TypeOf((*MockFetchHistory)(nil).GetSourceFetchStats), ctx, sourceIDs, since)
+}
+
+// Init mocks base method.
+func (m *MockFetchHistory) Init(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockFetchHistoryMockRecorder) Init(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockFetchHistory)(nil).Init), ctx)
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+type PreferenceStorageImpl struct {
+	db *gorm.DB
+}
+
+func NewPreferenceStorageImpl(db *gorm.DB) *PreferenceStorageImpl {
+	return &PreferenceStorageImpl{db: db}
+}
+
+func (s *PreferenceStorageImpl) Init(ctx context.Context) error {
+	return s.db.Migrator().AutoMigrate(&model.ChatPreference{})
+}
+
+func (s *PreferenceStorageImpl) GetPreference(ctx context.Context, chatID int64) (*model.ChatPreference, error) {
+	var preference = &model.ChatPreference{}
+	result := s.db.WithContext(ctx).Where("chat_id = ?", chatID).First(preference)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return preference, nil
+}
+
+func (s *PreferenceStorageImpl) UpsertPreference(ctx context.Context, preference *model.ChatPreference) error {
+	result := s.db.WithContext(ctx).Save(preference)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// movePreference moves the preferences of a chat to another chat, kept if the other chat has its own
+func movePreference(tx *gorm.DB, fromChatID int64, toChatID int64) error {
+	var count int64
+	if err := tx.Model(&model.ChatPreference{}).Where("chat_id = ?", toChatID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return tx.Model(&model.ChatPreference{}).Where("chat_id = ?", fromChatID).Update("chat_id", toChatID).Error
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+func TestPreferenceStorageImpl(t *testing.T) {
+	db := GetTestDB(t)
+	s := NewPreferenceStorageImpl(db)
+	ctx := context.Background()
+	s.Init(ctx)
+
+	preference := &model.ChatPreference{
+		ChatID:             -100123,
+		EnableActionButton: 1,
+	}
+
+	t.Run(
+		"preference not exist", func(t *testing.T) {
+			got, err := s.GetPreference(ctx, preference.ChatID)
+			assert.Equal(t, ErrRecordNotFound, err)
+			assert.Nil(t, got)
+		},
+	)
+
+	t.Run(
+		"upsert preference", func(t *testing.T) {
+			err := s.UpsertPreference(ctx, preference)
+			assert.Nil(t, err)
+
+			got, err := s.GetPreference(ctx, preference.ChatID)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, got.EnableActionButton)
+
+			preference.EnableActionButton = 0
+			err = s.UpsertPreference(ctx, preference)
+			assert.Nil(t, err)
+
+			got, err = s.GetPreference(ctx, preference.ChatID)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, got.EnableActionButton)
+		},
+	)
+
+	t.Run(
+		"move preference", func(t *testing.T) {
+			err := movePreference(s.db.WithContext(ctx), preference.ChatID, -100456)
+			assert.Nil(t, err)
+
+			_, err = s.GetPreference(ctx, preference.ChatID)
+			assert.Equal(t, ErrRecordNotFound, err)
+			got, err := s.GetPreference(ctx, -100456)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(-100456), got.ChatID)
+
+			// the preferences of the other chat are kept
+			err = s.UpsertPreference(ctx, &model.ChatPreference{ChatID: -100789, Language: "ja"})
+			assert.Nil(t, err)
+			err = movePreference(s.db.WithContext(ctx), -100456, -100789)
+			assert.Nil(t, err)
+			got, err = s.GetPreference(ctx, -100789)
+			assert.Nil(t, err)
+			assert.Equal(t, "ja", got.Language)
+		},
+	)
+}
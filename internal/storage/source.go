@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -75,6 +76,17 @@ func (s *SourceStorageImpl) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+func (s *SourceStorageImpl) GetOrphanSources(ctx context.Context, createdBefore time.Time) ([]*model.Source, error) {
+	var sources []*model.Source
+	result := s.db.WithContext(ctx).Where(
+		"created_at < ? and id not in (select source_id from subscribes)", createdBefore,
+	).Find(&sources)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sources, nil
+}
+
 func (s *SourceStorageImpl) UpsertSource(ctx context.Context, sourceID uint, newSource *model.Source) error {
 	newSource.ID = sourceID
 	result := s.db.WithContext(ctx).Where("id = ?", sourceID).Save(newSource)
@@ -5,6 +5,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 )
@@ -23,6 +24,31 @@ type User interface {
 	Storage
 	CreateUser(ctx context.Context, user *model.User) error
 	GetUser(ctx context.Context, id int64) (*model.User, error)
+	// UpsertUser creates a user or updates its profile, the allowlist state is kept
+	UpsertUser(ctx context.Context, user *model.User) error
+	// SetUserAllowed adds a user to the allowlist or removes it, creating the user if missing
+	SetUserAllowed(ctx context.Context, id int64, allowed bool) error
+	GetAllowedUsers(ctx context.Context) ([]*model.User, error)
+	AddInvite(ctx context.Context, invite *model.Invite) error
+	// UseInvite marks an unused invite as used by a user and allowlists the user,
+	// ErrRecordNotFound if the invite does not exist or was used
+	UseInvite(ctx context.Context, code string, userID int64) error
+}
+
+// Chat chat storage interface
+type Chat interface {
+	Storage
+	// UpsertChat creates a chat or updates its profile, the time zone of existing chats is kept
+	// and so is the user who registered it unless another one is set
+	UpsertChat(ctx context.Context, chat *model.Chat) error
+	GetChat(ctx context.Context, id int64) (*model.Chat, error)
+	// GetActiveChats gets the chats of a type the bot was not removed from, registered by a user unless 0
+	GetActiveChats(ctx context.Context, chatType string, registeredBy int64) ([]*model.Chat, error)
+	// SetChatLeftAt records when the bot was blocked or removed from a chat, nil once it can post again
+	SetChatLeftAt(ctx context.Context, id int64, leftAt *time.Time) error
+	// MoveChat moves the subscriptions, tags and preferences of a chat to another chat in one transaction,
+	// merged into the ones the other chat has already. Returns the number of subscriptions moved
+	MoveChat(ctx context.Context, fromChatID int64, toChatID int64) (int64, error)
 }
 
 // Source subscription source storage interface
@@ -34,6 +60,9 @@ type Source interface {
 	GetSourceByURL(ctx context.Context, url string) (*model.Source, error)
 	Delete(ctx context.Context, id uint) error
 	UpsertSource(ctx context.Context, sourceID uint, newSource *model.Source) error
+	// GetOrphanSources gets the sources created before a time without any subscription,
+	// the removed subscriptions not purged yet count as well
+	GetOrphanSources(ctx context.Context, createdBefore time.Time) ([]*model.Source, error)
 }
 
 type SubscriptionSortType = int
@@ -46,6 +75,7 @@ type GetSubscriptionsOptions struct {
 	Count    int // Number of items to retrieve, -1 to retrieve all
 	Offset   int
 	SortType SubscriptionSortType
+	Tag      string // Only subscriptions with the tag, empty to not filter
 }
 
 type GetSubscriptionsResult struct {
@@ -53,6 +83,11 @@ type GetSubscriptionsResult struct {
 	HasMore       bool
 }
 
+type ChatSubscriptionCount struct {
+	UserID int64
+	Count  int64
+}
+
 type Subscription interface {
 	Storage
 	AddSubscription(ctx context.Context, subscription *model.Subscribe) error
@@ -66,7 +101,21 @@ type Subscription interface {
 	) (*GetSubscriptionsResult, error)
 	CountSubscriptions(ctx context.Context) (int64, error)
 	DeleteSubscription(ctx context.Context, userID int64, sourceID uint) (int64, error)
+	// RemoveSubscriptions soft deletes the subscriptions of a chat at removedAt as the removal removalID,
+	// sourceID 0 removes all of them. Returns the number of subscriptions removed
+	RemoveSubscriptions(
+		ctx context.Context, userID int64, sourceID uint, removalID uint32, removedAt time.Time,
+	) (int64, error)
+	// RestoreSubscriptions restores the subscriptions of a chat removed by the removal removalID after removedAfter.
+	// The sources subscribed again since are skipped, returns the number of subscriptions restored
+	RestoreSubscriptions(ctx context.Context, userID int64, removalID uint32, removedAfter time.Time) (int64, error)
+	// GetRemovedSubscriptions gets the subscriptions removed before a time
+	GetRemovedSubscriptions(ctx context.Context, before time.Time) ([]*model.Subscribe, error)
+	// PurgeSubscriptions deletes removed subscriptions for good
+	PurgeSubscriptions(ctx context.Context, ids []uint) (int64, error)
 	CountSourceSubscriptions(ctx context.Context, sourceID uint) (int64, error)
+	// GetChatSubscriptionCounts gets the chats with subscriptions and their subscription counts, most first
+	GetChatSubscriptionCounts(ctx context.Context) ([]*ChatSubscriptionCount, error)
 	UpdateSubscription(
 		ctx context.Context, userID int64, sourceID uint, newSubscription *model.Subscribe,
 	) error
@@ -75,6 +124,42 @@ type Subscription interface {
 	) error
 }
 
+type TagCount struct {
+	Name  string
+	Count int64 // Number of subscriptions with the tag
+}
+
+// Tag subscription tag storage interface, tag names are expected to be normalized
+type Tag interface {
+	Storage
+	// SetSubscriptionTags replaces the tags of a subscription, creating the tags missing in the chat
+	SetSubscriptionTags(ctx context.Context, subscription *model.Subscribe, names []string) error
+	// DeleteSubscriptionTags removes the tags of a subscription
+	DeleteSubscriptionTags(ctx context.Context, subscription *model.Subscribe) error
+	GetTag(ctx context.Context, userID int64, name string) (*model.Tag, error)
+	// GetTagCounts gets the tags of a chat with their subscription counts, ordered by name
+	GetTagCounts(ctx context.Context, userID int64) ([]*TagCount, error)
+	// MoveTag moves the subscriptions of a tag to another one, which is renamed from the tag if it does not exist.
+	// Returns the number of subscriptions moved
+	MoveTag(ctx context.Context, userID int64, from string, to string) (int64, error)
+	// DeleteTag removes a tag from all subscriptions of a chat, returns the number of subscriptions affected
+	DeleteTag(ctx context.Context, userID int64, name string) (int64, error)
+}
+
+type SearchContentsOptions struct {
+	Query     string    // Empty to match everything
+	SourceIDs []uint    // Sources to search in
+	Since     time.Time // Zero to not limit
+	Until     time.Time // Zero to not limit
+	Count     int       // Number of items to retrieve, -1 to retrieve all
+	Offset    int
+}
+
+type SearchContentsResult struct {
+	Contents []*model.Content
+	HasMore  bool
+}
+
 type Content interface {
 	Storage
 	// AddContent adds a new article
@@ -83,4 +168,74 @@ type Content interface {
 	DeleteSourceContents(ctx context.Context, sourceID uint) (int64, error)
 	// HashIDExist checks if an article with the given hash id already exists
 	HashIDExist(ctx context.Context, hashID string) (bool, error)
+	// GetContentByHashID gets an article by its hash id
+	GetContentByHashID(ctx context.Context, hashID string) (*model.Content, error)
+	// UpdateContent updates an existing article
+	UpdateContent(ctx context.Context, content *model.Content) error
+	// SearchContents searches articles by title and text, newest first
+	SearchContents(ctx context.Context, opts *SearchContentsOptions) (*SearchContentsResult, error)
+}
+
+// Preference chat preference storage interface
+type Preference interface {
+	Storage
+	// GetPreference gets the preferences of a chat
+	GetPreference(ctx context.Context, chatID int64) (*model.ChatPreference, error)
+	// UpsertPreference creates or updates the preferences of a chat
+	UpsertPreference(ctx context.Context, preference *model.ChatPreference) error
+}
+
+type GetBookmarksOptions struct {
+	Count  int // Number of items to retrieve, -1 to retrieve all
+	Offset int
+}
+
+type GetBookmarksResult struct {
+	Bookmarks []*model.Bookmark
+	HasMore   bool
+}
+
+// Bookmark read later bookmark storage interface
+type Bookmark interface {
+	Storage
+	AddBookmark(ctx context.Context, bookmark *model.Bookmark) error
+	BookmarkExist(ctx context.Context, userID int64, hashID string) (bool, error)
+	GetBookmarksByUserID(ctx context.Context, userID int64, opts *GetBookmarksOptions) (*GetBookmarksResult, error)
+	CountUserBookmarks(ctx context.Context, userID int64) (int64, error)
+	DeleteBookmark(ctx context.Context, userID int64, id uint) (int64, error)
+}
+
+// Message delivered message storage interface
+type Message interface {
+	Storage
+	// AddMessage records a message delivered to a chat
+	AddMessage(ctx context.Context, message *model.Message) error
+	// GetMessagesByHashID gets all delivered messages of an article
+	GetMessagesByHashID(ctx context.Context, hashID string) ([]*model.Message, error)
+	// DeleteSourceMessages deletes all delivered messages of a subscription source and returns the number of deleted messages
+	DeleteSourceMessages(ctx context.Context, sourceID uint) (int64, error)
+	// DeleteChatMessages deletes the delivered messages of a chat from a source, sourceID 0 for every source,
+	// returns the number of deleted messages
+	DeleteChatMessages(ctx context.Context, chatID int64, sourceID uint) (int64, error)
+}
+
+type SourceFetchStats struct {
+	SourceID      uint
+	Fetches       int64
+	Failures      int64
+	NewItems      int64
+	AvgDurationMs float64
+}
+
+// FetchHistory source fetch history storage interface
+type FetchHistory interface {
+	Storage
+	AddSourceFetch(ctx context.Context, fetch *model.SourceFetch) error
+	AddFetchCycle(ctx context.Context, cycle *model.FetchCycle) error
+	// GetSourceFetchStats aggregates the fetches since a time per source, nil sourceIDs for all sources
+	GetSourceFetchStats(ctx context.Context, sourceIDs []uint, since time.Time) ([]*SourceFetchStats, error)
+	// GetFetchCycles gets the latest fetch cycles, newest first
+	GetFetchCycles(ctx context.Context, count int) ([]*model.FetchCycle, error)
+	// DeleteFetchHistory deletes the fetches and cycles before a time, returns the number of deleted records
+	DeleteFetchHistory(ctx context.Context, before time.Time) (int64, error)
 }
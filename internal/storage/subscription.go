@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -60,9 +61,15 @@ func (s *SubscriptionStorageImpl) GetSubscriptionsByUserID(
 
 	count := s.getSubscriptionsCount(opts)
 	orderBy := s.getSubscriptionsOrderBy(opts)
-	dbResult := s.db.WithContext(ctx).Where(
-		&model.Subscribe{UserID: userID},
-	).Limit(count).Order(orderBy).Offset(opts.Offset).Find(&subscriptions)
+	db := s.db.WithContext(ctx).Where(&model.Subscribe{UserID: userID})
+	if opts.Tag != "" {
+		db = db.Where(
+			"id in (select subscribe_tags.subscribe_id from subscribe_tags "+
+				"join tags on tags.id = subscribe_tags.tag_id where tags.user_id = ? and tags.name = ?)",
+			userID, model.NormalizeTag(opts.Tag),
+		)
+	}
+	dbResult := db.Limit(count).Order(orderBy).Offset(opts.Offset).Find(&subscriptions)
 	if dbResult.Error != nil {
 		if errors.Is(dbResult.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrRecordNotFound
@@ -133,7 +140,7 @@ func (s *SubscriptionStorageImpl) CountSubscriptions(ctx context.Context) (int64
 }
 
 func (s *SubscriptionStorageImpl) DeleteSubscription(ctx context.Context, userID int64, sourceID uint) (int64, error) {
-	result := s.db.WithContext(ctx).Where(
+	result := s.db.WithContext(ctx).Unscoped().Where(
 		"user_id = ? and source_id = ?", userID, sourceID,
 	).Delete(&model.Subscribe{})
 	if result.Error != nil {
@@ -142,6 +149,66 @@ func (s *SubscriptionStorageImpl) DeleteSubscription(ctx context.Context, userID
 	return result.RowsAffected, nil
 }
 
+func (s *SubscriptionStorageImpl) RemoveSubscriptions(
+	ctx context.Context, userID int64, sourceID uint, removalID uint32, removedAt time.Time,
+) (int64, error) {
+	db := s.db.WithContext(ctx).Model(&model.Subscribe{}).Where("user_id = ?", userID)
+	if sourceID != 0 {
+		db = db.Where("source_id = ?", sourceID)
+	}
+	result := db.Updates(map[string]interface{}{"deleted_at": removedAt, "removal_id": removalID})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (s *SubscriptionStorageImpl) RestoreSubscriptions(
+	ctx context.Context, userID int64, removalID uint32, removedAfter time.Time,
+) (int64, error) {
+	var subscribedSourceIDs []uint
+	result := s.db.WithContext(ctx).Model(&model.Subscribe{}).Where("user_id = ?", userID).
+		Pluck("source_id", &subscribedSourceIDs)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	db := s.db.WithContext(ctx).Unscoped().Model(&model.Subscribe{}).Where(
+		"user_id = ? and removal_id = ? and deleted_at >= ?", userID, removalID, removedAfter,
+	)
+	if len(subscribedSourceIDs) > 0 {
+		db = db.Where("source_id not in ?", subscribedSourceIDs)
+	}
+	result = db.Updates(map[string]interface{}{"deleted_at": nil, "removal_id": 0})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (s *SubscriptionStorageImpl) GetRemovedSubscriptions(ctx context.Context, before time.Time) (
+	[]*model.Subscribe, error,
+) {
+	var subscriptions []*model.Subscribe
+	result := s.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before).Find(&subscriptions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subscriptions, nil
+}
+
+func (s *SubscriptionStorageImpl) PurgeSubscriptions(ctx context.Context, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	subscription := &model.Subscribe{}
+	result := s.db.WithContext(ctx).Unscoped().Model(subscription).Where("id in ?", ids).Delete(subscription)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 func (s *SubscriptionStorageImpl) CountSourceSubscriptions(ctx context.Context, sourceID uint) (int64, error) {
 	var count int64
 	result := s.db.WithContext(ctx).Where("source_id = ?", sourceID).Count(&count)
@@ -151,6 +218,49 @@ func (s *SubscriptionStorageImpl) CountSourceSubscriptions(ctx context.Context,
 	return count, nil
 }
 
+func (s *SubscriptionStorageImpl) GetChatSubscriptionCounts(ctx context.Context) ([]*ChatSubscriptionCount, error) {
+	var counts []*ChatSubscriptionCount
+	result := s.db.WithContext(ctx).Select("user_id, count(*) as count").
+		Group("user_id").Order("count desc, user_id").Scan(&counts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return counts, nil
+}
+
+// moveChatSubscriptions moves the subscriptions of a chat to another chat, returns the number of subscriptions moved.
+// The sources the other chat subscribes to already keep the settings and tags it has for them
+func moveChatSubscriptions(tx *gorm.DB, fromUserID int64, toUserID int64) (int64, error) {
+	var subscribedIDs []uint
+	err := tx.Model(&model.Subscribe{}).Where("user_id = ?", toUserID).Pluck("source_id", &subscribedIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(subscribedIDs) > 0 {
+		var droppedIDs []uint
+		err := tx.Unscoped().Model(&model.Subscribe{}).
+			Where("user_id = ? and source_id in ?", fromUserID, subscribedIDs).Pluck("id", &droppedIDs).Error
+		if err != nil {
+			return 0, err
+		}
+		if len(droppedIDs) > 0 {
+			if err := tx.Where("subscribe_id in ?", droppedIDs).Delete(&model.SubscribeTag{}).Error; err != nil {
+				return 0, err
+			}
+			if err := tx.Unscoped().Where("id in ?", droppedIDs).Delete(&model.Subscribe{}).Error; err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// the removed subscriptions move as well, so they can still be undone in the new chat
+	result := tx.Unscoped().Model(&model.Subscribe{}).Where("user_id = ?", fromUserID).Update("user_id", toUserID)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 func (s *SubscriptionStorageImpl) UpdateSubscription(
 	ctx context.Context, userID int64, sourceID uint, newSubscription *model.Subscribe,
 ) error {
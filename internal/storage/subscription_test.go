@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -164,4 +165,161 @@ func TestSubscriptionStorageImpl(t *testing.T) {
 			assert.Equal(t, sub.Tag, subscription.Tag)
 		},
 	)
+
+	t.Run(
+		"filter subscriptions by tag", func(t *testing.T) {
+			subs := []*model.Subscribe{
+				{SourceID: 2001, UserID: 2000, Tag: "#go #news"},
+				{SourceID: 2002, UserID: 2000, Tag: "#golang"},
+				{SourceID: 2003, UserID: 2000, Tag: "#news #go"},
+			}
+			tagStorage := NewTagStorageImpl(db)
+			assert.Nil(t, tagStorage.Init(ctx))
+			for _, sub := range subs {
+				err := s.AddSubscription(ctx, sub)
+				assert.Nil(t, err)
+				err = tagStorage.SetSubscriptionTags(ctx, sub, model.ParseTags(sub.Tag))
+				assert.Nil(t, err)
+			}
+
+			result, err := s.GetSubscriptionsByUserID(ctx, 2000, &GetSubscriptionsOptions{Count: -1, Tag: "go"})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(result.Subscriptions))
+
+			result, err = s.GetSubscriptionsByUserID(ctx, 2000, &GetSubscriptionsOptions{Count: 1, Tag: "#news"})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(result.Subscriptions))
+			assert.True(t, result.HasMore)
+		},
+	)
+	t.Run(
+		"chat subscription counts", func(t *testing.T) {
+			counts, err := s.GetChatSubscriptionCounts(ctx)
+			assert.Nil(t, err)
+			assert.NotEmpty(t, counts)
+			for i := 1; i < len(counts); i++ {
+				assert.GreaterOrEqual(t, counts[i-1].Count, counts[i].Count)
+			}
+			for _, count := range counts {
+				if count.UserID == 2000 {
+					assert.Equal(t, int64(3), count.Count)
+				}
+			}
+		},
+	)
+	t.Run(
+		"move chat subscriptions", func(t *testing.T) {
+			moved, err := moveChatSubscriptions(db.WithContext(ctx), 2000, -1002000)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(3), moved)
+
+			exist, err := s.SubscriptionExist(ctx, -1002000, 2001)
+			assert.Nil(t, err)
+			assert.True(t, exist)
+			exist, err = s.SubscriptionExist(ctx, 2000, 2001)
+			assert.Nil(t, err)
+			assert.False(t, exist)
+		},
+	)
+	t.Run(
+		"remove and restore subscriptions", func(t *testing.T) {
+			for sourceID := uint(5001); sourceID <= 5003; sourceID++ {
+				assert.Nil(t, s.AddSubscription(ctx, &model.Subscribe{UserID: 5000, SourceID: sourceID}))
+			}
+			removedAt := time.Now()
+
+			removed, err := s.RemoveSubscriptions(ctx, 5000, 5001, 1, removedAt)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), removed)
+			exist, err := s.SubscriptionExist(ctx, 5000, 5001)
+			assert.Nil(t, err)
+			assert.False(t, exist)
+
+			// the removals older than the undo period are not restored
+			restored, err := s.RestoreSubscriptions(ctx, 5000, 1, removedAt.Add(time.Minute))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(0), restored)
+			restored, err = s.RestoreSubscriptions(ctx, 5000, 2, removedAt.Add(-time.Minute))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(0), restored)
+			restored, err = s.RestoreSubscriptions(ctx, 5000, 1, removedAt.Add(-time.Minute))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), restored)
+			exist, err = s.SubscriptionExist(ctx, 5000, 5001)
+			assert.Nil(t, err)
+			assert.True(t, exist)
+
+			// remove one, remove all in the same second, then subscribe one of them again before undoing
+			removed, err = s.RemoveSubscriptions(ctx, 5000, 5003, 2, removedAt)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), removed)
+			removed, err = s.RemoveSubscriptions(ctx, 5000, 0, 3, removedAt)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), removed)
+			assert.Nil(t, s.AddSubscription(ctx, &model.Subscribe{UserID: 5000, SourceID: 5002}))
+			restored, err = s.RestoreSubscriptions(ctx, 5000, 3, removedAt.Add(-time.Minute))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), restored)
+			exist, err = s.SubscriptionExist(ctx, 5000, 5003)
+			assert.Nil(t, err)
+			assert.False(t, exist)
+			restored, err = s.RestoreSubscriptions(ctx, 5000, 2, removedAt.Add(-time.Minute))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), restored)
+			count, err := s.CountSourceSubscriptions(ctx, 5002)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), count)
+
+			removedSubscriptions, err := s.GetRemovedSubscriptions(ctx, time.Now().Add(time.Second))
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(removedSubscriptions))
+			assert.Equal(t, uint(5002), removedSubscriptions[0].SourceID)
+			assert.True(t, removedSubscriptions[0].DeletedAt.Valid)
+			purgeID := removedSubscriptions[0].ID
+
+			removedSubscriptions, err = s.GetRemovedSubscriptions(ctx, removedAt.Add(-time.Minute))
+			assert.Nil(t, err)
+			assert.Empty(t, removedSubscriptions)
+
+			purged, err := s.PurgeSubscriptions(ctx, []uint{purgeID})
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), purged)
+			removedSubscriptions, err = s.GetRemovedSubscriptions(ctx, time.Now().Add(time.Second))
+			assert.Nil(t, err)
+			assert.Empty(t, removedSubscriptions)
+		},
+	)
+	t.Run(
+		"orphan sources", func(t *testing.T) {
+			sourceStorage := NewSourceStorageImpl(db)
+			assert.Nil(t, sourceStorage.Init(ctx))
+
+			subscribed := &model.Source{ID: 5101, Link: "http://subscribed.example.com"}
+			removed := &model.Source{ID: 5102, Link: "http://removed.example.com"}
+			orphan := &model.Source{ID: 5103, Link: "http://orphan.example.com"}
+			for _, source := range []*model.Source{subscribed, removed, orphan} {
+				assert.Nil(t, sourceStorage.AddSource(ctx, source))
+			}
+			assert.Nil(t, s.AddSubscription(ctx, &model.Subscribe{UserID: 5100, SourceID: subscribed.ID}))
+			assert.Nil(t, s.AddSubscription(ctx, &model.Subscribe{UserID: 5100, SourceID: removed.ID}))
+			_, err := s.RemoveSubscriptions(ctx, 5100, removed.ID, 1, time.Now())
+			assert.Nil(t, err)
+
+			sources, err := sourceStorage.GetOrphanSources(ctx, time.Now().Add(-time.Minute))
+			assert.Nil(t, err)
+			for _, source := range sources {
+				assert.NotEqual(t, orphan.ID, source.ID)
+			}
+
+			sources, err = sourceStorage.GetOrphanSources(ctx, time.Now().Add(time.Second))
+			assert.Nil(t, err)
+			var ids []uint
+			for _, source := range sources {
+				ids = append(ids, source.ID)
+			}
+			assert.Contains(t, ids, orphan.ID)
+			assert.NotContains(t, ids, subscribed.ID)
+			assert.NotContains(t, ids, removed.ID)
+		},
+	)
 }
@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/log"
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+type TagStorageImpl struct {
+	db *gorm.DB
+}
+
+func NewTagStorageImpl(db *gorm.DB) *TagStorageImpl {
+	return &TagStorageImpl{db: db}
+}
+
+func (s *TagStorageImpl) Init(ctx context.Context) error {
+	migrated := s.db.Migrator().HasTable(&model.SubscribeTag{})
+	if err := s.db.Migrator().AutoMigrate(&model.Tag{}, &model.SubscribeTag{}); err != nil {
+		return err
+	}
+	if migrated {
+		return nil
+	}
+	return s.migrateRenderedTags(ctx)
+}
+
+// migrateRenderedTags moves the tags of subscriptions created before the tags table into it
+func (s *TagStorageImpl) migrateRenderedTags(ctx context.Context) error {
+	var subscriptions []*model.Subscribe
+	if err := s.db.WithContext(ctx).Where("tag <> ''").Find(&subscriptions).Error; err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		tags := model.ParseTags(subscription.Tag)
+		if err := s.SetSubscriptionTags(ctx, subscription, tags); err != nil {
+			return err
+		}
+		result := s.db.WithContext(ctx).Model(subscription).Update("tag", model.FormatTags(tags))
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	log.Infof("migrated tags of %d subscriptions", len(subscriptions))
+	return nil
+}
+
+func (s *TagStorageImpl) SetSubscriptionTags(
+	ctx context.Context, subscription *model.Subscribe, names []string,
+) error {
+	return s.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where("subscribe_id = ?", subscription.ID).Delete(&model.SubscribeTag{}).Error; err != nil {
+				return err
+			}
+
+			for _, name := range names {
+				tag := &model.Tag{UserID: subscription.UserID, Name: name}
+				if err := tx.Where(tag).FirstOrCreate(tag).Error; err != nil {
+					return err
+				}
+				link := &model.SubscribeTag{SubscribeID: subscription.ID, TagID: tag.ID}
+				if err := tx.Create(link).Error; err != nil {
+					return err
+				}
+			}
+			return deleteUnusedTags(tx, subscription.UserID)
+		},
+	)
+}
+
+func (s *TagStorageImpl) DeleteSubscriptionTags(ctx context.Context, subscription *model.Subscribe) error {
+	return s.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Where("subscribe_id = ?", subscription.ID).Delete(&model.SubscribeTag{}).Error; err != nil {
+				return err
+			}
+			return deleteUnusedTags(tx, subscription.UserID)
+		},
+	)
+}
+
+func (s *TagStorageImpl) GetTag(ctx context.Context, userID int64, name string) (*model.Tag, error) {
+	return getTag(s.db.WithContext(ctx), userID, name)
+}
+
+func getTag(tx *gorm.DB, userID int64, name string) (*model.Tag, error) {
+	tag := &model.Tag{}
+	result := tx.Where("user_id = ? and name = ?", userID, name).First(tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		return nil, result.Error
+	}
+	return tag, nil
+}
+
+func (s *TagStorageImpl) GetTagCounts(ctx context.Context, userID int64) ([]*TagCount, error) {
+	var counts []*TagCount
+	result := s.db.WithContext(ctx).Model(&model.Tag{}).
+		Select("tags.name as name, count(subscribe_tags.subscribe_id) as count").
+		Joins("join subscribe_tags on subscribe_tags.tag_id = tags.id").
+		Joins("join subscribes on subscribes.id = subscribe_tags.subscribe_id and subscribes.deleted_at is null").
+		Where("tags.user_id = ?", userID).
+		Group("tags.id, tags.name").
+		Order("tags.name").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return counts, nil
+}
+
+func (s *TagStorageImpl) MoveTag(ctx context.Context, userID int64, from string, to string) (int64, error) {
+	var moved int64
+	err := s.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			fromTag, err := getTag(tx, userID, from)
+			if err != nil {
+				return err
+			}
+			subscribeIDs, err := taggedSubscriptionIDs(tx, fromTag.ID)
+			if err != nil {
+				return err
+			}
+			moved = int64(len(subscribeIDs))
+
+			toTag, err := getTag(tx, userID, to)
+			if errors.Is(err, ErrRecordNotFound) {
+				if err := tx.Model(fromTag).Update("name", to).Error; err != nil {
+					return err
+				}
+				return renameRenderedTag(tx, subscribeIDs, from, to)
+			}
+			if err != nil {
+				return err
+			}
+			if toTag.ID == fromTag.ID {
+				return nil
+			}
+
+			// subscriptions with both tags keep a single link to the target tag
+			toSubscribeIDs, err := taggedSubscriptionIDs(tx, toTag.ID)
+			if err != nil {
+				return err
+			}
+			linked := make(map[uint]bool, len(toSubscribeIDs))
+			for _, id := range toSubscribeIDs {
+				linked[id] = true
+			}
+			if err := tx.Where("tag_id = ?", fromTag.ID).Delete(&model.SubscribeTag{}).Error; err != nil {
+				return err
+			}
+			for _, id := range subscribeIDs {
+				if linked[id] {
+					continue
+				}
+				if err := tx.Create(&model.SubscribeTag{SubscribeID: id, TagID: toTag.ID}).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Delete(fromTag).Error; err != nil {
+				return err
+			}
+			return renameRenderedTag(tx, subscribeIDs, from, to)
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+func (s *TagStorageImpl) DeleteTag(ctx context.Context, userID int64, name string) (int64, error) {
+	var deleted int64
+	err := s.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			tag, err := getTag(tx, userID, name)
+			if err != nil {
+				return err
+			}
+			subscribeIDs, err := taggedSubscriptionIDs(tx, tag.ID)
+			if err != nil {
+				return err
+			}
+			deleted = int64(len(subscribeIDs))
+
+			if err := tx.Where("tag_id = ?", tag.ID).Delete(&model.SubscribeTag{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(tag).Error; err != nil {
+				return err
+			}
+			return renameRenderedTag(tx, subscribeIDs, name, "")
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func taggedSubscriptionIDs(tx *gorm.DB, tagID uint) ([]uint, error) {
+	var subscribeIDs []uint
+	result := tx.Model(&model.SubscribeTag{}).Where("tag_id = ?", tagID).Pluck("subscribe_id", &subscribeIDs)
+	return subscribeIDs, result.Error
+}
+
+// renameRenderedTag replaces a tag in the rendered tags of the subscriptions keeping their order, to empty removes it
+func renameRenderedTag(tx *gorm.DB, subscribeIDs []uint, from string, to string) error {
+	if len(subscribeIDs) == 0 {
+		return nil
+	}
+
+	// the removed subscriptions are renamed too, they come back with the tags when undone
+	var subscriptions []*model.Subscribe
+	if err := tx.Unscoped().Where("id in ?", subscribeIDs).Find(&subscriptions).Error; err != nil {
+		return err
+	}
+	for _, subscription := range subscriptions {
+		tags := model.ParseTags(subscription.Tag)
+		for i := range tags {
+			if tags[i] == from {
+				tags[i] = to
+			}
+		}
+		rendered := model.FormatTags(model.NormalizeTags(tags))
+		if err := tx.Unscoped().Model(subscription).Update("tag", rendered).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUnusedTags deletes the tags of a chat no subscription is linked to anymore
+func deleteUnusedTags(tx *gorm.DB, userID int64) error {
+	return tx.Where(
+		"user_id = ? and id not in (select tag_id from subscribe_tags)", userID,
+	).Delete(&model.Tag{}).Error
+}
+
+// moveChatTags moves the tags of a chat to another chat, merged into the tags of the same name the other chat has
+func moveChatTags(tx *gorm.DB, fromUserID int64, toUserID int64) error {
+	var tags []*model.Tag
+	if err := tx.Where("user_id = ?", fromUserID).Find(&tags).Error; err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		existing, err := getTag(tx, toUserID, tag.Name)
+		if err == ErrRecordNotFound {
+			if err := tx.Model(tag).Update("user_id", toUserID).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.SubscribeTag{}).Where("tag_id = ?", tag.ID).
+			Update("tag_id", existing.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(tag).Error; err != nil {
+			return err
+		}
+	}
+	// the tags of the subscriptions dropped in favour of the other chat's
+	return deleteUnusedTags(tx, toUserID)
+}
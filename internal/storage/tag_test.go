@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/andatoshiki/toshiki-rssbot/internal/model"
+)
+
+func TestTagStorageImpl(t *testing.T) {
+	db := GetTestDB(t)
+	subscriptionStorage := NewSubscriptionStorageImpl(db)
+	s := NewTagStorageImpl(db)
+	ctx := context.Background()
+	subscriptionStorage.Init(ctx)
+	s.Init(ctx)
+
+	countTags := func(userID int64) int64 {
+		var count int64
+		db.Model(&model.Tag{}).Where("user_id = ?", userID).Count(&count)
+		return count
+	}
+	tagged := func(userID int64, tag string) int {
+		result, err := subscriptionStorage.GetSubscriptionsByUserID(
+			ctx, userID, &GetSubscriptionsOptions{Count: -1, Tag: tag},
+		)
+		assert.Nil(t, err)
+		return len(result.Subscriptions)
+	}
+
+	subs := []*model.Subscribe{
+		{SourceID: 3001, UserID: 3000},
+		{SourceID: 3002, UserID: 3000},
+	}
+	for _, sub := range subs {
+		err := subscriptionStorage.AddSubscription(ctx, sub)
+		assert.Nil(t, err)
+	}
+
+	t.Run(
+		"set subscription tags", func(t *testing.T) {
+			err := s.SetSubscriptionTags(ctx, subs[0], []string{"go", "news"})
+			assert.Nil(t, err)
+			err = s.SetSubscriptionTags(ctx, subs[1], []string{"news"})
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), countTags(3000))
+			assert.Equal(t, 2, tagged(3000, "#news"))
+			assert.Equal(t, 1, tagged(3000, "go"))
+
+			err = s.SetSubscriptionTags(ctx, subs[0], []string{"go"})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, tagged(3000, "news"))
+			assert.Equal(t, 0, tagged(3001, "news"))
+		},
+	)
+
+	t.Run(
+		"delete subscription tags", func(t *testing.T) {
+			err := s.DeleteSubscriptionTags(ctx, subs[0])
+			assert.Nil(t, err)
+			assert.Equal(t, 0, tagged(3000, "go"))
+			assert.Equal(t, int64(1), countTags(3000))
+		},
+	)
+
+	t.Run(
+		"migrate rendered tags", func(t *testing.T) {
+			sub := &model.Subscribe{SourceID: 3003, UserID: 3000, Tag: "#Go #go #News"}
+			err := subscriptionStorage.AddSubscription(ctx, sub)
+			assert.Nil(t, err)
+
+			err = db.Migrator().DropTable(&model.SubscribeTag{})
+			assert.Nil(t, err)
+			err = s.Init(ctx)
+			assert.Nil(t, err)
+
+			assert.Equal(t, 1, tagged(3000, "go"))
+			subscription, err := subscriptionStorage.GetSubscription(ctx, 3000, 3003)
+			assert.Nil(t, err)
+			assert.Equal(t, "#go #news", subscription.Tag)
+		},
+	)
+
+	t.Run(
+		"get tag counts", func(t *testing.T) {
+			counts, err := s.GetTagCounts(ctx, 3000)
+			assert.Nil(t, err)
+			assert.Equal(t, []*TagCount{{Name: "go", Count: 1}, {Name: "news", Count: 1}}, counts)
+
+			_, err = s.GetTag(ctx, 3000, "rust")
+			assert.ErrorIs(t, err, ErrRecordNotFound)
+		},
+	)
+
+	t.Run(
+		"rename tag", func(t *testing.T) {
+			moved, err := s.MoveTag(ctx, 3000, "go", "golang")
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), moved)
+			assert.Equal(t, 0, tagged(3000, "go"))
+			assert.Equal(t, 1, tagged(3000, "golang"))
+
+			subscription, err := subscriptionStorage.GetSubscription(ctx, 3000, 3003)
+			assert.Nil(t, err)
+			assert.Equal(t, "#golang #news", subscription.Tag)
+
+			_, err = s.MoveTag(ctx, 3000, "go", "golang")
+			assert.ErrorIs(t, err, ErrRecordNotFound)
+		},
+	)
+
+	t.Run(
+		"merge tag", func(t *testing.T) {
+			moved, err := s.MoveTag(ctx, 3000, "news", "golang")
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), moved)
+			assert.Equal(t, 1, tagged(3000, "golang"))
+			assert.Equal(t, int64(1), countTags(3000))
+
+			subscription, err := subscriptionStorage.GetSubscription(ctx, 3000, 3003)
+			assert.Nil(t, err)
+			assert.Equal(t, "#golang", subscription.Tag)
+		},
+	)
+
+	t.Run(
+		"delete tag", func(t *testing.T) {
+			deleted, err := s.DeleteTag(ctx, 3000, "golang")
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), deleted)
+			assert.Equal(t, int64(0), countTags(3000))
+
+			subscription, err := subscriptionStorage.GetSubscription(ctx, 3000, 3003)
+			assert.Nil(t, err)
+			assert.Equal(t, "", subscription.Tag)
+		},
+	)
+
+	t.Run(
+		"move chat tags", func(t *testing.T) {
+			sub := &model.Subscribe{SourceID: 3004, UserID: 3000}
+			assert.Nil(t, subscriptionStorage.AddSubscription(ctx, sub))
+			assert.Nil(t, s.SetSubscriptionTags(ctx, sub, []string{"rust"}))
+
+			err := moveChatTags(s.db.WithContext(ctx), 3000, -1003000)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(0), countTags(3000))
+			assert.Equal(t, int64(1), countTags(-1003000))
+		},
+	)
+}
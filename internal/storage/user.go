@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/andatoshiki/toshiki-rssbot/internal/model"
 )
@@ -13,20 +15,15 @@ type UserStorageImpl struct {
 	db *gorm.DB
 }
 
-// CreateUser implements User.
-func (*UserStorageImpl) CreateUser(ctx context.Context, user *model.User) error {
-	panic("unimplemented")
-}
-
 func NewUserStorageImpl(db *gorm.DB) *UserStorageImpl {
 	return &UserStorageImpl{db: db}
 }
 
 func (s *UserStorageImpl) Init(ctx context.Context) error {
-	return s.db.Migrator().AutoMigrate(&model.User{})
+	return s.db.Migrator().AutoMigrate(&model.User{}, &model.Invite{})
 }
 
-func (s *UserStorageImpl) CrateUser(ctx context.Context, user *model.User) error {
+func (s *UserStorageImpl) CreateUser(ctx context.Context, user *model.User) error {
 	result := s.db.WithContext(ctx).Create(user)
 	if result.Error != nil {
 		return result.Error
@@ -45,3 +42,57 @@ func (s *UserStorageImpl) GetUser(ctx context.Context, id int64) (*model.User, e
 	}
 	return user, nil
 }
+
+func (s *UserStorageImpl) UpsertUser(ctx context.Context, user *model.User) error {
+	return s.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"username", "name", "language_code", "updated_at"}),
+		},
+	).Create(user).Error
+}
+
+func (s *UserStorageImpl) SetUserAllowed(ctx context.Context, id int64, allowed bool) error {
+	return setUserAllowed(s.db.WithContext(ctx), id, allowed)
+}
+
+func setUserAllowed(tx *gorm.DB, id int64, allowed bool) error {
+	user := &model.User{ID: id, Allowed: allowed}
+	return tx.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"allowed", "updated_at"}),
+		},
+	).Create(user).Error
+}
+
+func (s *UserStorageImpl) GetAllowedUsers(ctx context.Context) ([]*model.User, error) {
+	var users []*model.User
+	result := s.db.WithContext(ctx).Where("allowed = ?", true).Order("id").Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+func (s *UserStorageImpl) AddInvite(ctx context.Context, invite *model.Invite) error {
+	return s.db.WithContext(ctx).Create(invite).Error
+}
+
+func (s *UserStorageImpl) UseInvite(ctx context.Context, code string, userID int64) error {
+	return s.db.WithContext(ctx).Transaction(
+		func(tx *gorm.DB) error {
+			now := time.Now()
+			result := tx.Model(&model.Invite{}).Where("code = ? and used_by = 0", code).Updates(
+				map[string]interface{}{"used_by": userID, "used_at": &now},
+			)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrRecordNotFound
+			}
+			return setUserAllowed(tx, userID, true)
+		},
+	)
+}
@@ -31,7 +31,7 @@ func TestUserStorageImpl(t *testing.T) {
 
 	t.Run(
 		"save user", func(t *testing.T) {
-			err := s.CrateUser(ctx, user)
+			err := s.CreateUser(ctx, user)
 			assert.Nil(t, err)
 		},
 	)
@@ -44,4 +44,51 @@ func TestUserStorageImpl(t *testing.T) {
 			assert.Equal(t, user.ID, got.ID)
 		},
 	)
+	t.Run(
+		"set user allowed", func(t *testing.T) {
+			err := s.SetUserAllowed(ctx, user.ID, true)
+			assert.Nil(t, err)
+			err = s.SetUserAllowed(ctx, 124, true)
+			assert.Nil(t, err)
+
+			users, err := s.GetAllowedUsers(ctx)
+			assert.Nil(t, err)
+			assert.Len(t, users, 2)
+
+			err = s.SetUserAllowed(ctx, 124, false)
+			assert.Nil(t, err)
+			users, err = s.GetAllowedUsers(ctx)
+			assert.Nil(t, err)
+			assert.Len(t, users, 1)
+			assert.Equal(t, user.ID, users[0].ID)
+		},
+	)
+
+	t.Run(
+		"use invite", func(t *testing.T) {
+			err := s.AddInvite(ctx, &model.Invite{Code: "code", CreatedBy: user.ID})
+			assert.Nil(t, err)
+
+			err = s.UseInvite(ctx, "code", 125)
+			assert.Nil(t, err)
+			got, err := s.GetUser(ctx, 125)
+			assert.Nil(t, err)
+			assert.True(t, got.Allowed)
+
+			err = s.UseInvite(ctx, "code", 126)
+			assert.ErrorIs(t, err, ErrRecordNotFound)
+			err = s.UseInvite(ctx, "unknown", 126)
+			assert.ErrorIs(t, err, ErrRecordNotFound)
+		},
+	)
+	t.Run(
+		"upsert user", func(t *testing.T) {
+			err := s.UpsertUser(ctx, &model.User{ID: user.ID, Username: "user", LanguageCode: "ja"})
+			assert.Nil(t, err)
+			got, err := s.GetUser(ctx, user.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, "ja", got.LanguageCode)
+			assert.True(t, got.Allowed)
+		},
+	)
 }
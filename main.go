@@ -16,20 +16,24 @@ func main() {
 	if err := appCore.Init(); err != nil {
 		log.Fatal(err)
 	}
-	go handleSignal()
 	b := bot.NewBot(appCore)
+	go handleSignal(b)
 
 	task := scheduler.NewRssTask(appCore)
 	task.Register(b)
 	task.Start()
-	b.Run()
+	scheduler.NewPurgeTask(appCore).Start()
+	if err := b.Run(); err != nil {
+		log.Fatal(err)
+	}
 }
 
-func handleSignal() {
-	c := make(chan os.Signal)
+func handleSignal(b *bot.Bot) {
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, os.Kill, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
 
 	<-c
 
+	b.Stop()
 	os.Exit(0)
 }